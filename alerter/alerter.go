@@ -4,12 +4,14 @@ package alerter
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/NaveLIL/erez-monitor/config"
 	"github.com/NaveLIL/erez-monitor/logger"
 	"github.com/NaveLIL/erez-monitor/models"
+	"github.com/NaveLIL/erez-monitor/storage"
 )
 
 // AlertHandler is a function that handles an alert.
@@ -18,6 +20,9 @@ type AlertHandler func(alert *models.Alert)
 // Alerter monitors metrics and triggers alerts when thresholds are exceeded.
 type Alerter struct {
 	config     *config.AlertsConfig
+	watchlist  []config.WatchedProcessConfig
+	sensors    []config.SensorConfig
+	metrics    *storage.RingBuffer // metrics history for spike attribution
 	log        *logger.Logger
 	handlers   []AlertHandler
 	handlersMu sync.RWMutex
@@ -95,7 +100,7 @@ func (a *Alerter) Check(metrics *models.Metrics) {
 
 	// Check CPU threshold
 	if metrics.CPU.UsagePercent >= a.config.CPUThreshold {
-		a.triggerAlert("cpu", models.AlertTypeCPU,
+		a.triggerAttributedAlert("cpu", models.AlertTypeCPU, resourceCPU,
 			fmt.Sprintf("CPU usage is %.1f%% (threshold: %.1f%%)",
 				metrics.CPU.UsagePercent, a.config.CPUThreshold),
 			metrics.CPU.UsagePercent,
@@ -106,7 +111,7 @@ func (a *Alerter) Check(metrics *models.Metrics) {
 
 	// Check RAM threshold
 	if metrics.Memory.UsedPercent >= a.config.RAMThreshold {
-		a.triggerAlert("ram", models.AlertTypeRAM,
+		a.triggerAttributedAlert("ram", models.AlertTypeRAM, resourceMemory,
 			fmt.Sprintf("RAM usage is %.1f%% (threshold: %.1f%%)",
 				metrics.Memory.UsedPercent, a.config.RAMThreshold),
 			metrics.Memory.UsedPercent,
@@ -118,7 +123,7 @@ func (a *Alerter) Check(metrics *models.Metrics) {
 	// Check GPU threshold (if available)
 	if metrics.GPU.Available {
 		if metrics.GPU.UsagePercent >= a.config.GPUThreshold {
-			a.triggerAlert("gpu", models.AlertTypeGPU,
+			a.triggerAttributedAlert("gpu", models.AlertTypeGPU, resourceGPU,
 				fmt.Sprintf("GPU usage is %.1f%% (threshold: %.1f%%)",
 					metrics.GPU.UsagePercent, a.config.GPUThreshold),
 				metrics.GPU.UsagePercent,
@@ -143,6 +148,7 @@ func (a *Alerter) Check(metrics *models.Metrics) {
 	for _, disk := range metrics.Disk.Disks {
 		alertKey := "disk_" + disk.Path
 		if disk.UsedPercent >= a.config.DiskThreshold {
+			// Capacity is not caused by current activity, so no attribution
 			a.triggerAlert(alertKey, models.AlertTypeDisk,
 				fmt.Sprintf("Disk %s usage is %.1f%% (threshold: %.1f%%)",
 					disk.Path, disk.UsedPercent, a.config.DiskThreshold),
@@ -152,6 +158,354 @@ func (a *Alerter) Check(metrics *models.Metrics) {
 			a.clearActiveAlert(alertKey)
 		}
 	}
+
+	// Check network latency, jitter and packet loss per ping target
+	a.checkNetwork(metrics)
+
+	// Check watched process thresholds
+	a.checkWatchedProcesses(metrics)
+
+	// Check battery level
+	a.checkBattery(metrics)
+
+	// Check hardware sensor thresholds
+	a.checkSensors(metrics)
+
+	// Check throttling
+	a.checkThrottling(metrics)
+
+	// Check cgroup memory limits
+	a.checkCgroups(metrics)
+
+	// Check Wi-Fi signal strength
+	a.checkWireless(metrics)
+
+	// Check processes with steadily growing memory
+	a.checkMemoryLeaks(metrics)
+}
+
+// memoryLeakPrefix starts the active alert keys of memory leaks.
+const memoryLeakPrefix = "memory_leak_"
+
+// checkMemoryLeaks alerts once per process flagged by the leak detector and
+// re-arms when the process is no longer flagged.
+func (a *Alerter) checkMemoryLeaks(metrics *models.Metrics) {
+	flagged := make(map[string]bool, len(metrics.MemoryLeaks))
+	for _, leak := range metrics.MemoryLeaks {
+		key := fmt.Sprintf("%s%d_%s", memoryLeakPrefix, leak.PID, leak.Name)
+		flagged[key] = true
+
+		message := fmt.Sprintf("Possible memory leak: %s (PID %d) grows %.0f MB/h for %s, now %d MB",
+			leak.Name, leak.PID, leak.GrowthMBPerHour,
+			metrics.Timestamp.Sub(leak.Since).Round(time.Minute), leak.MemoryMB)
+		if leak.ExhaustInSec > 0 {
+			message += fmt.Sprintf("; RAM exhausted in ~%s",
+				(time.Duration(leak.ExhaustInSec) * time.Second).Round(time.Minute))
+		}
+		a.triggerAlert(key, models.AlertTypeMemoryLeak, message, leak.GrowthMBPerHour, 0)
+	}
+
+	a.activeMu.Lock()
+	defer a.activeMu.Unlock()
+	for key := range a.activeAlerts {
+		if strings.HasPrefix(key, memoryLeakPrefix) && !flagged[key] {
+			delete(a.activeAlerts, key)
+		}
+	}
+}
+
+// checkWireless alerts when a connected Wi-Fi link has a weak signal.
+func (a *Alerter) checkWireless(metrics *models.Metrics) {
+	threshold := a.config.WifiSignalDBm
+	for _, iface := range metrics.Network.Interfaces {
+		w := iface.Wireless
+		if w == nil {
+			continue
+		}
+		key := "wifi_signal_" + iface.Name
+		if threshold != 0 && w.Connected && w.SignalDBm != 0 && w.SignalDBm <= threshold {
+			network := iface.Name
+			if w.SSID != "" {
+				network = fmt.Sprintf("%s (%s)", w.SSID, iface.Name)
+			}
+			a.triggerAlert(key, models.AlertTypeWireless,
+				fmt.Sprintf("Weak Wi-Fi signal on %s: %.0f dBm, link quality %.0f%%",
+					network, w.SignalDBm, w.LinkQuality),
+				w.SignalDBm,
+				threshold)
+		} else {
+			a.clearActiveAlert(key)
+		}
+	}
+}
+
+// checkCgroups alerts when a cgroup nears its memory limit.
+func (a *Alerter) checkCgroups(metrics *models.Metrics) {
+	threshold := a.config.CgroupMemoryPercent
+	for _, cg := range metrics.Cgroups {
+		key := "cgroup_memory_" + cg.Path
+		if threshold > 0 && cg.MemoryMaxMB > 0 && cg.MemoryPercent >= threshold {
+			a.triggerAlert(key, models.AlertTypeCgroup,
+				fmt.Sprintf("%s memory at %.1f%% of limit (%d/%d MB)",
+					cg.Name, cg.MemoryPercent, cg.MemoryCurrentMB, cg.MemoryMaxMB),
+				cg.MemoryPercent,
+				threshold)
+		} else {
+			a.clearActiveAlert(key)
+		}
+	}
+}
+
+// checkThrottling alerts when throttling lasts at least the configured duration.
+func (a *Alerter) checkThrottling(metrics *models.Metrics) {
+	state := metrics.Throttling
+	if state.Throttled && state.DurationSec >= a.config.ThrottlingMinDuration.Seconds() {
+		message := fmt.Sprintf("Throttling detected (%s)", strings.Join(state.Reasons, ", "))
+		if state.FrequencyRatio > 0 {
+			message += fmt.Sprintf(", CPU at %.0f%% of max frequency", state.FrequencyRatio*100)
+		}
+		a.triggerAlert("throttling", models.AlertTypeThrottling, message,
+			state.DurationSec,
+			a.config.ThrottlingMinDuration.Seconds())
+	} else if !state.Throttled {
+		a.clearActiveAlert("throttling")
+	}
+}
+
+// checkSensors checks hardware sensors against their configured thresholds.
+func (a *Alerter) checkSensors(metrics *models.Metrics) {
+	a.mu.RLock()
+	thresholds := make(map[string]config.SensorConfig, len(a.sensors))
+	for _, s := range a.sensors {
+		thresholds[s.ID] = s
+	}
+	a.mu.RUnlock()
+
+	for _, r := range metrics.Sensors {
+		cfg, ok := thresholds[r.ID]
+		if !ok {
+			continue
+		}
+
+		highKey := "sensor_high_" + r.ID
+		if cfg.Max > 0 && r.Value >= cfg.Max {
+			a.triggerAlert(highKey, models.AlertTypeSensor,
+				fmt.Sprintf("Sensor %s is %.1f %s (threshold: %.1f %s)",
+					r.Name, r.Value, r.Unit, cfg.Max, r.Unit),
+				r.Value,
+				cfg.Max)
+		} else {
+			a.clearActiveAlert(highKey)
+		}
+
+		lowKey := "sensor_low_" + r.ID
+		if cfg.Min > 0 && r.Value <= cfg.Min {
+			a.triggerAlert(lowKey, models.AlertTypeSensor,
+				fmt.Sprintf("Sensor %s dropped to %.1f %s (minimum: %.1f %s)",
+					r.Name, r.Value, r.Unit, cfg.Min, r.Unit),
+				r.Value,
+				cfg.Min)
+		} else {
+			a.clearActiveAlert(lowKey)
+		}
+	}
+}
+
+// checkBattery checks the battery level while running on battery.
+func (a *Alerter) checkBattery(metrics *models.Metrics) {
+	power := metrics.Power
+	if a.config.BatteryLowPercent > 0 && power.OnBattery() && power.BatteryPercent <= a.config.BatteryLowPercent {
+		message := fmt.Sprintf("Battery low: %.0f%% (threshold: %.0f%%)",
+			power.BatteryPercent, a.config.BatteryLowPercent)
+		if power.TimeToEmptyMin > 0 {
+			message += fmt.Sprintf(", about %.0f min left", power.TimeToEmptyMin)
+		}
+		a.triggerAlert("battery_low", models.AlertTypeBattery, message,
+			power.BatteryPercent,
+			a.config.BatteryLowPercent)
+	} else {
+		a.clearActiveAlert("battery_low")
+	}
+}
+
+// minLossSamples is the number of probes required before packet loss is alerted on.
+const minLossSamples = 5
+
+// checkNetwork checks ping target statistics against network thresholds.
+func (a *Alerter) checkNetwork(metrics *models.Metrics) {
+	for _, target := range metrics.Network.PingTargets {
+		latencyKey := "net_latency_" + target.Name
+		if a.config.PingThresholdMs > 0 && target.P50Ms >= a.config.PingThresholdMs {
+			a.triggerAlert(latencyKey, models.AlertTypeNetwork,
+				fmt.Sprintf("Latency to %s is %.0f ms (threshold: %.0f ms)",
+					target.Name, target.P50Ms, a.config.PingThresholdMs),
+				target.P50Ms,
+				a.config.PingThresholdMs)
+		} else {
+			a.clearActiveAlert(latencyKey)
+		}
+
+		jitterKey := "net_jitter_" + target.Name
+		if a.config.JitterThresholdMs > 0 && target.JitterMs >= a.config.JitterThresholdMs {
+			a.triggerAlert(jitterKey, models.AlertTypeNetwork,
+				fmt.Sprintf("Jitter to %s is %.1f ms (threshold: %.1f ms)",
+					target.Name, target.JitterMs, a.config.JitterThresholdMs),
+				target.JitterMs,
+				a.config.JitterThresholdMs)
+		} else {
+			a.clearActiveAlert(jitterKey)
+		}
+
+		lossKey := "net_loss_" + target.Name
+		if a.config.PacketLossThreshold > 0 && target.Samples >= minLossSamples &&
+			target.LossPercent >= a.config.PacketLossThreshold {
+			a.triggerAlert(lossKey, models.AlertTypeNetwork,
+				fmt.Sprintf("Packet loss to %s is %.0f%% (threshold: %.0f%%)",
+					target.Name, target.LossPercent, a.config.PacketLossThreshold),
+				target.LossPercent,
+				a.config.PacketLossThreshold)
+		} else {
+			a.clearActiveAlert(lossKey)
+		}
+
+		nxdomainKey := "dns_nxdomain_" + target.Name
+		if target.Protocol == "dns" && target.Status == "nxdomain" {
+			a.triggerAlert(nxdomainKey, models.AlertTypeNetwork,
+				fmt.Sprintf("DNS name %s of %s does not exist (NXDOMAIN in %d of %d probes)",
+					target.Host, target.Name, target.NXDomainCount, target.Samples),
+				float64(target.NXDomainCount),
+				0)
+		} else {
+			a.clearActiveAlert(nxdomainKey)
+		}
+
+		if target.Protocol == "http" {
+			a.checkHealthCheck(target)
+		}
+	}
+}
+
+// checkHealthCheck checks an http target for a failed check and an
+// upcoming certificate expiry.
+func (a *Alerter) checkHealthCheck(target models.PingTargetStats) {
+	downKey := "http_down_" + target.Name
+	if !target.Available {
+		var status float64
+		if target.HTTP != nil {
+			status = float64(target.HTTP.StatusCode)
+		}
+		a.triggerAlert(downKey, models.AlertTypeNetwork,
+			fmt.Sprintf("Health check %s failed: %s", target.Name, target.Error),
+			status,
+			0)
+	} else {
+		a.clearActiveAlert(downKey)
+	}
+
+	certKey := "cert_expiry_" + target.Name
+	days := float64(a.config.CertExpiryDays)
+	if a.config.CertExpiryDays > 0 && target.HTTP.HasCert() && target.HTTP.CertDaysLeft <= days {
+		a.triggerAlert(certKey, models.AlertTypeNetwork,
+			fmt.Sprintf("Certificate of %s expires in %.0f days (%s)",
+				target.Name, target.HTTP.CertDaysLeft, target.HTTP.CertExpiry.Format("2006-01-02")),
+			target.HTTP.CertDaysLeft,
+			days)
+	} else {
+		a.clearActiveAlert(certKey)
+	}
+}
+
+// checkWatchedProcesses checks watched processes against their own thresholds.
+func (a *Alerter) checkWatchedProcesses(metrics *models.Metrics) {
+	a.mu.RLock()
+	thresholds := make(map[string]config.WatchedProcessConfig, len(a.watchlist))
+	for _, w := range a.watchlist {
+		thresholds[w.ID] = w
+	}
+	a.mu.RUnlock()
+
+	for _, w := range metrics.WatchedProcesses {
+		cfg, ok := thresholds[w.ID]
+		if !ok {
+			continue
+		}
+
+		cpuKey := "process_cpu_" + w.ID
+		if cfg.CPUThreshold > 0 && w.Running && w.CPUPercent >= cfg.CPUThreshold {
+			a.triggerAlert(cpuKey, models.AlertTypeProcess,
+				fmt.Sprintf("Process %s CPU usage is %.1f%% (threshold: %.1f%%)",
+					w.ID, w.CPUPercent, cfg.CPUThreshold),
+				w.CPUPercent,
+				cfg.CPUThreshold)
+		} else {
+			a.clearActiveAlert(cpuKey)
+		}
+
+		memKey := "process_mem_" + w.ID
+		if cfg.MemoryThresholdMB > 0 && w.Running && w.MemoryMB >= cfg.MemoryThresholdMB {
+			a.triggerAlert(memKey, models.AlertTypeProcess,
+				fmt.Sprintf("Process %s memory usage is %d MB (threshold: %d MB)",
+					w.ID, w.MemoryMB, cfg.MemoryThresholdMB),
+				float64(w.MemoryMB),
+				float64(cfg.MemoryThresholdMB))
+		} else {
+			a.clearActiveAlert(memKey)
+		}
+	}
+}
+
+// CheckProcessEvent raises an alert when a watched process with alert_on_exit exits.
+// A start of the same watched process re-arms the alert.
+func (a *Alerter) CheckProcessEvent(event *models.ProcessEvent) {
+	a.mu.RLock()
+	if !a.running || !a.config.Enabled {
+		a.mu.RUnlock()
+		return
+	}
+	alertOnExit := make(map[string]bool, len(a.watchlist))
+	for _, w := range a.watchlist {
+		alertOnExit[w.ID] = w.AlertOnExit
+	}
+	a.mu.RUnlock()
+
+	for _, id := range event.WatchIDs {
+		if !alertOnExit[id] {
+			continue
+		}
+
+		key := "process_exit_" + id
+		if event.Type == models.ProcessEventStart {
+			a.clearActiveAlert(key)
+			continue
+		}
+
+		exited := "exited"
+		if event.ExitCode != nil {
+			exited = fmt.Sprintf("exited with code %d", *event.ExitCode)
+			if event.Crashed {
+				exited = fmt.Sprintf("crashed (0x%08X)", *event.ExitCode)
+			}
+		}
+		a.triggerAlert(key, models.AlertTypeProcess,
+			fmt.Sprintf("Process %s (%s, PID %d) %s after %s, peak memory %d MB",
+				id, event.Name, event.PID, exited, event.Runtime.Round(time.Second), event.PeakMemoryMB),
+			float64(event.PeakMemoryMB),
+			0)
+	}
+}
+
+// HasActiveAlerts returns true while any threshold alert condition persists.
+// Process exit alerts stay armed until the process restarts and memory
+// leaks develop over hours, so neither is counted.
+func (a *Alerter) HasActiveAlerts() bool {
+	a.activeMu.Lock()
+	defer a.activeMu.Unlock()
+	for key := range a.activeAlerts {
+		if !strings.HasPrefix(key, "process_exit_") && !strings.HasPrefix(key, memoryLeakPrefix) {
+			return true
+		}
+	}
+	return false
 }
 
 // clearActiveAlert clears an active alert when condition is resolved.
@@ -163,6 +517,12 @@ func (a *Alerter) clearActiveAlert(key string) {
 
 // triggerAlert creates and dispatches an alert if not already active.
 func (a *Alerter) triggerAlert(key string, alertType models.AlertType, message string, value, threshold float64) {
+	a.triggerAttributedAlert(key, alertType, resourceNone, message, value, threshold)
+}
+
+// triggerAttributedAlert triggers an alert and, when attribution is enabled,
+// attaches the processes whose usage of the resource rose the most.
+func (a *Alerter) triggerAttributedAlert(key string, alertType models.AlertType, r resource, message string, value, threshold float64) {
 	// Check if alert is already active (don't repeat)
 	a.activeMu.Lock()
 	if a.activeAlerts[key] {
@@ -180,6 +540,9 @@ func (a *Alerter) triggerAlert(key string, alertType models.AlertType, message s
 		Value:     value,
 		Threshold: threshold,
 	}
+	if r != resourceNone {
+		a.attribute(alert, r)
+	}
 
 	// Add to history
 	a.historyMu.Lock()
@@ -209,6 +572,25 @@ func (a *Alerter) triggerAlert(key string, alertType models.AlertType, message s
 	}
 }
 
+// attribute attaches the top contributors of the resource spike to the
+// alert and its message.
+func (a *Alerter) attribute(alert *models.Alert, r resource) {
+	a.mu.RLock()
+	history := a.metrics
+	a.mu.RUnlock()
+
+	cfg := a.config.Attribution
+	if history == nil || !cfg.Enabled {
+		return
+	}
+
+	snapshots := history.GetWindow(cfg.Window + cfg.Baseline)
+	alert.Contributors = attributeSpike(r, snapshots, cfg.Window, cfg.Count)
+	if len(alert.Contributors) > 0 {
+		alert.Message += formatContributors(r, alert.Contributors)
+	}
+}
+
 // playAlertSound plays the system alert sound.
 func (a *Alerter) playAlertSound() {
 	// Windows API call to play system sound
@@ -291,6 +673,28 @@ func (a *Alerter) UpdateConfig(cfg *config.AlertsConfig) {
 	a.config = cfg
 }
 
+// SetWatchlist sets the watched processes whose thresholds are checked.
+func (a *Alerter) SetWatchlist(watchlist []config.WatchedProcessConfig) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.watchlist = watchlist
+}
+
+// SetMetricsHistory sets the metrics history the processes behind CPU,
+// RAM and GPU alerts are attributed from.
+func (a *Alerter) SetMetricsHistory(history *storage.RingBuffer) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.metrics = history
+}
+
+// SetSensors sets the hardware sensors whose thresholds are checked.
+func (a *Alerter) SetSensors(sensors []config.SensorConfig) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.sensors = sensors
+}
+
 // IsEnabled returns whether alerts are enabled.
 func (a *Alerter) IsEnabled() bool {
 	a.mu.RLock()
@@ -0,0 +1,45 @@
+package alerter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+func newTestAlerter(cfg *config.AlertsConfig) *Alerter {
+	cfg.Enabled = true
+	a := New(cfg)
+	a.running = true
+	return a
+}
+
+func TestCheckProcessEventRestart(t *testing.T) {
+	a := newTestAlerter(&config.AlertsConfig{})
+	a.SetWatchlist([]config.WatchedProcessConfig{{ID: "game", Name: "game.exe", AlertOnExit: true}})
+
+	now := time.Now()
+	event := func(eventType models.ProcessEventType, pid int32) *models.ProcessEvent {
+		return &models.ProcessEvent{Type: eventType, Timestamp: now, Name: "game.exe", PID: pid, WatchIDs: []string{"game"}}
+	}
+
+	a.CheckProcessEvent(event(models.ProcessEventExit, 10))
+	if alerts := a.GetHistory(); len(alerts) != 1 || !strings.Contains(alerts[0].Message, "PID 10") {
+		t.Fatalf("Expected an exit alert, got %+v", alerts)
+	}
+
+	// Exits repeat only after the process has started again
+	a.CheckProcessEvent(event(models.ProcessEventExit, 10))
+	if alerts := a.GetHistory(); len(alerts) != 1 {
+		t.Fatalf("Expected the exit alert not to repeat, got %d alerts", len(alerts))
+	}
+
+	a.CheckProcessEvent(event(models.ProcessEventStart, 11))
+	a.CheckProcessEvent(event(models.ProcessEventExit, 11))
+	alerts := a.GetHistory()
+	if len(alerts) != 2 || !strings.Contains(alerts[1].Message, "PID 11") {
+		t.Fatalf("Expected a second exit alert after the restart, got %+v", alerts)
+	}
+}
@@ -0,0 +1,164 @@
+package alerter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// resource selects the per-process usage a spike is attributed by.
+type resource int
+
+const (
+	resourceNone resource = iota
+	resourceCPU
+	resourceMemory
+	resourceGPU
+)
+
+// processes returns the ranked process list of a snapshot for the resource.
+func (r resource) processes(m *models.Metrics) []models.ProcessInfo {
+	switch r {
+	case resourceCPU:
+		return m.TopProcesses
+	case resourceMemory:
+		return m.TopMemoryProcesses
+	case resourceGPU:
+		return m.TopGPUProcesses
+	}
+	return nil
+}
+
+// value returns the usage of a process for the resource.
+func (r resource) value(p *models.ProcessInfo) float64 {
+	switch r {
+	case resourceCPU:
+		return p.CPUPercent
+	case resourceMemory:
+		return float64(p.MemoryMB)
+	case resourceGPU:
+		return p.GPUPercent
+	}
+	return 0
+}
+
+// format formats a usage delta for alert messages.
+func (r resource) format(delta float64) string {
+	switch r {
+	case resourceMemory:
+		if delta >= 1024 {
+			return fmt.Sprintf("+%.1f GB", delta/1024)
+		}
+		return fmt.Sprintf("+%.0f MB", delta)
+	}
+	return fmt.Sprintf("+%.1f%%", delta)
+}
+
+// processKey identifies a process across snapshots; the name guards
+// against PID reuse.
+type processKey struct {
+	pid  int32
+	name string
+}
+
+// attributeSpike compares the average per-process usage during the last
+// window of the snapshots to the period before it and returns up to limit
+// processes with the largest increase. Snapshots only list the top
+// processes, so the usage of a process missing from a snapshot is unknown:
+// during the spike it is counted as nothing, before it as much as the last
+// listed process (or nothing if it had not started yet). Both the value and
+// the increase are therefore lower bounds.
+func attributeSpike(r resource, snapshots []*models.Metrics, window time.Duration, limit int) []models.ProcessContribution {
+	if len(snapshots) == 0 || limit <= 0 {
+		return nil
+	}
+
+	end := snapshots[len(snapshots)-1].Timestamp
+	spikeStart := end.Add(-window)
+	split := len(snapshots) - 1
+	for split > 0 && snapshots[split-1].Timestamp.After(spikeStart) {
+		split--
+	}
+	spike, baseline := snapshots[split:], snapshots[:split]
+
+	// Processes seen during the spike, with their start time
+	started := make(map[processKey]time.Time)
+	var order []processKey
+	for _, m := range spike {
+		for _, p := range r.processes(m) {
+			key := processKey{p.PID, p.Name}
+			if _, ok := started[key]; !ok {
+				order = append(order, key)
+			}
+			started[key] = p.StartTime
+		}
+	}
+
+	periodStart := snapshots[0].Timestamp
+	var result []models.ProcessContribution
+	for _, key := range order {
+		value := averageUsage(r, spike, key, started[key], false)
+		delta := value
+		if len(baseline) > 0 {
+			delta -= averageUsage(r, baseline, key, started[key], true)
+		}
+		if delta <= 0 {
+			continue
+		}
+		result = append(result, models.ProcessContribution{
+			Name:  key.name,
+			PID:   key.pid,
+			Value: value,
+			Delta: delta,
+			New:   !started[key].IsZero() && started[key].After(periodStart),
+		})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Delta > result[j].Delta
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// averageUsage returns the average usage of a process over the snapshots.
+func averageUsage(r resource, snapshots []*models.Metrics, key processKey, started time.Time, upper bool) float64 {
+	var sum float64
+	for _, m := range snapshots {
+		sum += usageAt(r, m, key, started, upper)
+	}
+	return sum / float64(len(snapshots))
+}
+
+// usageAt returns the usage of a process in one snapshot. If the process is
+// not listed, upper selects an upper bound for its usage instead of zero.
+func usageAt(r resource, m *models.Metrics, key processKey, started time.Time, upper bool) float64 {
+	processes := r.processes(m)
+	for i := range processes {
+		if processes[i].PID == key.pid && processes[i].Name == key.name {
+			return r.value(&processes[i])
+		}
+	}
+	if !upper || len(processes) == 0 || (!started.IsZero() && started.After(m.Timestamp)) {
+		return 0
+	}
+	// Lists are sorted by usage, the process used at most the last value
+	return r.value(&processes[len(processes)-1])
+}
+
+// formatContributors appends the contributors to an alert message.
+func formatContributors(r resource, contributors []models.ProcessContribution) string {
+	parts := make([]string, len(contributors))
+	for i, c := range contributors {
+		parts[i] = fmt.Sprintf("%s %s", c.Name, r.format(c.Delta))
+		if c.New {
+			parts[i] += " (new)"
+		}
+	}
+	return "; top contributors: " + strings.Join(parts, ", ")
+}
@@ -0,0 +1,166 @@
+package alerter
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+var attributionStart = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// cpuSnapshots returns one snapshot per second; processes returns the
+// listed processes (sorted by CPU usage) at each second.
+func cpuSnapshots(seconds int, processes func(second int) []models.ProcessInfo) []*models.Metrics {
+	snapshots := make([]*models.Metrics, seconds)
+	for i := range snapshots {
+		snapshots[i] = &models.Metrics{
+			Timestamp:    attributionStart.Add(time.Duration(i) * time.Second),
+			TopProcesses: processes(i),
+		}
+	}
+	return snapshots
+}
+
+func cpuProcess(name string, pid int32, cpu float64) models.ProcessInfo {
+	return models.ProcessInfo{Name: name, PID: pid, CPUPercent: cpu}
+}
+
+func TestAttributeSpike(t *testing.T) {
+	// With a 5 s window over 10 snapshots, seconds 5-9 are the spike
+	tests := []struct {
+		name      string
+		processes func(second int) []models.ProcessInfo
+		window    time.Duration
+		limit     int
+		want      []models.ProcessContribution
+	}{
+		{
+			name: "spike and baseline",
+			processes: func(second int) []models.ProcessInfo {
+				if second < 5 {
+					return []models.ProcessInfo{cpuProcess("game.exe", 10, 10), cpuProcess("idle.exe", 20, 5)}
+				}
+				return []models.ProcessInfo{cpuProcess("game.exe", 10, 50), cpuProcess("idle.exe", 20, 5)}
+			},
+			window: 5 * time.Second,
+			limit:  3,
+			want: []models.ProcessContribution{
+				{Name: "game.exe", PID: 10, Value: 50, Delta: 40},
+			},
+		},
+		{
+			name: "no baseline",
+			processes: func(second int) []models.ProcessInfo {
+				return []models.ProcessInfo{cpuProcess("game.exe", 10, 30)}
+			},
+			window: time.Minute,
+			limit:  3,
+			want: []models.ProcessContribution{
+				{Name: "game.exe", PID: 10, Value: 30, Delta: 30},
+			},
+		},
+		{
+			name: "new process",
+			processes: func(second int) []models.ProcessInfo {
+				old := cpuProcess("game.exe", 10, 20)
+				old.StartTime = attributionStart.Add(-time.Hour)
+				if second < 6 {
+					return []models.ProcessInfo{old}
+				}
+				fresh := cpuProcess("update.exe", 30, 30)
+				fresh.StartTime = attributionStart.Add(6 * time.Second)
+				old.CPUPercent = 25
+				return []models.ProcessInfo{fresh, old}
+			},
+			window: 5 * time.Second,
+			limit:  3,
+			want: []models.ProcessContribution{
+				// Not running at second 5, so 4 of 5 spike samples
+				{Name: "update.exe", PID: 30, Value: 24, Delta: 24, New: true},
+				{Name: "game.exe", PID: 10, Value: 24, Delta: 4},
+			},
+		},
+		{
+			name: "pid reuse",
+			processes: func(second int) []models.ProcessInfo {
+				if second < 5 {
+					return []models.ProcessInfo{cpuProcess("old.exe", 10, 40)}
+				}
+				p := cpuProcess("new.exe", 10, 40)
+				p.StartTime = attributionStart.Add(5 * time.Second)
+				return []models.ProcessInfo{p}
+			},
+			window: 5 * time.Second,
+			limit:  3,
+			want: []models.ProcessContribution{
+				{Name: "new.exe", PID: 10, Value: 40, Delta: 40, New: true},
+			},
+		},
+		{
+			name: "missing from snapshots",
+			processes: func(second int) []models.ProcessInfo {
+				switch {
+				case second < 5:
+					// x.exe is below the listed processes
+					return []models.ProcessInfo{cpuProcess("hog.exe", 1, 10), cpuProcess("y.exe", 3, 5)}
+				case second < 8:
+					return []models.ProcessInfo{cpuProcess("hog.exe", 1, 80), cpuProcess("x.exe", 2, 30)}
+				}
+				return []models.ProcessInfo{cpuProcess("hog.exe", 1, 80), cpuProcess("y.exe", 3, 35)}
+			},
+			window: 5 * time.Second,
+			limit:  3,
+			want: []models.ProcessContribution{
+				{Name: "hog.exe", PID: 1, Value: 80, Delta: 70},
+				// Counted as nothing when missing during the spike and as
+				// the last listed process before it
+				{Name: "x.exe", PID: 2, Value: 18, Delta: 13},
+				{Name: "y.exe", PID: 3, Value: 14, Delta: 9},
+			},
+		},
+		{
+			name: "limit",
+			processes: func(second int) []models.ProcessInfo {
+				if second < 5 {
+					return []models.ProcessInfo{cpuProcess("a.exe", 1, 10), cpuProcess("b.exe", 2, 10)}
+				}
+				return []models.ProcessInfo{cpuProcess("a.exe", 1, 60), cpuProcess("b.exe", 2, 40)}
+			},
+			window: 5 * time.Second,
+			limit:  1,
+			want: []models.ProcessContribution{
+				{Name: "a.exe", PID: 1, Value: 60, Delta: 50},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := attributeSpike(resourceCPU, cpuSnapshots(10, tt.processes), tt.window, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d contributors, got %+v", len(tt.want), got)
+			}
+			for i, want := range tt.want {
+				c := got[i]
+				if c.Name != want.Name || c.PID != want.PID || c.New != want.New ||
+					math.Abs(c.Value-want.Value) > 1e-9 || math.Abs(c.Delta-want.Delta) > 1e-9 {
+					t.Errorf("Contributor %d: expected %+v, got %+v", i, want, c)
+				}
+			}
+		})
+	}
+}
+
+func TestAttributeSpikeEmpty(t *testing.T) {
+	if got := attributeSpike(resourceCPU, nil, 5*time.Second, 3); got != nil {
+		t.Errorf("Expected no contributors without snapshots, got %+v", got)
+	}
+	snapshots := cpuSnapshots(3, func(int) []models.ProcessInfo {
+		return []models.ProcessInfo{cpuProcess("game.exe", 10, 30)}
+	})
+	if got := attributeSpike(resourceCPU, snapshots, 5*time.Second, 0); got != nil {
+		t.Errorf("Expected no contributors with a zero limit, got %+v", got)
+	}
+}
@@ -0,0 +1,222 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// Defaults for the bottleneck classifier.
+const (
+	defaultBottleneckWindow = 5 * time.Second
+	// bottleneckMinSamples is how many samples are needed to classify. The
+	// window keeps at least this many, so that long collection intervals
+	// (adaptive sampling) stretch it instead of leaving one sample.
+	bottleneckMinSamples = 3
+	// bottleneckMinScore is the score a component needs to be the limit.
+	bottleneckMinScore = 0.5
+)
+
+// bottleneckSample is the classifier input from one snapshot.
+type bottleneckSample struct {
+	at         time.Time
+	cpu        float64 // total CPU usage
+	hottest    float64 // usage of the busiest core
+	cores      int
+	gpu        float64
+	gpuOK      bool
+	vramUsedMB uint64
+	vramMB     uint64
+	faults     float64 // hard page faults per second
+	queue      float64 // disk queue length
+}
+
+// bottleneckInputs are the classifier inputs averaged over the window.
+type bottleneckInputs struct {
+	cpu, hottest, gpu, vram, faults, queue float64
+	vramUsedMB, vramMB                     uint64
+	cores                                  int
+	gpuOK                                  bool
+}
+
+// BottleneckAnalyzer classifies what limits performance from the recent
+// total CPU, hottest core, GPU utilisation, VRAM, paging and disk queue.
+//
+// Each component gets a score between 0 and 1 from the averages over the
+// window; the highest score wins if it reaches 0.5, otherwise the system is
+// balanced. A busy GPU explains a busy CPU thread and I/O waits, so those
+// scores are reduced while the GPU (or all cores) is saturated.
+type BottleneckAnalyzer struct {
+	window time.Duration
+
+	mu      sync.Mutex
+	samples []bottleneckSample
+}
+
+// NewBottleneckAnalyzer creates a new bottleneck classifier.
+func NewBottleneckAnalyzer(cfg *config.BottleneckConfig) *BottleneckAnalyzer {
+	a := &BottleneckAnalyzer{window: defaultBottleneckWindow}
+	if cfg != nil && cfg.Window > 0 {
+		a.window = cfg.Window
+	}
+	return a
+}
+
+// Analyze adds a snapshot to the window and returns the classification. The
+// state is empty until enough samples have been seen.
+func (a *BottleneckAnalyzer) Analyze(m *models.Metrics) models.BottleneckState {
+	s := bottleneckSample{
+		at:         m.Timestamp,
+		cpu:        m.CPU.UsagePercent,
+		hottest:    m.CPU.UsagePercent,
+		cores:      len(m.CPU.PerCorePercent),
+		gpu:        m.GPU.UsagePercent,
+		gpuOK:      m.GPU.Available,
+		vramUsedMB: m.GPU.VRAMUsedMB,
+		vramMB:     m.GPU.VRAMTotalMB,
+		faults:     m.Memory.HardFaultsPerSec,
+		queue:      m.Disk.QueueLength,
+	}
+	for _, core := range m.CPU.PerCorePercent {
+		s.hottest = max(s.hottest, core)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.samples = append(a.samples, s)
+	cutoff := s.at.Add(-a.window)
+	drop := 0
+	for drop < len(a.samples)-bottleneckMinSamples && !a.samples[drop].at.After(cutoff) {
+		drop++
+	}
+	a.samples = append(a.samples[:0], a.samples[drop:]...)
+
+	if len(a.samples) < bottleneckMinSamples {
+		return models.BottleneckState{}
+	}
+	return classifyBottleneck(averageBottleneckInputs(a.samples))
+}
+
+// Reset clears the window, e.g. after a collection gap.
+func (a *BottleneckAnalyzer) Reset() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.samples = nil
+}
+
+// averageBottleneckInputs averages the samples. VRAM uses the latest
+// reading, the GPU is considered only if available in every sample.
+func averageBottleneckInputs(samples []bottleneckSample) bottleneckInputs {
+	in := bottleneckInputs{gpuOK: true}
+	for _, s := range samples {
+		in.cpu += s.cpu
+		in.hottest += s.hottest
+		in.gpu += s.gpu
+		in.faults += s.faults
+		in.queue += s.queue
+		in.gpuOK = in.gpuOK && s.gpuOK
+		if s.vramMB > 0 {
+			in.vram += float64(s.vramUsedMB) / float64(s.vramMB)
+		}
+	}
+	n := float64(len(samples))
+	in.cpu /= n
+	in.hottest /= n
+	in.gpu /= n
+	in.faults /= n
+	in.queue /= n
+	in.vram /= n
+
+	last := samples[len(samples)-1]
+	in.cores = last.cores
+	in.vramUsedMB = last.vramUsedMB
+	in.vramMB = last.vramMB
+	return in
+}
+
+// classifyBottleneck scores each component and picks the limiting one.
+func classifyBottleneck(in bottleneckInputs) models.BottleneckState {
+	var gpu, vram float64
+	if in.gpuOK {
+		gpu = ramp(in.gpu, 85, 98)
+		if in.vramMB > 0 {
+			vram = ramp(in.vram, 0.90, 0.98)
+		}
+	}
+	cpu := ramp(in.cpu, 85, 97)
+
+	// One saturated core matters only if the others have headroom
+	var thread float64
+	if in.cores > 1 {
+		thread = ramp(in.hottest, 75, 95) * (1 - ramp(in.cpu, 70, 90)) * (1 - gpu)
+	}
+	stall := max(ramp(in.queue, 2, 8), ramp(in.faults, 100, 1000))
+	io := stall * (1 - max(gpu, cpu))
+
+	// On a tie the earlier class wins: full VRAM explains a busy GPU
+	scores := []struct {
+		class models.BottleneckClass
+		score float64
+	}{
+		{models.BottleneckVRAM, vram},
+		{models.BottleneckGPU, gpu},
+		{models.BottleneckCPUThread, thread},
+		{models.BottleneckCPU, cpu},
+		{models.BottleneckIO, io},
+	}
+	best, second := 0, -1
+	for i := 1; i < len(scores); i++ {
+		if scores[i].score > scores[best].score {
+			best, second = i, best
+		} else if second < 0 || scores[i].score > scores[second].score {
+			second = i
+		}
+	}
+
+	state := models.BottleneckState{Reason: bottleneckReason(in)}
+	top := scores[best].score
+	if top < bottleneckMinScore {
+		state.Class = models.BottleneckBalanced
+		state.Confidence = 1 - top
+		return state
+	}
+	state.Class = scores[best].class
+	state.Confidence = min(1, max(0, top-0.5*scores[second].score))
+	return state
+}
+
+// bottleneckReason lists the averaged readings.
+func bottleneckReason(in bottleneckInputs) string {
+	cpu := fmt.Sprintf("CPU %.0f%%", in.cpu)
+	if in.cores > 1 {
+		cpu += fmt.Sprintf(" (hottest core %.0f%%)", in.hottest)
+	}
+	parts := []string{cpu}
+	if in.gpuOK {
+		parts = append(parts, fmt.Sprintf("GPU %.0f%%", in.gpu))
+		if in.vramMB > 0 {
+			parts = append(parts, fmt.Sprintf("VRAM %.1f/%.1f GB",
+				float64(in.vramUsedMB)/1024, float64(in.vramMB)/1024))
+		}
+	}
+	parts = append(parts,
+		fmt.Sprintf("disk queue %.1f", in.queue),
+		fmt.Sprintf("%.0f hard faults/s", in.faults))
+	return strings.Join(parts, ", ")
+}
+
+// ramp maps v linearly from 0 at low to 1 at high.
+func ramp(v, low, high float64) float64 {
+	switch {
+	case v <= low:
+		return 0
+	case v >= high:
+		return 1
+	}
+	return (v - low) / (high - low)
+}
@@ -0,0 +1,131 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// bottleneckMetrics returns a snapshot for the classifier; cores lists the
+// per-core usage and the total is their average.
+func bottleneckMetrics(at time.Time, cores []float64, gpu float64, vramUsedMB uint64) *models.Metrics {
+	m := &models.Metrics{Timestamp: at}
+	m.CPU.PerCorePercent = cores
+	for _, c := range cores {
+		m.CPU.UsagePercent += c / float64(len(cores))
+	}
+	m.GPU.Available = true
+	m.GPU.UsagePercent = gpu
+	m.GPU.VRAMUsedMB = vramUsedMB
+	m.GPU.VRAMTotalMB = 8192
+	return m
+}
+
+func TestBottleneckAnalyzer(t *testing.T) {
+	start := time.Now()
+	idle := []float64{20, 15, 10, 25}
+	oneThread := []float64{99, 20, 15, 30}
+	allCores := []float64{99, 98, 99, 97}
+
+	tests := []struct {
+		name   string
+		cores  []float64
+		gpu    float64
+		vramMB uint64
+		faults float64
+		queue  float64
+		want   models.BottleneckClass
+	}{
+		{"balanced", idle, 40, 2048, 0, 0, models.BottleneckBalanced},
+		{"gpu", idle, 99, 4096, 0, 0, models.BottleneckGPU},
+		{"vram", idle, 99, 8100, 0, 0, models.BottleneckVRAM},
+		{"single thread", oneThread, 60, 4096, 0, 0, models.BottleneckCPUThread},
+		{"single thread with busy gpu", oneThread, 99, 4096, 0, 0, models.BottleneckGPU},
+		{"all cores", allCores, 60, 4096, 0, 0, models.BottleneckCPU},
+		{"paging", idle, 30, 4096, 2000, 0, models.BottleneckIO},
+		{"disk queue", idle, 30, 4096, 0, 10, models.BottleneckIO},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := NewBottleneckAnalyzer(&config.BottleneckConfig{Window: 5 * time.Second})
+
+			var state models.BottleneckState
+			for i := 0; i < bottleneckMinSamples; i++ {
+				if state.Class != "" {
+					t.Fatalf("Expected no classification before %d samples", bottleneckMinSamples)
+				}
+				m := bottleneckMetrics(start.Add(time.Duration(i)*time.Second), tt.cores, tt.gpu, tt.vramMB)
+				m.Memory.HardFaultsPerSec = tt.faults
+				m.Disk.QueueLength = tt.queue
+				state = a.Analyze(m)
+			}
+
+			if state.Class != tt.want {
+				t.Errorf("Expected %s, got %s (%s)", tt.want, state.Class, state.Reason)
+			}
+			if state.Confidence < 0.5 || state.Confidence > 1 {
+				t.Errorf("Expected a confident classification, got %.2f", state.Confidence)
+			}
+		})
+	}
+}
+
+func TestBottleneckAnalyzerWindow(t *testing.T) {
+	a := NewBottleneckAnalyzer(&config.BottleneckConfig{Window: 3 * time.Second})
+	start := time.Now()
+
+	// A GPU-bound period followed by a balanced one
+	for i := 0; i < 5; i++ {
+		a.Analyze(bottleneckMetrics(start.Add(time.Duration(i)*time.Second), []float64{20, 20}, 99, 1024))
+	}
+	var state models.BottleneckState
+	for i := 5; i < 9; i++ {
+		state = a.Analyze(bottleneckMetrics(start.Add(time.Duration(i)*time.Second), []float64{20, 20}, 30, 1024))
+	}
+	if state.Class != models.BottleneckBalanced {
+		t.Errorf("Expected old samples to leave the window, got %s (%s)", state.Class, state.Reason)
+	}
+
+	a.Reset()
+	if state = a.Analyze(bottleneckMetrics(start.Add(10*time.Second), []float64{20, 20}, 99, 1024)); state.Class != "" {
+		t.Errorf("Expected no classification after a reset, got %s", state.Class)
+	}
+}
+
+func TestBottleneckAnalyzerSlowInterval(t *testing.T) {
+	a := NewBottleneckAnalyzer(&config.BottleneckConfig{Window: 5 * time.Second})
+	start := time.Now()
+
+	// At a 5 s interval the window holds one sample; the last three are used
+	var state models.BottleneckState
+	for i := 0; i < 4; i++ {
+		if i > 0 && i < bottleneckMinSamples && state.Class != "" {
+			t.Fatalf("Expected no classification from %d samples", i)
+		}
+		state = a.Analyze(bottleneckMetrics(start.Add(time.Duration(i)*5*time.Second), []float64{20, 20}, 99, 1024))
+	}
+	if state.Class != models.BottleneckGPU {
+		t.Errorf("Expected a GPU bottleneck, got %s (%s)", state.Class, state.Reason)
+	}
+	if len(a.samples) != bottleneckMinSamples {
+		t.Errorf("Expected %d samples to be kept, got %d", bottleneckMinSamples, len(a.samples))
+	}
+}
+
+func TestBottleneckAnalyzerNoGPU(t *testing.T) {
+	a := NewBottleneckAnalyzer(nil)
+	start := time.Now()
+
+	var state models.BottleneckState
+	for i := 0; i < bottleneckMinSamples; i++ {
+		m := bottleneckMetrics(start.Add(time.Duration(i)*time.Second), []float64{30, 30}, 0, 0)
+		m.GPU = models.GPUMetrics{}
+		state = a.Analyze(m)
+	}
+	if state.Class != models.BottleneckBalanced || state.Reason != "CPU 30% (hottest core 30%), disk queue 0.0, 0 hard faults/s" {
+		t.Errorf("Unexpected state without a GPU: %+v", state)
+	}
+}
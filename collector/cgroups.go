@@ -0,0 +1,258 @@
+package collector
+
+import (
+	"bufio"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// defaultCgroupRoot is the cgroup v2 unified hierarchy mount point.
+const defaultCgroupRoot = "/sys/fs/cgroup"
+
+// cgroupSample holds the cumulative counters of a cgroup at a point in time.
+type cgroupSample struct {
+	usageUsec  uint64
+	readBytes  uint64
+	writeBytes uint64
+	at         time.Time
+}
+
+// CgroupCollector collects resource usage of cgroup v2 groups, such as
+// systemd units and containers.
+type CgroupCollector struct {
+	root   string
+	paths  []string
+	slices []string
+
+	mu   sync.Mutex
+	last map[string]cgroupSample // cgroup path -> previous sample
+}
+
+// NewCgroupCollector creates a new cgroup collector for the configured
+// paths and slices.
+func NewCgroupCollector(cfg *config.CgroupsConfig) *CgroupCollector {
+	root := cfg.Root
+	if root == "" {
+		root = defaultCgroupRoot
+	}
+	return &CgroupCollector{
+		root:   root,
+		paths:  cfg.Paths,
+		slices: cfg.Slices,
+		last:   make(map[string]cgroupSample),
+	}
+}
+
+// Collect gathers current cgroup statistics.
+func (c *CgroupCollector) Collect() []models.CgroupStats {
+	return c.collectAt(time.Now())
+}
+
+// Reset discards the previous samples so that the next rates are not
+// computed across a collection gap.
+func (c *CgroupCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last = make(map[string]cgroupSample)
+}
+
+// collectAt reads all monitored cgroups. Rates are computed against the
+// previous call and are zero on the first one.
+func (c *CgroupCollector) collectAt(now time.Time) []models.CgroupStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var result []models.CgroupStats
+	seen := make(map[string]bool)
+	for _, p := range c.cgroupPaths() {
+		if seen[p] {
+			continue
+		}
+		stats, sample, ok := readCgroup(c.root, p)
+		if !ok {
+			continue
+		}
+		seen[p] = true
+		sample.at = now
+
+		if prev, ok := c.last[p]; ok {
+			elapsed := now.Sub(prev.at).Seconds()
+			if elapsed > 0 {
+				if sample.usageUsec >= prev.usageUsec {
+					stats.CPUPercent = float64(sample.usageUsec-prev.usageUsec) / (elapsed * 1e6) * 100
+				}
+				if sample.readBytes >= prev.readBytes {
+					stats.IOReadKBps = float64(sample.readBytes-prev.readBytes) / 1024 / elapsed
+				}
+				if sample.writeBytes >= prev.writeBytes {
+					stats.IOWriteKBps = float64(sample.writeBytes-prev.writeBytes) / 1024 / elapsed
+				}
+			}
+		}
+		c.last[p] = sample
+		result = append(result, stats)
+	}
+
+	// Forget cgroups that went away (stopped units, removed containers)
+	for p := range c.last {
+		if !seen[p] {
+			delete(c.last, p)
+		}
+	}
+
+	return result
+}
+
+// cgroupPaths returns the configured paths followed by the children of
+// each configured slice, sorted by name.
+func (c *CgroupCollector) cgroupPaths() []string {
+	paths := make([]string, 0, len(c.paths))
+	for _, p := range c.paths {
+		paths = append(paths, cleanCgroupPath(p))
+	}
+
+	for _, slice := range c.slices {
+		slice = cleanCgroupPath(slice)
+		entries, err := os.ReadDir(filepath.Join(c.root, filepath.FromSlash(slice)))
+		if err != nil {
+			continue
+		}
+		var children []string
+		for _, entry := range entries {
+			if entry.IsDir() {
+				children = append(children, path.Join(slice, entry.Name()))
+			}
+		}
+		sort.Strings(children)
+		paths = append(paths, children...)
+	}
+
+	return paths
+}
+
+// cleanCgroupPath normalizes a configured cgroup path to a relative,
+// slash-separated form.
+func cleanCgroupPath(p string) string {
+	return strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(p)), "/")
+}
+
+// readCgroup reads the statistics of a single cgroup. It returns false if
+// the cgroup does not exist.
+func readCgroup(root, p string) (models.CgroupStats, cgroupSample, bool) {
+	dir := filepath.Join(root, filepath.FromSlash(p))
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return models.CgroupStats{}, cgroupSample{}, false
+	}
+
+	stats := models.CgroupStats{
+		Path: p,
+		Name: path.Base(p),
+	}
+	var sample cgroupSample
+
+	cpuStat := readKeyValueFile(filepath.Join(dir, "cpu.stat"))
+	sample.usageUsec = cpuStat["usage_usec"]
+	stats.NrThrottled = cpuStat["nr_throttled"]
+	stats.ThrottledMs = cpuStat["throttled_usec"] / 1000
+
+	if current, ok := readCgroupLimit(dir, "memory.current"); ok {
+		stats.MemoryCurrentMB = current / (1024 * 1024)
+		if limit, ok := readCgroupLimit(dir, "memory.max"); ok && limit > 0 {
+			stats.MemoryMaxMB = limit / (1024 * 1024)
+			stats.MemoryPercent = float64(current) / float64(limit) * 100
+		}
+	}
+
+	sample.readBytes, sample.writeBytes = readIOStat(filepath.Join(dir, "io.stat"))
+
+	if pids, ok := readCgroupLimit(dir, "pids.current"); ok {
+		stats.Pids = pids
+	}
+	if pidsMax, ok := readCgroupLimit(dir, "pids.max"); ok {
+		stats.PidsMax = pidsMax
+	}
+
+	return stats, sample, true
+}
+
+// readCgroupLimit reads a single-value cgroup file. "max" (unlimited) is
+// reported as 0.
+func readCgroupLimit(dir, name string) (uint64, bool) {
+	value := readSysfsString(dir, name)
+	if value == "" {
+		return 0, false
+	}
+	if value == "max" {
+		return 0, true
+	}
+	n, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// readKeyValueFile parses a flat-keyed file with "key value" lines, such
+// as cpu.stat.
+func readKeyValueFile(name string) map[string]uint64 {
+	values := make(map[string]uint64)
+
+	file, err := os.Open(name)
+	if err != nil {
+		return values
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseUint(fields[1], 10, 64); err == nil {
+			values[fields[0]] = n
+		}
+	}
+	return values
+}
+
+// readIOStat sums read and written bytes across all devices in io.stat.
+// Lines look like "8:0 rbytes=1459200 wbytes=314773504 rios=192 wios=353".
+func readIOStat(name string) (readBytes, writeBytes uint64) {
+	file, err := os.Open(name)
+	if err != nil {
+		return 0, 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// The first field is the device number and has no "="
+		for _, field := range strings.Fields(scanner.Text()) {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				continue
+			}
+			switch key {
+			case "rbytes":
+				readBytes += n
+			case "wbytes":
+				writeBytes += n
+			}
+		}
+	}
+	return readBytes, writeBytes
+}
@@ -0,0 +1,104 @@
+package collector
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+)
+
+// writeCgroup creates or updates a fake cgroup v2 directory.
+func writeCgroup(t *testing.T, root, path, cpuStat, memCurrent, memMax, ioStat, pids string) {
+	t.Helper()
+
+	writeSysfsFiles(t, filepath.Join(root, filepath.FromSlash(path)), map[string]string{
+		"cpu.stat":       cpuStat,
+		"memory.current": memCurrent,
+		"memory.max":     memMax,
+		"io.stat":        ioStat,
+		"pids.current":   pids,
+		"pids.max":       "max",
+	})
+}
+
+func TestCgroupCollector(t *testing.T) {
+	root := t.TempDir()
+	writeCgroup(t, root, "system.slice/docker.service",
+		"usage_usec 1000000\nuser_usec 800000\nsystem_usec 200000\nnr_periods 10\nnr_throttled 2\nthrottled_usec 5000",
+		"943718400", "1073741824",
+		"8:0 rbytes=1048576 wbytes=0 rios=10 wios=0 dbytes=0 dios=0\n259:0 rbytes=0 wbytes=2097152 rios=0 wios=20",
+		"12")
+	writeCgroup(t, root, "system.slice/cron.service",
+		"usage_usec 50000", "1048576", "max", "", "1")
+	writeCgroup(t, root, "user.slice/user-1000.slice/user@1000.service/app.slice/build.scope",
+		"usage_usec 0", "0", "max", "", "0")
+
+	c := NewCgroupCollector(&config.CgroupsConfig{
+		Root:   root,
+		Paths:  []string{"/user.slice/user-1000.slice/user@1000.service/app.slice/build.scope", "missing.service"},
+		Slices: []string{"system.slice"},
+	})
+
+	start := time.Now()
+	stats := c.collectAt(start)
+	if len(stats) != 3 {
+		t.Fatalf("Expected 3 cgroups, got %d: %+v", len(stats), stats)
+	}
+	// Configured paths first, then slice children sorted by name
+	if stats[0].Name != "build.scope" || stats[1].Name != "cron.service" || stats[2].Name != "docker.service" {
+		t.Errorf("Unexpected cgroup order: %s, %s, %s", stats[0].Name, stats[1].Name, stats[2].Name)
+	}
+
+	docker := stats[2]
+	if docker.Path != "system.slice/docker.service" {
+		t.Errorf("Expected relative path, got %q", docker.Path)
+	}
+	if docker.MemoryCurrentMB != 900 || docker.MemoryMaxMB != 1024 {
+		t.Errorf("Expected 900/1024 MB, got %d/%d", docker.MemoryCurrentMB, docker.MemoryMaxMB)
+	}
+	if docker.MemoryPercent < 87.8 || docker.MemoryPercent > 87.9 {
+		t.Errorf("Expected memory at ~87.9%%, got %.2f", docker.MemoryPercent)
+	}
+	if docker.NrThrottled != 2 || docker.ThrottledMs != 5 {
+		t.Errorf("Expected 2 throttled periods / 5 ms, got %d / %d", docker.NrThrottled, docker.ThrottledMs)
+	}
+	if docker.Pids != 12 || docker.PidsMax != 0 {
+		t.Errorf("Expected 12 pids without limit, got %d/%d", docker.Pids, docker.PidsMax)
+	}
+	if docker.CPUPercent != 0 || docker.IOReadKBps != 0 {
+		t.Errorf("Expected no rates on first sample, got %+v", docker)
+	}
+
+	cron := stats[1]
+	if cron.MemoryMaxMB != 0 || cron.MemoryPercent != 0 {
+		t.Errorf("Expected unlimited memory for cron, got %d MB (%.1f%%)", cron.MemoryMaxMB, cron.MemoryPercent)
+	}
+
+	// 2 seconds later: 1.5 CPU-seconds used, 2 MB read, 4 MB written
+	writeCgroup(t, root, "system.slice/docker.service",
+		"usage_usec 2500000\nnr_throttled 3\nthrottled_usec 9000",
+		"943718400", "1073741824",
+		"8:0 rbytes=3145728 wbytes=0\n259:0 rbytes=0 wbytes=6291456",
+		"12")
+
+	stats = c.collectAt(start.Add(2 * time.Second))
+	docker = stats[2]
+	if docker.CPUPercent != 75 {
+		t.Errorf("Expected 75%% CPU, got %.2f", docker.CPUPercent)
+	}
+	if docker.IOReadKBps != 1024 || docker.IOWriteKBps != 2048 {
+		t.Errorf("Expected 1024/2048 KB/s, got %.1f/%.1f", docker.IOReadKBps, docker.IOWriteKBps)
+	}
+}
+
+func TestCgroupCollectorMissingRoot(t *testing.T) {
+	c := NewCgroupCollector(&config.CgroupsConfig{
+		Root:   filepath.Join(t.TempDir(), "absent"),
+		Paths:  []string{"system.slice"},
+		Slices: []string{"system.slice"},
+	})
+	if stats := c.Collect(); len(stats) != 0 {
+		t.Errorf("Expected no cgroups, got %+v", stats)
+	}
+}
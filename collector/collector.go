@@ -3,6 +3,7 @@ package collector
 
 import (
 	"context"
+	"math"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -39,13 +40,42 @@ type Collector struct {
 	subMu       sync.RWMutex
 
 	// Sub-collectors
-	cpuCollector     *CPUCollector
-	memoryCollector  *MemoryCollector
-	gpuCollector     *GPUCollector
-	diskCollector    *DiskCollector
-	networkCollector *NetworkCollector
-	processCollector *ProcessCollector
-	pingCollector    *PingCollector
+	cpuCollector      *CPUCollector
+	memoryCollector   *MemoryCollector
+	gpuCollector      *GPUCollector
+	diskCollector     *DiskCollector
+	networkCollector  *NetworkCollector
+	processCollector  *ProcessCollector
+	pingCollector     *PingCollector
+	gameServer        *GameServerCollector
+	powerCollector    *PowerCollector
+	sensorCollector   *SensorCollector
+	raplCollector     *RAPLCollector
+	throttleDetector  *ThrottleDetector
+	cgroupCollector   *CgroupCollector
+	wirelessCollector *WirelessCollector
+	pressureCollector *PressureCollector
+	bottleneck        *BottleneckAnalyzer
+	leakDetector      *LeakDetector
+
+	// Power source state and change hooks
+	onBattery  atomic.Bool
+	powerHooks []func(onBattery bool)
+	hooksMu    sync.RWMutex
+
+	// Adaptive sampling state and activity hints from the UI and alerter
+	sampler        *adaptiveSampler
+	overlayVisible atomic.Bool
+	alertActive    atomic.Bool
+	lastSampleAt   time.Time
+
+	// Collector events (resume after sleep)
+	events   []*models.CollectorEvent
+	eventsMu sync.Mutex
+
+	// Cached system inventory
+	sysInfo   *models.SystemInfo
+	sysInfoMu sync.Mutex
 
 	// State
 	running bool
@@ -59,24 +89,73 @@ type Collector struct {
 
 // New creates a new Collector with the given configuration.
 func New(cfg *config.MonitoringConfig) *Collector {
-	historySeconds := int(cfg.HistoryDuration.Seconds())
-	if historySeconds <= 0 {
-		historySeconds = 60
+	// Size the history for the shortest interval so it always covers
+	// HistoryDuration
+	minInterval := cfg.UpdateInterval
+	if cfg.Adaptive.Enabled && cfg.Adaptive.MinInterval > 0 && cfg.Adaptive.MinInterval < minInterval {
+		minInterval = cfg.Adaptive.MinInterval
+	}
+	historySamples := 60
+	if minInterval > 0 && cfg.HistoryDuration > 0 {
+		historySamples = int(cfg.HistoryDuration / minInterval)
 	}
 
 	c := &Collector{
 		config:  cfg,
-		storage: storage.NewRingBuffer(historySeconds),
+		storage: storage.NewRingBuffer(historySamples),
 		log:     logger.Get(),
 	}
 
+	if cfg.Adaptive.Enabled {
+		c.sampler = newAdaptiveSampler(&cfg.Adaptive, cfg.UpdateInterval)
+	}
+
 	// Initialize sub-collectors
 	c.cpuCollector = NewCPUCollector()
 	c.memoryCollector = NewMemoryCollector()
 	c.diskCollector = NewDiskCollector()
 	c.networkCollector = NewNetworkCollector()
+	c.pressureCollector = NewPressureCollector("")
 	c.processCollector = NewProcessCollector(cfg.TopProcessCount)
-	c.pingCollector = NewPingCollector()
+	c.processCollector.SetGrouping(&cfg.ProcessGrouping)
+	if cfg.ProcessGPU.Enabled {
+		c.processCollector.SetGPU(NewProcessGPUCollector(&cfg.ProcessGPU))
+	}
+	if cfg.LeakDetection.Enabled {
+		c.leakDetector = NewLeakDetector(&cfg.LeakDetection)
+		c.processCollector.SetLeakDetector(c.leakDetector)
+	}
+	if err := c.processCollector.SetWatchlist(cfg.Watchlist); err != nil {
+		c.log.Warnf("Process watchlist: %v", err)
+	}
+	if cfg.Ping.Enabled {
+		c.pingCollector = NewPingCollector(&cfg.Ping)
+	}
+	if cfg.Throttling.Enabled {
+		c.throttleDetector = NewThrottleDetector(&cfg.Throttling)
+	}
+	if cfg.Bottleneck.Enabled {
+		c.bottleneck = NewBottleneckAnalyzer(&cfg.Bottleneck)
+		c.cpuCollector.SetPerCore(true)
+	}
+	if cfg.RAPL.Enabled {
+		c.raplCollector = NewRAPLCollector(cfg.RAPL.Root)
+	}
+	if cfg.Sensors.Enabled {
+		c.sensorCollector = NewSensorCollector(&cfg.Sensors)
+	}
+	if cfg.Cgroups.Enabled {
+		c.cgroupCollector = NewCgroupCollector(&cfg.Cgroups)
+	}
+	if cfg.Wireless.Enabled {
+		c.wirelessCollector = NewWirelessCollector(&cfg.Wireless)
+	}
+	if cfg.Power.Enabled {
+		c.powerCollector = NewPowerCollector(cfg.Power.Root)
+	}
+	if cfg.GameServer.Enabled {
+		c.gameServer = NewGameServerCollector(&cfg.GameServer, c.networkCollector)
+	}
 
 	if cfg.EnableGPU {
 		c.gpuCollector = NewGPUCollector()
@@ -120,6 +199,13 @@ func (c *Collector) Start(ctx context.Context) error {
 		}
 	}
 
+	// Initialize game server detection
+	if c.gameServer != nil {
+		if err := c.gameServer.Init(); err != nil {
+			c.log.Warnf("Game server detection unavailable: %v", err)
+		}
+	}
+
 	// Initial collection
 	c.collect()
 
@@ -127,7 +213,12 @@ func (c *Collector) Start(ctx context.Context) error {
 	c.wg.Add(1)
 	go c.collectionLoop(ctx)
 
-	c.log.Infof("Collector started with %v interval", c.config.UpdateInterval)
+	if c.sampler != nil {
+		c.log.Infof("Collector started with adaptive interval (%v-%v)",
+			c.config.Adaptive.MinInterval, c.config.Adaptive.MaxInterval)
+	} else {
+		c.log.Infof("Collector started with %v interval", c.config.UpdateInterval)
+	}
 	return nil
 }
 
@@ -158,6 +249,11 @@ func (c *Collector) Stop() {
 		c.pingCollector.Shutdown()
 	}
 
+	// Cleanup game server detection
+	if c.gameServer != nil {
+		c.gameServer.Shutdown()
+	}
+
 	c.log.Info("Collector stopped")
 }
 
@@ -170,7 +266,8 @@ func (c *Collector) collectionLoop(ctx context.Context) {
 		}
 	}()
 
-	ticker := time.NewTicker(c.config.UpdateInterval)
+	interval := c.sampleInterval()
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for {
@@ -179,14 +276,56 @@ func (c *Collector) collectionLoop(ctx context.Context) {
 			return
 		case <-ticker.C:
 			c.collect()
+
+			// Switch sampling profile when activity or the power source changes
+			if next := c.sampleInterval(); next != interval {
+				c.log.Debugf("Collection interval changed to %v", next)
+				interval = next
+				ticker.Reset(interval)
+			}
 		}
 	}
 }
 
+// sampleInterval returns the collection interval for the current activity
+// and power source.
+func (c *Collector) sampleInterval() time.Duration {
+	if c.sampler == nil {
+		if c.onBattery.Load() && c.config.Power.BatteryInterval > 0 {
+			return c.config.Power.BatteryInterval
+		}
+		return c.config.UpdateInterval
+	}
+
+	var load float64
+	if latest := c.GetLatest(); latest != nil {
+		load = math.Max(latest.CPU.UsagePercent, latest.GPU.UsagePercent)
+	}
+	alertActive := c.alertActive.Load()
+	interval := c.sampler.next(load, c.overlayVisible.Load(), alertActive)
+
+	// The battery interval is a floor unless an alert needs attention
+	if c.onBattery.Load() && !alertActive && interval < c.config.Power.BatteryInterval {
+		interval = c.config.Power.BatteryInterval
+	}
+	return interval
+}
+
+// SetActivity reports whether the overlay is visible and whether an alert
+// is active. Used by adaptive sampling to pick the collection interval.
+func (c *Collector) SetActivity(overlayVisible, alertActive bool) {
+	c.overlayVisible.Store(overlayVisible)
+	c.alertActive.Store(alertActive)
+}
+
 // collect gathers all metrics and stores them.
 func (c *Collector) collect() {
 	metrics := models.NewMetrics()
 
+	// After a suspend, reset rate baselines before collecting so that the
+	// first rates are not averaged over the sleep
+	metrics.Event = c.checkResume(metrics.Timestamp)
+
 	// Use timeout for all collection - never block more than 800ms
 	done := make(chan struct{})
 
@@ -199,6 +338,9 @@ func (c *Collector) collect() {
 			defer wg.Done()
 			defer recoverPanic("CPU")
 			metrics.CPU = c.cpuCollector.Collect()
+			if c.raplCollector != nil {
+				c.raplCollector.Apply(&metrics.CPU)
+			}
 		}()
 
 		// Collect memory metrics
@@ -235,6 +377,36 @@ func (c *Collector) collect() {
 			metrics.Network = c.networkCollector.Collect()
 		}()
 
+		// Collect hardware sensors
+		if c.sensorCollector != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer recoverPanic("Sensors")
+				metrics.Sensors = c.sensorCollector.Collect()
+			}()
+		}
+
+		// Collect power supply metrics
+		if c.powerCollector != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer recoverPanic("Power")
+				metrics.Power = c.powerCollector.Collect()
+			}()
+		}
+
+		// Collect cgroup (systemd unit, container) metrics
+		if c.cgroupCollector != nil {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer recoverPanic("Cgroups")
+				metrics.Cgroups = c.cgroupCollector.Collect()
+			}()
+		}
+
 		// Collect process metrics
 		if c.config.EnableProcesses {
 			wg.Add(1)
@@ -242,6 +414,11 @@ func (c *Collector) collect() {
 				defer wg.Done()
 				defer recoverPanic("Processes")
 				metrics.TopProcesses = c.processCollector.Collect()
+				metrics.ProcessGroups = c.processCollector.GetGroups()
+				metrics.TopGPUProcesses = c.processCollector.GetTopByGPU()
+				metrics.TopMemoryProcesses = c.processCollector.GetTopMemory()
+				metrics.TopIOProcesses = c.processCollector.GetTopIO()
+				metrics.WatchedProcesses = c.processCollector.GetWatched()
 			}()
 		}
 
@@ -258,6 +435,27 @@ func (c *Collector) collect() {
 		c.log.Debug("Collection timeout, using partial metrics")
 	}
 
+	// Fill in the CPU temperature from hwmon when the platform has none
+	if metrics.CPU.Temperature == 0 {
+		metrics.CPU.Temperature = cpuTemperatureFromSensors(metrics.Sensors)
+	}
+
+	// Detect throttling (also refreshes the live CPU frequency)
+	if c.throttleDetector != nil {
+		metrics.Throttling = c.throttleDetector.Detect(&metrics.CPU, metrics.GPU, metrics.Timestamp)
+	}
+
+	// Paging and disk queue, then classify the bottleneck from them
+	c.pressureCollector.Apply(&metrics.Memory, &metrics.Disk)
+	if c.bottleneck != nil {
+		metrics.Bottleneck = c.bottleneck.Analyze(metrics)
+	}
+
+	// Memory leaks, projected against the current free memory
+	if c.leakDetector != nil && c.config.EnableProcesses {
+		metrics.MemoryLeaks = c.leakDetector.Leaks(&metrics.Memory)
+	}
+
 	// Add ping data (non-blocking, reads cached values)
 	if c.pingCollector != nil && c.pingCollector.IsInitialized() {
 		latency, target := c.pingCollector.GetBestLatency()
@@ -265,8 +463,32 @@ func (c *Collector) collect() {
 			metrics.Network.PingMs = float64(latency.Milliseconds())
 			metrics.Network.PingTarget = target
 		}
+		metrics.Network.PingTargets = c.pingCollector.GetStats()
 	}
 
+	// Add Wi-Fi link quality (association details are cached)
+	if c.wirelessCollector != nil {
+		mergeWireless(&metrics.Network, c.wirelessCollector.Collect())
+	}
+
+	// Add game server latency (non-blocking, reads cached values)
+	if c.gameServer != nil {
+		metrics.Network.GameServer = c.gameServer.GetInfo()
+		if gs := metrics.Network.GameServer; gs != nil && gs.Available {
+			metrics.Network.GameServerMs = gs.LatencyMs
+		}
+	}
+
+	// Record the actual sampling interval; it varies with adaptive sampling
+	// and is unknown across a gap
+	if !c.lastSampleAt.IsZero() && metrics.Event == nil {
+		metrics.IntervalMs = float64(metrics.Timestamp.Sub(c.lastSampleAt).Microseconds()) / 1000
+	}
+	c.lastSampleAt = metrics.Timestamp
+
+	// Detect power source changes (battery <-> AC)
+	c.updatePowerSource(metrics.Power.OnBattery())
+
 	// Store metrics
 	c.storage.Add(metrics)
 
@@ -277,6 +499,42 @@ func (c *Collector) collect() {
 	c.notifySubscribers(metrics)
 }
 
+// updatePowerSource records the power source and runs the hooks on change.
+func (c *Collector) updatePowerSource(onBattery bool) {
+	if c.onBattery.Swap(onBattery) == onBattery {
+		return
+	}
+
+	if onBattery {
+		c.log.Info("Running on battery")
+	} else {
+		c.log.Info("Running on AC power")
+	}
+
+	c.hooksMu.RLock()
+	hooks := make([]func(bool), len(c.powerHooks))
+	copy(hooks, c.powerHooks)
+	c.hooksMu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(onBattery)
+	}
+}
+
+// OnPowerSourceChange registers a hook called when the system switches
+// between battery and AC power.
+func (c *Collector) OnPowerSourceChange(hook func(onBattery bool)) {
+	c.hooksMu.Lock()
+	defer c.hooksMu.Unlock()
+	c.powerHooks = append(c.powerHooks, hook)
+}
+
+// IsOnBattery returns true if the system was running from battery at the
+// last collection.
+func (c *Collector) IsOnBattery() bool {
+	return c.onBattery.Load()
+}
+
 // recoverPanic recovers from panics in collection goroutines.
 func recoverPanic(component string) {
 	if r := recover(); r != nil {
@@ -334,6 +592,27 @@ func (c *Collector) Unsubscribe(ch chan<- *models.Metrics) {
 	}
 }
 
+// SubscribeProcessEvents adds a channel to receive process start/exit events.
+func (c *Collector) SubscribeProcessEvents(ch chan<- *models.ProcessEvent) {
+	c.processCollector.Events().Subscribe(ch)
+}
+
+// UnsubscribeProcessEvents removes a channel from receiving process events.
+func (c *Collector) UnsubscribeProcessEvents(ch chan<- *models.ProcessEvent) {
+	c.processCollector.Events().Unsubscribe(ch)
+}
+
+// GetProcessEvents returns the recent process start/exit events.
+func (c *Collector) GetProcessEvents() []*models.ProcessEvent {
+	return c.processCollector.Events().GetHistory()
+}
+
+// GetProcessTree returns the process hierarchy from the last collection,
+// with resource usage aggregated per subtree.
+func (c *Collector) GetProcessTree() []*models.ProcessTreeNode {
+	return c.processCollector.GetProcessTree()
+}
+
 // IsRunning returns whether the collector is running.
 func (c *Collector) IsRunning() bool {
 	c.mu.RLock()
@@ -341,9 +620,38 @@ func (c *Collector) IsRunning() bool {
 	return c.running
 }
 
-// GetSystemInfo returns static system information.
+// GetSystemInfo returns the system inventory. The inventory is collected
+// once and cached; only the uptime is updated on each call. Use
+// RefreshSystemInfo to re-read it.
 func (c *Collector) GetSystemInfo() *models.SystemInfo {
-	info := &models.SystemInfo{}
+	c.sysInfoMu.Lock()
+	if c.sysInfo == nil {
+		c.sysInfo = c.collectSystemInfo()
+	}
+	info := c.sysInfo.Clone()
+	c.sysInfoMu.Unlock()
+
+	if !info.BootTime.IsZero() {
+		info.UptimeSec = uint64(time.Since(info.BootTime).Seconds())
+	}
+	return info
+}
+
+// RefreshSystemInfo re-reads the system inventory (e.g., after hardware
+// or network changes) and returns the new value.
+func (c *Collector) RefreshSystemInfo() *models.SystemInfo {
+	info := c.collectSystemInfo()
+
+	c.sysInfoMu.Lock()
+	c.sysInfo = info
+	c.sysInfoMu.Unlock()
+
+	return c.GetSystemInfo()
+}
+
+// collectSystemInfo gathers the full system inventory.
+func (c *Collector) collectSystemInfo() *models.SystemInfo {
+	info := collectInventory(defaultSysRoot)
 
 	// Get CPU info
 	if cpuInfo := c.cpuCollector.GetInfo(); cpuInfo != nil {
@@ -21,6 +21,7 @@ type CPUCollector struct {
 	info            *CPUInfo
 	infoOnce        sync.Once
 	cachedFrequency uint32
+	perCore         bool
 }
 
 // NewCPUCollector creates a new CPU collector.
@@ -28,6 +29,13 @@ func NewCPUCollector() *CPUCollector {
 	return &CPUCollector{}
 }
 
+// SetPerCore enables per-core usage collection. It is off by default as it
+// is expensive and rarely shown; the bottleneck classifier needs it to spot
+// a saturated thread.
+func (c *CPUCollector) SetPerCore(enabled bool) {
+	c.perCore = enabled
+}
+
 // Collect gathers current CPU metrics.
 func (c *CPUCollector) Collect() models.CPUMetrics {
 	metrics := models.CPUMetrics{}
@@ -38,11 +46,13 @@ func (c *CPUCollector) Collect() models.CPUMetrics {
 		metrics.UsagePercent = percentages[0]
 	}
 
-	// Get per-core CPU usage - skip this as it's expensive and rarely shown
-	// perCore, err := cpu.Percent(0, true)
-	// if err == nil {
-	// 	metrics.PerCorePercent = perCore
-	// }
+	// Get per-core CPU usage only when needed, it's expensive
+	if c.perCore {
+		perCore, err := cpu.Percent(0, true)
+		if err == nil {
+			metrics.PerCorePercent = perCore
+		}
+	}
 
 	// Use cached frequency from GetInfo() instead of calling every time
 	info := c.GetInfo()
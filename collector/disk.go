@@ -23,6 +23,14 @@ func NewDiskCollector() *DiskCollector {
 	}
 }
 
+// Reset discards the previous I/O counters so that the next rates are not
+// computed across a collection gap.
+func (c *DiskCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastIOCounters = make(map[string]disk.IOCountersStat)
+}
+
 // Collect gathers current disk metrics.
 func (c *DiskCollector) Collect() models.DiskMetrics {
 	metrics := models.DiskMetrics{
@@ -0,0 +1,327 @@
+package collector
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/logger"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+const (
+	defaultGameServerInterval = 5 * time.Second
+	defaultGameServerTimeout  = time.Second
+)
+
+// Socket types reported in ConnectionStat.Type.
+const (
+	sockStream = 1
+	sockDgram  = 2
+)
+
+// gameEndpoint is a remote endpoint a game process is talking to.
+type gameEndpoint struct {
+	ip        string
+	port      uint32
+	transport string // "udp" or "tcp"
+	sockets   int    // number of sockets connected to the endpoint
+}
+
+// GameServerCollector detects the server the game process is connected to
+// and measures the latency to it.
+//
+// UDP endpoints are only visible for connected UDP sockets; most game
+// clients connect their socket to the match server, so UDP endpoints are
+// preferred over TCP ones. Windows does not report the remote address of UDP
+// sockets, so there only games talking to their server over TCP are found.
+// Latency is measured with ICMP echo and, for TCP endpoints that do not
+// answer ICMP, with a TCP connect to the same port.
+type GameServerCollector struct {
+	mu          sync.RWMutex
+	log         *logger.Logger
+	network     *NetworkCollector
+	initialized bool
+	stopCh      chan struct{}
+
+	processes   []string // lowercase game executable names
+	ignorePorts map[uint32]bool
+	interval    time.Duration
+	timeout     time.Duration
+
+	info *models.GameServerInfo
+}
+
+// NewGameServerCollector creates a new game server collector that reads
+// connections through network.
+func NewGameServerCollector(cfg *config.GameServerConfig, network *NetworkCollector) *GameServerCollector {
+	c := &GameServerCollector{
+		log:         logger.Get(),
+		network:     network,
+		stopCh:      make(chan struct{}),
+		ignorePorts: make(map[uint32]bool),
+		interval:    defaultGameServerInterval,
+		timeout:     defaultGameServerTimeout,
+	}
+
+	if cfg != nil {
+		for _, name := range cfg.Processes {
+			c.processes = append(c.processes, strings.ToLower(name))
+		}
+		for _, port := range cfg.IgnorePorts {
+			c.ignorePorts[uint32(port)] = true
+		}
+		if cfg.Interval > 0 {
+			c.interval = cfg.Interval
+		}
+		if cfg.Timeout > 0 {
+			c.timeout = cfg.Timeout
+		}
+	}
+
+	return c
+}
+
+// Init starts the background detection loop.
+func (c *GameServerCollector) Init() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.initialized {
+		return nil
+	}
+
+	c.initialized = true
+	go c.backgroundDetect()
+
+	return nil
+}
+
+// backgroundDetect periodically detects and probes the game server.
+func (c *GameServerCollector) backgroundDetect() {
+	c.update()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.stopCh:
+			return
+		case <-ticker.C:
+			c.update()
+		}
+	}
+}
+
+// update detects the game process and its server, and probes the server.
+func (c *GameServerCollector) update() {
+	info := c.detect()
+
+	c.mu.Lock()
+	prev := c.info
+	c.info = info
+	c.mu.Unlock()
+
+	if info != nil && (prev == nil || prev.Address != info.Address || prev.Port != info.Port) {
+		c.log.Infof("Game server detected: %s %s:%d (%s)", info.ProcessName, info.Address, info.Port, info.Transport)
+	}
+}
+
+// detect returns the current game server, or nil if none is found.
+func (c *GameServerCollector) detect() *models.GameServerInfo {
+	pid, name, ok := c.findGameProcess()
+	if !ok {
+		return nil
+	}
+
+	conns, err := c.network.GetConnectionsByPID("inet", pid)
+	if err != nil {
+		c.log.Debugf("Failed to get connections of %s: %v", name, err)
+		return nil
+	}
+
+	endpoints := selectGameEndpoints(conns, c.ignorePorts)
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	ep := endpoints[0]
+	info := &models.GameServerInfo{
+		ProcessName: name,
+		PID:         pid,
+		Address:     ep.ip,
+		Port:        ep.port,
+		Transport:   ep.transport,
+		Endpoints:   len(endpoints),
+		LastCheck:   time.Now(),
+	}
+
+	latency, method, err := probeGameEndpoint(ep, c.timeout)
+	info.Method = method
+	if err == nil {
+		info.Available = true
+		info.LatencyMs = durationMs(latency)
+	}
+
+	return info
+}
+
+// findGameProcess returns the game process: the foreground process if it is
+// a configured game (or no games are configured), otherwise the first
+// running configured game.
+func (c *GameServerCollector) findGameProcess() (int32, string, bool) {
+	if pid, ok := foregroundPID(); ok {
+		if name := processName(pid); name != "" && (len(c.processes) == 0 || c.isGame(name)) {
+			return pid, name, true
+		}
+	}
+
+	if len(c.processes) == 0 {
+		return 0, "", false
+	}
+
+	procs, err := process.Processes()
+	if err != nil {
+		return 0, "", false
+	}
+	for _, p := range procs {
+		name, err := p.Name()
+		if err == nil && c.isGame(name) {
+			return p.Pid, name, true
+		}
+	}
+
+	return 0, "", false
+}
+
+// isGame returns true if name is a configured game executable.
+func (c *GameServerCollector) isGame(name string) bool {
+	name = strings.ToLower(name)
+	for _, game := range c.processes {
+		if name == game {
+			return true
+		}
+	}
+	return false
+}
+
+// processName returns the name of the process, or "" if it is gone.
+func processName(pid int32) string {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return ""
+	}
+	name, err := p.Name()
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// selectGameEndpoints returns the public IPv4 remote endpoints of conns,
+// ranked by likelihood of being the game server: connected UDP sockets
+// first, then established TCP connections, each by number of sockets.
+// Private and loopback addresses (LAN devices, Steam Link, local launchers)
+// are skipped, and so is IPv6, as the ICMP probe only supports IPv4.
+func selectGameEndpoints(conns []psnet.ConnectionStat, ignorePorts map[uint32]bool) []gameEndpoint {
+	byAddr := make(map[string]*gameEndpoint)
+	var endpoints []*gameEndpoint
+
+	for _, conn := range conns {
+		if conn.Raddr.IP == "" || conn.Raddr.Port == 0 || ignorePorts[conn.Raddr.Port] {
+			continue
+		}
+
+		ip := net.ParseIP(conn.Raddr.IP).To4()
+		if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+			ip.IsMulticast() || ip.IsLinkLocalUnicast() {
+			continue
+		}
+		addr := ip.String() // IPv4-mapped IPv6 addresses in plain form
+
+		var transport string
+		switch conn.Type {
+		case sockDgram:
+			transport = "udp"
+		case sockStream:
+			if conn.Status != "ESTABLISHED" {
+				continue
+			}
+			transport = "tcp"
+		default:
+			continue
+		}
+
+		key := transport + "/" + net.JoinHostPort(addr, itoa(int(conn.Raddr.Port)))
+		if ep, ok := byAddr[key]; ok {
+			ep.sockets++
+			continue
+		}
+		ep := &gameEndpoint{ip: addr, port: conn.Raddr.Port, transport: transport, sockets: 1}
+		byAddr[key] = ep
+		endpoints = append(endpoints, ep)
+	}
+
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		a, b := endpoints[i], endpoints[j]
+		if a.transport != b.transport {
+			return a.transport == "udp"
+		}
+		if a.sockets != b.sockets {
+			return a.sockets > b.sockets
+		}
+		return a.ip < b.ip
+	})
+
+	result := make([]gameEndpoint, len(endpoints))
+	for i, ep := range endpoints {
+		result[i] = *ep
+	}
+	return result
+}
+
+// probeGameEndpoint measures the latency to ep with ICMP echo, falling back
+// to a TCP connect for TCP endpoints. It returns the probe method used.
+func probeGameEndpoint(ep gameEndpoint, timeout time.Duration) (time.Duration, string, error) {
+	latency, err := probeICMP(ep.ip, timeout)
+	if err == nil || ep.transport != "tcp" {
+		return latency, string(ProbeICMP), err
+	}
+
+	latency, err = probeTCP(ep.ip, int(ep.port), timeout)
+	return latency, string(ProbeTCP), err
+}
+
+// GetInfo returns the detected game server, or nil if none.
+func (c *GameServerCollector) GetInfo() *models.GameServerInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.info == nil {
+		return nil
+	}
+	info := *c.info
+	return &info
+}
+
+// Shutdown stops the detection loop.
+func (c *GameServerCollector) Shutdown() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.initialized {
+		select {
+		case <-c.stopCh:
+		default:
+			close(c.stopCh)
+		}
+		c.initialized = false
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package collector
+
+// foregroundPID is not available without a Windows desktop; configured game
+// processes are used instead.
+func foregroundPID() (int32, bool) {
+	return 0, false
+}
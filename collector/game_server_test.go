@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	psnet "github.com/shirou/gopsutil/v3/net"
+)
+
+func gameConn(typ uint32, status, ip string, port uint32) psnet.ConnectionStat {
+	return psnet.ConnectionStat{
+		Type:   typ,
+		Status: status,
+		Laddr:  psnet.Addr{IP: "192.168.1.10", Port: 50000},
+		Raddr:  psnet.Addr{IP: ip, Port: port},
+	}
+}
+
+func TestSelectGameEndpoints(t *testing.T) {
+	conns := []psnet.ConnectionStat{
+		gameConn(sockStream, "ESTABLISHED", "23.1.2.3", 443),          // launcher HTTPS, ignored
+		gameConn(sockStream, "ESTABLISHED", "51.4.5.6", 27015),        // game TCP
+		gameConn(sockStream, "TIME_WAIT", "51.4.5.7", 27015),          // not established
+		gameConn(sockDgram, "NONE", "", 0),                            // unconnected UDP
+		gameConn(sockDgram, "NONE", "127.0.0.1", 9000),                // loopback
+		gameConn(sockDgram, "NONE", "155.133.1.1", 27020),             // match server
+		gameConn(sockDgram, "NONE", "155.133.1.1", 27020),             // second socket
+		gameConn(sockDgram, "NONE", "162.1.1.1", 3478),                // voice
+		gameConn(sockDgram, "NONE", "fe80::1", 5000),                  // link-local
+		gameConn(sockDgram, "NONE", "239.255.255.250", 1900),          // multicast
+		gameConn(sockDgram, "NONE", "192.168.1.20", 27031),            // Steam Link on the LAN
+		gameConn(sockStream, "ESTABLISHED", "10.0.0.5", 8080),         // local launcher
+		gameConn(sockDgram, "NONE", "2a01:4f8::1", 27020),             // IPv6, not probed
+		gameConn(sockStream, "ESTABLISHED", "::ffff:51.4.5.6", 27015), // mapped IPv4
+	}
+
+	endpoints := selectGameEndpoints(conns, map[uint32]bool{443: true})
+	if len(endpoints) != 3 {
+		t.Fatalf("Expected 3 endpoints, got %d: %+v", len(endpoints), endpoints)
+	}
+
+	want := []gameEndpoint{
+		{ip: "155.133.1.1", port: 27020, transport: "udp", sockets: 2},
+		{ip: "162.1.1.1", port: 3478, transport: "udp", sockets: 1},
+		{ip: "51.4.5.6", port: 27015, transport: "tcp", sockets: 2},
+	}
+	for i, ep := range endpoints {
+		if ep != want[i] {
+			t.Errorf("Endpoint %d: expected %+v, got %+v", i, want[i], ep)
+		}
+	}
+}
+
+func TestSelectGameEndpointsNone(t *testing.T) {
+	conns := []psnet.ConnectionStat{
+		gameConn(sockStream, "LISTEN", "0.0.0.0", 0),
+		gameConn(sockDgram, "NONE", "", 0),
+	}
+	if endpoints := selectGameEndpoints(conns, nil); len(endpoints) != 0 {
+		t.Errorf("Expected no endpoints, got %+v", endpoints)
+	}
+}
+
+func TestProbeGameEndpointTCPFallback(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	ep := gameEndpoint{ip: "127.0.0.1", port: uint32(listenerPort(t, ln.Addr())), transport: "tcp"}
+	latency, method, err := probeGameEndpoint(ep, time.Second)
+	if err != nil {
+		t.Fatalf("Expected endpoint to be reachable: %v", err)
+	}
+	if method != string(ProbeICMP) && method != string(ProbeTCP) {
+		t.Errorf("Unexpected probe method %q", method)
+	}
+	if latency <= 0 {
+		t.Error("Expected positive latency")
+	}
+}
@@ -0,0 +1,30 @@
+//go:build windows
+
+package collector
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	user32DLL                    = syscall.NewLazyDLL("user32.dll")
+	procGetForegroundWindow      = user32DLL.NewProc("GetForegroundWindow")
+	procGetWindowThreadProcessId = user32DLL.NewProc("GetWindowThreadProcessId")
+)
+
+// foregroundPID returns the process ID owning the foreground window.
+func foregroundPID() (int32, bool) {
+	hwnd, _, _ := procGetForegroundWindow.Call()
+	if hwnd == 0 {
+		return 0, false
+	}
+
+	var pid uint32
+	procGetWindowThreadProcessId.Call(hwnd, uintptr(unsafe.Pointer(&pid)))
+	if pid == 0 {
+		return 0, false
+	}
+
+	return int32(pid), true
+}
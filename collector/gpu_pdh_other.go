@@ -0,0 +1,35 @@
+//go:build !windows
+
+package collector
+
+import (
+	"fmt"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// PDHGPUCollector is unavailable outside Windows; GPU metrics are reported as
+// unavailable.
+type PDHGPUCollector struct {
+	// GPU info
+	gpuName     string
+	vramTotalMB uint64
+}
+
+// NewPDHGPUCollector creates a new PDH-based GPU collector.
+func NewPDHGPUCollector() *PDHGPUCollector {
+	return &PDHGPUCollector{}
+}
+
+// Init always fails, PDH is a Windows API.
+func (c *PDHGPUCollector) Init() error {
+	return fmt.Errorf("PDH GPU monitoring is only supported on Windows")
+}
+
+// Collect returns unavailable GPU metrics.
+func (c *PDHGPUCollector) Collect() models.GPUMetrics {
+	return models.GPUMetrics{Available: false}
+}
+
+// Shutdown does nothing.
+func (c *PDHGPUCollector) Shutdown() {}
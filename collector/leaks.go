@@ -0,0 +1,235 @@
+package collector
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// Defaults for the leak detection heuristics.
+const (
+	defaultLeakSampleInterval = time.Minute
+	defaultLeakWindow         = 3 * time.Hour
+	defaultLeakMinDuration    = 30 * time.Minute
+	defaultLeakMinGrowth      = 50 // MB per hour
+
+	// leakMinPoints is the fewest downsampled points a fit is made from.
+	leakMinPoints = 6
+	// leakMinTStat is the t-statistic of the slope needed to report a leak
+	// (about p < 0.01 for the minimum number of points).
+	leakMinTStat = 3.5
+	// leakMaxTStat caps the t-statistic of perfectly linear growth.
+	leakMaxTStat = 1000
+	// leakMinRising is the share of steps between points that must not
+	// decrease, so that a sawtooth with an upward trend is not a leak.
+	leakMinRising = 0.8
+	// leakNoiseMB is the decrease between points still counted as rising.
+	leakNoiseMB = 1
+)
+
+// leakPoint is the average memory of a process over one sample interval.
+type leakPoint struct {
+	at time.Time
+	mb float64
+}
+
+// leakKey identifies a process; the name guards against PID reuse.
+type leakKey struct {
+	pid  int32
+	name string
+}
+
+// leakSeries is the downsampled memory history of one process.
+type leakSeries struct {
+	points []leakPoint
+	sum    float64 // memory samples in the open interval
+	count  int
+}
+
+// LeakDetector keeps a downsampled memory history of every process, far
+// longer than the metrics history, and flags processes whose memory grows
+// steadily: the slope of a least-squares fit must be significant, reach the
+// minimum growth rate, and most steps must not decrease.
+type LeakDetector struct {
+	sampleInterval time.Duration
+	window         time.Duration
+	minDuration    time.Duration
+	minGrowth      float64
+
+	mu            sync.Mutex
+	intervalStart time.Time
+	series        map[leakKey]*leakSeries
+	leaks         []models.MemoryLeak
+}
+
+// NewLeakDetector creates a new memory leak detector.
+func NewLeakDetector(cfg *config.LeakDetectionConfig) *LeakDetector {
+	d := &LeakDetector{
+		sampleInterval: defaultLeakSampleInterval,
+		window:         defaultLeakWindow,
+		minDuration:    defaultLeakMinDuration,
+		minGrowth:      defaultLeakMinGrowth,
+		series:         make(map[leakKey]*leakSeries),
+	}
+
+	if cfg != nil {
+		if cfg.SampleInterval > 0 {
+			d.sampleInterval = cfg.SampleInterval
+		}
+		if cfg.Window > 0 {
+			d.window = cfg.Window
+		}
+		if cfg.MinDuration > 0 {
+			d.minDuration = cfg.MinDuration
+		}
+		if cfg.MinGrowthMBPerHour > 0 {
+			d.minGrowth = cfg.MinGrowthMBPerHour
+		}
+	}
+	return d
+}
+
+// Add records the memory of the processes. Once per sample interval the
+// averages become new points and the leaks are re-evaluated.
+func (d *LeakDetector) Add(processes []models.ProcessInfo, now time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.intervalStart.IsZero() {
+		d.intervalStart = now
+	}
+	for i := range processes {
+		p := &processes[i]
+		key := leakKey{p.PID, p.Name}
+		s := d.series[key]
+		if s == nil {
+			s = &leakSeries{}
+			d.series[key] = s
+		}
+		s.sum += float64(p.MemoryMB)
+		s.count++
+	}
+
+	if now.Sub(d.intervalStart) < d.sampleInterval {
+		return
+	}
+	d.intervalStart = now
+
+	cutoff := now.Add(-d.window)
+	var leaks []models.MemoryLeak
+	for key, s := range d.series {
+		// Not seen in this interval: exited or PID reused by another name
+		sampled := s.count > 0
+		if sampled {
+			s.points = append(s.points, leakPoint{at: now, mb: s.sum / float64(s.count)})
+			s.sum, s.count = 0, 0
+		}
+		drop := 0
+		for drop < len(s.points) && s.points[drop].at.Before(cutoff) {
+			drop++
+		}
+		s.points = append(s.points[:0], s.points[drop:]...)
+		if len(s.points) == 0 {
+			// Exited (or PID reused) longer than the window ago
+			delete(d.series, key)
+			continue
+		}
+
+		if !sampled {
+			continue
+		}
+		if leak, ok := d.detect(s.points); ok {
+			leak.Name = key.name
+			leak.PID = key.pid
+			leaks = append(leaks, leak)
+		}
+	}
+
+	sort.Slice(leaks, func(i, j int) bool {
+		return leaks[i].GrowthMBPerHour > leaks[j].GrowthMBPerHour
+	})
+	d.leaks = leaks
+}
+
+// Leaks returns the processes flagged at the last evaluation, fastest
+// growing first, with the time until RAM runs out projected from memory.
+func (d *LeakDetector) Leaks(memory *models.MemoryMetrics) []models.MemoryLeak {
+	d.mu.Lock()
+	leaks := append([]models.MemoryLeak(nil), d.leaks...)
+	d.mu.Unlock()
+
+	if memory.TotalMB == 0 {
+		return leaks
+	}
+	available := float64(memory.TotalMB) - float64(memory.UsedMB)
+	for i := range leaks {
+		leaks[i].ExhaustInSec = max(0, available) / leaks[i].GrowthMBPerHour * 3600
+	}
+	return leaks
+}
+
+// detect fits the points of one process and reports whether it leaks.
+func (d *LeakDetector) detect(points []leakPoint) (models.MemoryLeak, bool) {
+	n := len(points)
+	if n < leakMinPoints || points[n-1].at.Sub(points[0].at) < d.minDuration {
+		return models.MemoryLeak{}, false
+	}
+
+	rising := 0
+	for i := 1; i < n; i++ {
+		if points[i].mb >= points[i-1].mb-leakNoiseMB {
+			rising++
+		}
+	}
+	if float64(rising) < leakMinRising*float64(n-1) {
+		return models.MemoryLeak{}, false
+	}
+
+	slope, tStat := fitGrowth(points)
+	if slope < d.minGrowth || tStat < leakMinTStat {
+		return models.MemoryLeak{}, false
+	}
+	return models.MemoryLeak{
+		MemoryMB:        uint64(points[n-1].mb),
+		GrowthMBPerHour: slope,
+		TStat:           tStat,
+		Since:           points[0].at,
+	}, true
+}
+
+// fitGrowth returns the least-squares slope of the points in MB per hour
+// and its t-statistic (slope divided by its standard error).
+func fitGrowth(points []leakPoint) (slope, tStat float64) {
+	n := float64(len(points))
+	var meanX, meanY float64
+	for _, p := range points {
+		meanX += p.at.Sub(points[0].at).Hours()
+		meanY += p.mb
+	}
+	meanX /= n
+	meanY /= n
+
+	var sxx, sxy, syy float64
+	for _, p := range points {
+		dx := p.at.Sub(points[0].at).Hours() - meanX
+		dy := p.mb - meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, 0
+	}
+
+	slope = sxy / sxx
+	residual := max(0, syy-slope*sxy)
+	stdErr := math.Sqrt(residual / (n - 2) / sxx)
+	if stdErr == 0 {
+		return slope, math.Copysign(leakMaxTStat, slope)
+	}
+	return slope, min(leakMaxTStat, slope/stdErr)
+}
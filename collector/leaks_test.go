@@ -0,0 +1,125 @@
+package collector
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+func newTestLeakDetector() *LeakDetector {
+	return NewLeakDetector(&config.LeakDetectionConfig{
+		SampleInterval:     time.Minute,
+		Window:             2 * time.Hour,
+		MinDuration:        30 * time.Minute,
+		MinGrowthMBPerHour: 50,
+	})
+}
+
+// feedLeakDetector adds one sample every 10 s between the offsets from the
+// start; memory returns the usage of each process at an offset.
+func feedLeakDetector(d *LeakDetector, start time.Time, from, to time.Duration, memory func(offset time.Duration) []models.ProcessInfo) {
+	for offset := from; offset <= to; offset += 10 * time.Second {
+		d.Add(memory(offset), start.Add(offset))
+	}
+}
+
+func TestLeakDetector(t *testing.T) {
+	d := newTestLeakDetector()
+	start := time.Now()
+
+	memory := func(offset time.Duration) []models.ProcessInfo {
+		minutes := offset.Minutes()
+		// Stable with noise, leaking 120 MB/h with a sawtooth from GC, and
+		// growing fast but then freeing everything
+		stable := 800 + 20*math.Sin(minutes)
+		leak := 300 + 2*minutes + math.Mod(minutes, 10)
+		cache := 200 + 10*math.Mod(minutes, 20)
+		return []models.ProcessInfo{
+			{PID: 10, Name: "game.exe", MemoryMB: uint64(stable)},
+			{PID: 20, Name: "launcher.exe", MemoryMB: uint64(leak)},
+			{PID: 30, Name: "cache.exe", MemoryMB: uint64(cache)},
+		}
+	}
+
+	// Not reported before the minimum duration
+	feedLeakDetector(d, start, 0, 20*time.Minute, memory)
+	if leaks := d.Leaks(&models.MemoryMetrics{}); len(leaks) != 0 {
+		t.Fatalf("Expected no leaks before min_duration, got %+v", leaks)
+	}
+
+	feedLeakDetector(d, start, 20*time.Minute+10*time.Second, 45*time.Minute, memory)
+	leaks := d.Leaks(&models.MemoryMetrics{TotalMB: 16384, UsedMB: 12288})
+	if len(leaks) != 1 || leaks[0].Name != "launcher.exe" || leaks[0].PID != 20 {
+		t.Fatalf("Expected only the launcher to leak, got %+v", leaks)
+	}
+
+	leak := leaks[0]
+	if leak.GrowthMBPerHour < 110 || leak.GrowthMBPerHour > 130 {
+		t.Errorf("Expected about 120 MB/h, got %.1f", leak.GrowthMBPerHour)
+	}
+	if leak.TStat < leakMinTStat || leak.MemoryMB < 380 {
+		t.Errorf("Unexpected leak: %+v", leak)
+	}
+	// 4 GB free at 120 MB/h
+	if hours := leak.ExhaustInSec / 3600; hours < 31 || hours > 37 {
+		t.Errorf("Expected about 34 h until RAM runs out, got %.1f h", hours)
+	}
+}
+
+func TestLeakDetectorSlowGrowth(t *testing.T) {
+	d := newTestLeakDetector()
+
+	// Steady, but below the minimum growth rate
+	feedLeakDetector(d, time.Now(), 0, time.Hour, func(offset time.Duration) []models.ProcessInfo {
+		return []models.ProcessInfo{{PID: 1, Name: "slow.exe", MemoryMB: 100 + uint64(offset.Minutes()/2)}}
+	})
+	if leaks := d.Leaks(&models.MemoryMetrics{}); len(leaks) != 0 {
+		t.Errorf("Expected slow growth to be ignored, got %+v", leaks)
+	}
+}
+
+func TestLeakDetectorWindow(t *testing.T) {
+	d := newTestLeakDetector()
+	start := time.Now()
+
+	feedLeakDetector(d, start, 0, time.Hour, func(offset time.Duration) []models.ProcessInfo {
+		return []models.ProcessInfo{{PID: 1, Name: "tab.exe", MemoryMB: 100 + uint64(offset.Minutes()*3)}}
+	})
+	if leaks := d.Leaks(&models.MemoryMetrics{}); len(leaks) != 1 {
+		t.Fatalf("Expected a leak, got %+v", leaks)
+	}
+
+	// The process exits: no longer reported, but its history is kept
+	exited := func(time.Duration) []models.ProcessInfo { return nil }
+	feedLeakDetector(d, start, time.Hour+10*time.Second, time.Hour+2*time.Minute, exited)
+	if leaks := d.Leaks(&models.MemoryMetrics{}); len(leaks) != 0 || len(d.series) != 1 {
+		t.Fatalf("Expected the exited process not to be reported, got %+v", leaks)
+	}
+
+	// Its history leaves the window
+	feedLeakDetector(d, start, time.Hour+2*time.Minute+10*time.Second, 3*time.Hour+2*time.Minute, exited)
+	if leaks := d.Leaks(&models.MemoryMetrics{}); len(leaks) != 0 || len(d.series) != 0 {
+		t.Errorf("Expected the exited process to be forgotten, got %+v", leaks)
+	}
+}
+
+func TestFitGrowth(t *testing.T) {
+	start := time.Now()
+	var points []leakPoint
+	for i := 0; i < 10; i++ {
+		points = append(points, leakPoint{at: start.Add(time.Duration(i) * 6 * time.Minute), mb: 500 + float64(i)*10})
+	}
+
+	slope, tStat := fitGrowth(points)
+	if math.Abs(slope-100) > 1e-9 || tStat != leakMaxTStat {
+		t.Errorf("Expected 100 MB/h exactly, got %.3f (t=%.1f)", slope, tStat)
+	}
+
+	points[5].mb -= 30
+	if _, tStat = fitGrowth(points); tStat >= leakMaxTStat || tStat < leakMinTStat {
+		t.Errorf("Expected a finite significant t-statistic, got %.1f", tStat)
+	}
+}
@@ -35,6 +35,15 @@ func (c *NetworkCollector) Init() {
 	}
 }
 
+// Reset discards the previous counters so that the next rates are not
+// computed across a collection gap.
+func (c *NetworkCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastCounters = nil
+	c.initialized = false
+}
+
 // Collect gathers current network metrics.
 func (c *NetworkCollector) Collect() models.NetworkMetrics {
 	metrics := models.NetworkMetrics{
@@ -1,30 +1,72 @@
-//go:build windows
-
 package collector
 
 import (
+	"bytes"
+	"fmt"
 	"net"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/NaveLIL/erez-monitor/config"
 	"github.com/NaveLIL/erez-monitor/logger"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// ProbeProtocol is the method used to measure latency to a target.
+type ProbeProtocol string
+
+const (
+	// ProbeTCP measures the time to establish a TCP connection.
+	ProbeTCP ProbeProtocol = "tcp"
+	// ProbeUDP sends a datagram and waits for it to be echoed back.
+	ProbeUDP ProbeProtocol = "udp"
+	// ProbeICMP sends an ICMP echo request without admin rights
+	// (unprivileged datagram socket on Linux, IcmpSendEcho on Windows).
+	ProbeICMP ProbeProtocol = "icmp"
+	// ProbeHTTP performs an HTTP(S) health check and measures the time until
+	// the response headers.
+	ProbeHTTP ProbeProtocol = "http"
+	// ProbeDNS measures the time to resolve Host, using the system resolver
+	// or a specific DNS server.
+	ProbeDNS ProbeProtocol = "dns"
+)
+
+const (
+	defaultPingInterval = 3 * time.Second
+	defaultPingTimeout  = 2 * time.Second
 )
 
 // PingTarget represents a server to ping.
 type PingTarget struct {
-	Name    string // Display name (e.g., "Cloudflare", "Google")
-	Host    string // Host to ping (IP or domain)
-	Port    int    // TCP port to connect to
-	Enabled bool   // Whether this target is enabled
+	Name     string        // Display name (e.g., "Cloudflare", "Google")
+	Host     string        // Host to ping (IP or domain)
+	Port     int           // Port for TCP, UDP and HTTP probes
+	Protocol ProbeProtocol // Probe type (defaults to TCP)
+	URL      string        // URL for HTTP probes (overrides Host and Port)
+	Server   string        // DNS server for DNS probes (empty = system resolver)
+	Timeout  time.Duration // Per-target timeout (0 = collector default)
+	Enabled  bool          // Whether this target is enabled
+
+	// HTTP health check expectations
+	ExpectStatus []int  // Accepted status codes (empty = any)
+	BodyContains string // Required body substring (empty = not checked)
 }
 
 // PingResult represents the result of a ping.
 type PingResult struct {
 	Name      string        // Target name
 	Host      string        // Target host
+	Protocol  ProbeProtocol // Probe type used
 	Latency   time.Duration // Round-trip latency
 	Available bool          // Whether the host is reachable
+	Status    string        // Probe status (ok, timeout, nxdomain, error)
+	Error     string        // Probe error if the host is not reachable
 	LastCheck time.Time     // When was the last check
+
+	// HTTP contains status, timings and certificate info of HTTP probes
+	HTTP *models.HTTPCheckInfo
 }
 
 // PingCollector measures network latency to various servers.
@@ -34,12 +76,21 @@ type PingCollector struct {
 	initialized bool
 	stopCh      chan struct{}
 
-	// Ping targets
-	targets []PingTarget
+	// Ping targets and timing
+	targets  []PingTarget
+	interval time.Duration
+	timeout  time.Duration
+
+	// HTTP client for health checks (no keep-alive, so every probe connects)
+	httpClient *http.Client
 
 	// Cached results
 	results map[string]*PingResult
 
+	// Sliding window of samples per target, for statistics
+	samples map[string][]pingSample
+	window  int
+
 	// Best (lowest) latency result
 	bestLatency time.Duration
 	bestTarget  string
@@ -49,22 +100,75 @@ type PingCollector struct {
 func DefaultPingTargets() []PingTarget {
 	return []PingTarget{
 		// DNS servers (always available, fast response)
-		{Name: "Cloudflare", Host: "1.1.1.1", Port: 443, Enabled: true},
-		{Name: "Google", Host: "8.8.8.8", Port: 443, Enabled: true},
+		{Name: "Cloudflare", Host: "1.1.1.1", Port: 443, Protocol: ProbeTCP, Enabled: true},
+		{Name: "Google", Host: "8.8.8.8", Port: 443, Protocol: ProbeTCP, Enabled: true},
 		// Gaming platforms - EU servers
-		{Name: "Steam EU", Host: "155.133.248.34", Port: 443, Enabled: true},
-		{Name: "Riot EU", Host: "185.40.64.65", Port: 443, Enabled: true},
+		{Name: "Steam EU", Host: "155.133.248.34", Port: 443, Protocol: ProbeTCP, Enabled: true},
+		{Name: "Riot EU", Host: "185.40.64.65", Port: 443, Protocol: ProbeTCP, Enabled: true},
+	}
+}
+
+// PingTargetsFromConfig converts configured targets to ping targets.
+func PingTargetsFromConfig(targets []config.PingTargetConfig) []PingTarget {
+	result := make([]PingTarget, 0, len(targets))
+	for _, t := range targets {
+		result = append(result, PingTarget{
+			Name:     t.Name,
+			Host:     t.Host,
+			Port:     t.Port,
+			Protocol: ProbeProtocol(strings.ToLower(t.Protocol)),
+			URL:      t.URL,
+			Server:   t.Server,
+			Timeout:  t.Timeout,
+			Enabled:  !t.Disabled,
+
+			ExpectStatus: t.ExpectStatus,
+			BodyContains: t.BodyContains,
+		})
 	}
+	return result
 }
 
 // NewPingCollector creates a new ping collector.
-func NewPingCollector() *PingCollector {
-	return &PingCollector{
-		log:     logger.Get(),
-		targets: DefaultPingTargets(),
-		results: make(map[string]*PingResult),
-		stopCh:  make(chan struct{}),
+// If cfg is nil or has no targets, DefaultPingTargets are used.
+func NewPingCollector(cfg *config.PingConfig) *PingCollector {
+	c := &PingCollector{
+		log:      logger.Get(),
+		targets:  DefaultPingTargets(),
+		interval: defaultPingInterval,
+		timeout:  defaultPingTimeout,
+		results:  make(map[string]*PingResult),
+		samples:  make(map[string][]pingSample),
+		window:   defaultPingWindow,
+		stopCh:   make(chan struct{}),
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:             http.ProxyFromEnvironment,
+				DisableKeepAlives: true,
+			},
+			// Latency is measured to the first response, never follow redirects
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+
+	if cfg != nil {
+		if len(cfg.Targets) > 0 {
+			c.targets = PingTargetsFromConfig(cfg.Targets)
+		}
+		if cfg.Interval > 0 {
+			c.interval = cfg.Interval
+		}
+		if cfg.Timeout > 0 {
+			c.timeout = cfg.Timeout
+		}
+		if cfg.Window > 0 {
+			c.window = cfg.Window
+		}
 	}
+
+	return c
 }
 
 // Init initializes the ping collector.
@@ -90,7 +194,7 @@ func (c *PingCollector) backgroundPing() {
 	// Initial ping
 	c.pingAll()
 
-	ticker := time.NewTicker(3 * time.Second)
+	ticker := time.NewTicker(c.interval)
 	defer ticker.Stop()
 
 	for {
@@ -134,46 +238,64 @@ func (c *PingCollector) pingAll() {
 
 	// Collect results
 	newResults := make(map[string]*PingResult)
-	var bestLatency time.Duration = time.Hour
-	var bestTarget string
-
 	for result := range resultsCh {
 		newResults[result.Name] = result
-		if result.Available && result.Latency < bestLatency {
-			bestLatency = result.Latency
-			bestTarget = result.Name
-		}
 	}
+	bestLatency, bestTarget := bestRoundTrip(newResults)
 
 	// Update cached results
 	c.mu.Lock()
 	c.results = newResults
+	c.recordSamples(newResults)
 	c.bestLatency = bestLatency
 	c.bestTarget = bestTarget
 	c.mu.Unlock()
 }
 
-// pingTarget pings a single target using TCP connection.
+// pingTarget pings a single target using its probe protocol.
 func (c *PingCollector) pingTarget(target PingTarget) *PingResult {
+	protocol := target.Protocol
+	if protocol == "" {
+		protocol = ProbeTCP
+	}
+
 	result := &PingResult{
 		Name:      target.Name,
 		Host:      target.Host,
+		Protocol:  protocol,
 		LastCheck: time.Now(),
 	}
 
-	// Use TCP connection to measure latency (works without admin rights)
-	addr := net.JoinHostPort(target.Host, itoa(target.Port))
+	timeout := target.Timeout
+	if timeout <= 0 {
+		timeout = c.timeout
+	}
 
-	start := time.Now()
-	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
-	latency := time.Since(start)
+	var latency time.Duration
+	var err error
+
+	switch protocol {
+	case ProbeTCP:
+		latency, err = probeTCP(target.Host, portOrDefault(target.Port, 443), timeout)
+	case ProbeUDP:
+		latency, err = probeUDP(target.Host, portOrDefault(target.Port, 7), timeout)
+	case ProbeICMP:
+		latency, err = probeICMP(target.Host, timeout)
+	case ProbeHTTP:
+		latency, result.HTTP, err = c.probeHTTP(target, timeout)
+	case ProbeDNS:
+		latency, err = probeDNS(target.Host, target.Server, timeout)
+	default:
+		err = fmt.Errorf("unknown probe protocol: %s", protocol)
+	}
 
+	result.Status = probeStatus(err)
 	if err != nil {
 		result.Available = false
 		result.Latency = 0
+		result.Error = err.Error()
 		return result
 	}
-	conn.Close()
 
 	result.Available = true
 	result.Latency = latency
@@ -181,7 +303,105 @@ func (c *PingCollector) pingTarget(target PingTarget) *PingResult {
 	return result
 }
 
-// GetBestLatency returns the best (lowest) latency and target name.
+// probeTCP measures the time to establish a TCP connection
+// (works without admin rights).
+func probeTCP(host string, port int, timeout time.Duration) (time.Duration, error) {
+	addr := net.JoinHostPort(host, itoa(port))
+
+	start := time.Now()
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	latency := time.Since(start)
+
+	if err != nil {
+		return 0, err
+	}
+	conn.Close()
+
+	return latency, nil
+}
+
+// probeUDP sends a datagram to a UDP echo service and waits for the same
+// payload to come back.
+func probeUDP(host string, port int, timeout time.Duration) (time.Duration, error) {
+	addr := net.JoinHostPort(host, itoa(port))
+
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	payload := []byte("erez-monitor-ping " + itoa(int(time.Now().UnixNano()%1e9)))
+	buf := make([]byte, 512)
+
+	start := time.Now()
+	if err := conn.SetDeadline(start.Add(timeout)); err != nil {
+		return 0, err
+	}
+	if _, err := conn.Write(payload); err != nil {
+		return 0, err
+	}
+
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return 0, err
+		}
+		// Ignore stray datagrams that are not our echo
+		if bytes.Equal(buf[:n], payload) {
+			return time.Since(start), nil
+		}
+	}
+}
+
+// httpTargetURL returns the URL probed for an HTTP target.
+func httpTargetURL(target PingTarget) string {
+	if target.URL != "" {
+		return target.URL
+	}
+
+	port := portOrDefault(target.Port, 443)
+	scheme := "https"
+	if port != 443 {
+		scheme = "http"
+	}
+	return scheme + "://" + net.JoinHostPort(target.Host, itoa(port)) + "/"
+}
+
+// portOrDefault returns port, or def if port is not set.
+func portOrDefault(port, def int) int {
+	if port <= 0 {
+		return def
+	}
+	return port
+}
+
+// bestRoundTrip returns the lowest latency of the available TCP, UDP and
+// ICMP results and the target name. DNS lookups (often answered from a
+// cache) and HTTP requests do not measure the network round trip. Without
+// such a result the latency is one hour and the name is empty.
+func bestRoundTrip(results map[string]*PingResult) (time.Duration, string) {
+	best := time.Hour
+	var name string
+	for _, result := range results {
+		switch result.Protocol {
+		case ProbeTCP, ProbeUDP, ProbeICMP:
+		default:
+			continue
+		}
+		if !result.Available {
+			continue
+		}
+		if result.Latency < best || (result.Latency == best && result.Name < name) {
+			best = result.Latency
+			name = result.Name
+		}
+	}
+	return best, name
+}
+
+// GetBestLatency returns the best (lowest) round-trip latency of the TCP,
+// UDP and ICMP targets and the target name.
 func (c *PingCollector) GetBestLatency() (time.Duration, string) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -0,0 +1,67 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+// Probe statuses reported in PingResult.Status.
+const (
+	ProbeStatusOK       = "ok"
+	ProbeStatusTimeout  = "timeout"
+	ProbeStatusNXDomain = "nxdomain"
+	ProbeStatusError    = "error"
+)
+
+// probeDNS measures the time to resolve hostname. If server is empty the
+// system resolver is used (which may answer from the OS cache); otherwise
+// the query is sent directly to server ("host" or "host:port").
+func probeDNS(hostname, server string, timeout time.Duration) (time.Duration, error) {
+	resolver := net.DefaultResolver
+	if server != "" {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	_, err := resolver.LookupHost(ctx, hostname)
+	latency := time.Since(start)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return latency, nil
+}
+
+// probeStatus classifies a probe error (nil means success).
+func probeStatus(err error) string {
+	if err == nil {
+		return ProbeStatusOK
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return ProbeStatusNXDomain
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ProbeStatusTimeout
+	}
+
+	return ProbeStatusError
+}
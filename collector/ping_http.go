@@ -0,0 +1,153 @@
+package collector
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// maxHealthBodyBytes limits how much of a response body is searched for
+// the expected substring.
+const maxHealthBodyBytes = 1 << 20
+
+// httpTrace records the phase timings of a single HTTP request.
+type httpTrace struct {
+	mu           sync.Mutex
+	start        time.Time
+	dnsStart     time.Time
+	dnsDone      time.Time
+	connectStart time.Time
+	connectDone  time.Time
+	tlsStart     time.Time
+	tlsDone      time.Time
+	firstByte    time.Time
+}
+
+func (t *httpTrace) set(field *time.Time) {
+	t.mu.Lock()
+	if field.IsZero() {
+		*field = time.Now()
+	}
+	t.mu.Unlock()
+}
+
+func (t *httpTrace) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart:             func(httptrace.DNSStartInfo) { t.set(&t.dnsStart) },
+		DNSDone:              func(httptrace.DNSDoneInfo) { t.set(&t.dnsDone) },
+		ConnectStart:         func(string, string) { t.set(&t.connectStart) },
+		ConnectDone:          func(string, string, error) { t.set(&t.connectDone) },
+		TLSHandshakeStart:    func() { t.set(&t.tlsStart) },
+		TLSHandshakeDone:     func(tls.ConnectionState, error) { t.set(&t.tlsDone) },
+		GotFirstResponseByte: func() { t.set(&t.firstByte) },
+	}
+}
+
+// fill copies the recorded phase durations into info.
+func (t *httpTrace) fill(info *models.HTTPCheckInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info.DNSMs = phaseMs(t.dnsStart, t.dnsDone)
+	info.ConnectMs = phaseMs(t.connectStart, t.connectDone)
+	info.TLSMs = phaseMs(t.tlsStart, t.tlsDone)
+	info.TTFBMs = phaseMs(t.start, t.firstByte)
+}
+
+// phaseMs returns the duration between start and end in milliseconds,
+// or 0 if the phase did not happen (e.g. no DNS lookup for an IP address).
+func phaseMs(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return durationMs(end.Sub(start))
+}
+
+// probeHTTP performs an HTTP health check and returns the time until the
+// response headers. A HEAD request is used unless the body must be searched.
+// The returned info is filled in as far as the check got, even on error.
+func (c *PingCollector) probeHTTP(target PingTarget, timeout time.Duration) (time.Duration, *models.HTTPCheckInfo, error) {
+	method := http.MethodHead
+	if target.BodyContains != "" {
+		method = http.MethodGet
+	}
+
+	req, err := http.NewRequest(method, httpTargetURL(target), nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("User-Agent", "EREZMonitor")
+
+	trace := &httpTrace{}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace.clientTrace()))
+
+	client := *c.httpClient
+	client.Timeout = timeout
+
+	info := &models.HTTPCheckInfo{}
+
+	trace.start = time.Now()
+	resp, err := client.Do(req)
+	latency := time.Since(trace.start)
+	trace.fill(info)
+
+	if err != nil {
+		return 0, info, err
+	}
+	defer resp.Body.Close()
+
+	info.StatusCode = resp.StatusCode
+	if resp.TLS != nil && len(resp.TLS.PeerCertificates) > 0 {
+		notAfter := resp.TLS.PeerCertificates[0].NotAfter
+		info.CertExpiry = notAfter
+		info.CertDaysLeft = time.Until(notAfter).Hours() / 24
+	}
+
+	if !statusExpected(resp.StatusCode, target.ExpectStatus) {
+		return 0, info, fmt.Errorf("unexpected status %d (expected %s)",
+			resp.StatusCode, formatStatuses(target.ExpectStatus))
+	}
+
+	if target.BodyContains != "" {
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxHealthBodyBytes))
+		if err != nil {
+			return 0, info, fmt.Errorf("failed to read body: %w", err)
+		}
+		if !strings.Contains(string(body), target.BodyContains) {
+			return 0, info, fmt.Errorf("body does not contain %q", target.BodyContains)
+		}
+		info.BodyMatched = true
+	}
+
+	return latency, info, nil
+}
+
+// statusExpected reports whether code is one of the expected status codes.
+// Any status is accepted if no codes are configured.
+func statusExpected(code int, expected []int) bool {
+	if len(expected) == 0 {
+		return true
+	}
+	for _, e := range expected {
+		if code == e {
+			return true
+		}
+	}
+	return false
+}
+
+// formatStatuses formats a list of status codes for error messages.
+func formatStatuses(codes []int) string {
+	parts := make([]string, len(codes))
+	for i, code := range codes {
+		parts[i] = itoa(code)
+	}
+	return strings.Join(parts, ", ")
+}
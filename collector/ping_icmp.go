@@ -0,0 +1,34 @@
+package collector
+
+// icmpEchoRequest builds an ICMP echo request message (type 8, code 0).
+func icmpEchoRequest(id, seq uint16, payload []byte) []byte {
+	msg := make([]byte, 8+len(payload))
+	msg[0] = 8 // Echo request
+	msg[1] = 0 // Code
+	msg[4] = byte(id >> 8)
+	msg[5] = byte(id)
+	msg[6] = byte(seq >> 8)
+	msg[7] = byte(seq)
+	copy(msg[8:], payload)
+
+	checksum := icmpChecksum(msg)
+	msg[2] = byte(checksum >> 8)
+	msg[3] = byte(checksum)
+
+	return msg
+}
+
+// icmpChecksum computes the Internet checksum (RFC 1071) of b.
+func icmpChecksum(b []byte) uint16 {
+	var sum uint32
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	for sum>>16 != 0 {
+		sum = (sum >> 16) + (sum & 0xffff)
+	}
+	return ^uint16(sum)
+}
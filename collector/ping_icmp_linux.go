@@ -0,0 +1,74 @@
+//go:build linux
+
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// probeICMP sends an ICMP echo request over an unprivileged datagram socket.
+// Requires the user's group to be within net.ipv4.ping_group_range.
+func probeICMP(host string, timeout time.Duration) (time.Duration, error) {
+	ipAddr, err := net.ResolveIPAddr("ip4", host)
+	if err != nil {
+		return 0, err
+	}
+	ip4 := ipAddr.IP.To4()
+	if ip4 == nil {
+		return 0, fmt.Errorf("icmp: %s is not an IPv4 address", host)
+	}
+
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, syscall.IPPROTO_ICMP)
+	if err != nil {
+		return 0, fmt.Errorf("icmp socket: %w", err)
+	}
+	defer syscall.Close(fd)
+
+	// The kernel rewrites the identifier to the socket's local port,
+	// so replies are matched by sequence number and payload only.
+	seq := uint16(os.Getpid() ^ int(time.Now().UnixNano()))
+	request := icmpEchoRequest(0, seq, []byte("erez-monitor"))
+
+	addr := &syscall.SockaddrInet4{}
+	copy(addr.Addr[:], ip4)
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+
+	if err := syscall.Sendto(fd, request, 0, addr); err != nil {
+		return 0, fmt.Errorf("icmp send: %w", err)
+	}
+
+	buf := make([]byte, 1500)
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return 0, fmt.Errorf("icmp: %w", os.ErrDeadlineExceeded)
+		}
+		tv := syscall.NsecToTimeval(remaining.Nanoseconds())
+		if err := syscall.SetsockoptTimeval(fd, syscall.SOL_SOCKET, syscall.SO_RCVTIMEO, &tv); err != nil {
+			return 0, err
+		}
+
+		n, _, err := syscall.Recvfrom(fd, buf, 0)
+		if err != nil {
+			if errors.Is(err, syscall.EINTR) {
+				continue
+			}
+			if errors.Is(err, syscall.EAGAIN) {
+				return 0, fmt.Errorf("icmp: %w", os.ErrDeadlineExceeded)
+			}
+			return 0, fmt.Errorf("icmp receive: %w", err)
+		}
+
+		// Echo reply: type 0, matching sequence number
+		if n >= 8 && buf[0] == 0 && uint16(buf[6])<<8|uint16(buf[7]) == seq {
+			return time.Since(start), nil
+		}
+	}
+}
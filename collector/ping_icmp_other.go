@@ -0,0 +1,13 @@
+//go:build !linux && !windows
+
+package collector
+
+import (
+	"fmt"
+	"time"
+)
+
+// probeICMP is not supported on this platform.
+func probeICMP(host string, timeout time.Duration) (time.Duration, error) {
+	return 0, fmt.Errorf("icmp probes are not supported on this platform")
+}
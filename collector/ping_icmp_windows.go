@@ -0,0 +1,89 @@
+//go:build windows
+
+package collector
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	iphlpapi            = syscall.NewLazyDLL("iphlpapi.dll")
+	procIcmpCreateFile  = iphlpapi.NewProc("IcmpCreateFile")
+	procIcmpCloseHandle = iphlpapi.NewProc("IcmpCloseHandle")
+	procIcmpSendEcho    = iphlpapi.NewProc("IcmpSendEcho")
+)
+
+// ICMP_ECHO_REPLY statuses (also the last error when no reply arrived).
+const (
+	ipSuccess     = 0     // IP_SUCCESS
+	ipReqTimedOut = 11010 // IP_REQ_TIMED_OUT
+)
+
+// icmpEchoReply is the ICMP_ECHO_REPLY structure (64-bit layout, only the
+// leading fields are read).
+type icmpEchoReply struct {
+	Address       uint32
+	Status        uint32
+	RoundTripTime uint32
+	DataSize      uint16
+	Reserved      uint16
+	Data          uintptr
+	Options       [16]byte // IP_OPTION_INFORMATION
+}
+
+// probeICMP sends an ICMP echo request via IcmpSendEcho (works without admin rights).
+func probeICMP(host string, timeout time.Duration) (time.Duration, error) {
+	ipAddr, err := net.ResolveIPAddr("ip4", host)
+	if err != nil {
+		return 0, err
+	}
+	ip4 := ipAddr.IP.To4()
+	if ip4 == nil {
+		return 0, fmt.Errorf("icmp: %s is not an IPv4 address", host)
+	}
+
+	handle, _, _ := procIcmpCreateFile.Call()
+	if handle == 0 || handle == uintptr(syscall.InvalidHandle) {
+		return 0, fmt.Errorf("icmp: IcmpCreateFile failed")
+	}
+	defer procIcmpCloseHandle.Call(handle)
+
+	payload := []byte("erez-monitor")
+	reply := make([]byte, unsafe.Sizeof(icmpEchoReply{})+uintptr(len(payload))+8)
+
+	start := time.Now()
+	n, _, callErr := procIcmpSendEcho.Call(
+		handle,
+		uintptr(binary.LittleEndian.Uint32(ip4)), // IPAddr is in network byte order
+		uintptr(unsafe.Pointer(&payload[0])),
+		uintptr(len(payload)),
+		0,
+		uintptr(unsafe.Pointer(&reply[0])),
+		uintptr(len(reply)),
+		uintptr(timeout.Milliseconds()),
+	)
+	latency := time.Since(start)
+
+	if n == 0 {
+		if errno, ok := callErr.(syscall.Errno); ok && errno == ipReqTimedOut {
+			return 0, fmt.Errorf("icmp: no reply from %s: %w", host, os.ErrDeadlineExceeded)
+		}
+		return 0, fmt.Errorf("icmp: no reply from %s", host)
+	}
+
+	echo := (*icmpEchoReply)(unsafe.Pointer(&reply[0]))
+	if echo.Status == ipReqTimedOut {
+		return 0, fmt.Errorf("icmp: no reply from %s: %w", host, os.ErrDeadlineExceeded)
+	}
+	if echo.Status != ipSuccess {
+		return 0, fmt.Errorf("icmp: echo failed with status %d", echo.Status)
+	}
+
+	return latency, nil
+}
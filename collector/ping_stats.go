@@ -0,0 +1,162 @@
+package collector
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// defaultPingWindow is the number of probe samples kept per target.
+const defaultPingWindow = 20
+
+// pingSample is a single probe outcome kept in the sliding window.
+type pingSample struct {
+	latency   time.Duration
+	available bool
+	status    string
+}
+
+// recordSamples appends the results to the per-target sliding windows.
+// Windows of targets that are no longer probed are dropped.
+func (c *PingCollector) recordSamples(results map[string]*PingResult) {
+	for name := range c.samples {
+		if _, ok := results[name]; !ok {
+			delete(c.samples, name)
+		}
+	}
+
+	for name, result := range results {
+		window := append(c.samples[name], pingSample{
+			latency:   result.Latency,
+			available: result.Available,
+			status:    result.Status,
+		})
+		if len(window) > c.window {
+			window = window[len(window)-c.window:]
+		}
+		c.samples[name] = window
+	}
+}
+
+// GetStats returns latency statistics over the sliding window for every
+// enabled target, in target order.
+func (c *PingCollector) GetStats() []models.PingTargetStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := make([]models.PingTargetStats, 0, len(c.targets))
+	for _, target := range c.targets {
+		result, ok := c.results[target.Name]
+		if !ok {
+			continue
+		}
+
+		s := computePingStats(c.samples[target.Name])
+		s.Name = result.Name
+		s.Host = result.Host
+		s.Protocol = string(result.Protocol)
+		s.Server = target.Server
+		s.Available = result.Available
+		s.Status = result.Status
+		s.Error = result.Error
+		if result.HTTP != nil {
+			http := *result.HTTP
+			s.HTTP = &http
+		}
+		if result.Available {
+			s.LastMs = durationMs(result.Latency)
+		}
+		stats = append(stats, s)
+	}
+
+	return stats
+}
+
+// computePingStats computes loss, jitter and latency percentiles of a window.
+// NXDOMAIN answers are counted separately and not as lost probes.
+// Jitter is the mean absolute difference between consecutive successful samples.
+func computePingStats(samples []pingSample) models.PingTargetStats {
+	stats := models.PingTargetStats{Samples: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+
+	latencies := make([]float64, 0, len(samples))
+	var jitterSum float64
+	var jitterCount int
+	var lost int
+
+	for _, sample := range samples {
+		switch sample.status {
+		case ProbeStatusTimeout:
+			stats.TimeoutCount++
+		case ProbeStatusNXDomain:
+			// The resolver answered, the name does not exist: not a loss
+			stats.NXDomainCount++
+			continue
+		}
+
+		if !sample.available {
+			lost++
+			continue
+		}
+
+		ms := durationMs(sample.latency)
+		if len(latencies) > 0 {
+			jitterSum += math.Abs(ms - latencies[len(latencies)-1])
+			jitterCount++
+		}
+		latencies = append(latencies, ms)
+	}
+
+	stats.LossPercent = float64(lost) / float64(len(samples)) * 100
+	if jitterCount > 0 {
+		stats.JitterMs = jitterSum / float64(jitterCount)
+	}
+
+	if len(latencies) == 0 {
+		return stats
+	}
+
+	var sum float64
+	for _, ms := range latencies {
+		sum += ms
+	}
+	stats.AvgMs = sum / float64(len(latencies))
+
+	sort.Float64s(latencies)
+	stats.MinMs = latencies[0]
+	stats.MaxMs = latencies[len(latencies)-1]
+	stats.P50Ms = percentile(latencies, 50)
+	stats.P95Ms = percentile(latencies, 95)
+
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted values using linear
+// interpolation between closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+
+	frac := rank - float64(lower)
+	return sorted[lower] + (sorted[upper]-sorted[lower])*frac
+}
+
+// durationMs converts a duration to fractional milliseconds.
+func durationMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,524 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+)
+
+func TestProbeTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer ln.Close()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	c := NewPingCollector(nil)
+	result := c.pingTarget(PingTarget{Name: "local", Host: "127.0.0.1", Port: listenerPort(t, ln.Addr()), Protocol: ProbeTCP})
+	if !result.Available {
+		t.Fatalf("Expected TCP target to be available, got error %q", result.Error)
+	}
+	if result.Latency <= 0 {
+		t.Errorf("Expected positive latency, got %v", result.Latency)
+	}
+
+	// Closed port must be reported as unavailable
+	port := listenerPort(t, ln.Addr())
+	ln.Close()
+	result = c.pingTarget(PingTarget{Name: "closed", Host: "127.0.0.1", Port: port, Protocol: ProbeTCP})
+	if result.Available || result.Error == "" {
+		t.Errorf("Expected closed port to be unavailable with an error, got %+v", result)
+	}
+}
+
+func TestProbeUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	// Echo server
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo(buf[:n], addr)
+		}
+	}()
+
+	c := NewPingCollector(nil)
+	result := c.pingTarget(PingTarget{Name: "echo", Host: "127.0.0.1", Port: listenerPort(t, conn.LocalAddr()), Protocol: ProbeUDP})
+	if !result.Available {
+		t.Fatalf("Expected UDP echo target to be available, got error %q", result.Error)
+	}
+}
+
+func TestProbeUDPTimeout(t *testing.T) {
+	// Listener that never answers
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	c := NewPingCollector(nil)
+	result := c.pingTarget(PingTarget{
+		Name:     "silent",
+		Host:     "127.0.0.1",
+		Port:     listenerPort(t, conn.LocalAddr()),
+		Protocol: ProbeUDP,
+		Timeout:  100 * time.Millisecond,
+	})
+	if result.Available {
+		t.Error("Expected silent UDP target to be unavailable")
+	}
+}
+
+func TestProbeHTTP(t *testing.T) {
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	c := NewPingCollector(nil)
+	result := c.pingTarget(PingTarget{Name: "web", URL: server.URL, Protocol: ProbeHTTP})
+	if !result.Available {
+		t.Fatalf("Expected HTTP target to be available, got error %q", result.Error)
+	}
+	if method != http.MethodHead {
+		t.Errorf("Expected HEAD request, got %s", method)
+	}
+	if result.HTTP == nil || result.HTTP.StatusCode != http.StatusNoContent {
+		t.Errorf("Expected status %d in check info, got %+v", http.StatusNoContent, result.HTTP)
+	}
+	if result.HTTP.TTFBMs <= 0 || result.HTTP.ConnectMs <= 0 {
+		t.Errorf("Expected connect and TTFB timings, got %+v", result.HTTP)
+	}
+}
+
+func TestProbeHTTPExpectations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			http.Error(w, "database unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"status":"ok"}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		path      string
+		expect    []int
+		body      string
+		available bool
+	}{
+		{"any status", "/broken", nil, "", true},
+		{"expected status", "/health", []int{200}, "", true},
+		{"unexpected status", "/broken", []int{200, 204}, "", false},
+		{"body match", "/health", []int{200}, `"ok"`, true},
+		{"body mismatch", "/health", nil, "healthy", false},
+	}
+
+	c := NewPingCollector(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := c.pingTarget(PingTarget{
+				Name:         "web",
+				URL:          server.URL + tt.path,
+				Protocol:     ProbeHTTP,
+				ExpectStatus: tt.expect,
+				BodyContains: tt.body,
+			})
+			if result.Available != tt.available {
+				t.Errorf("Expected available=%v, got %v (%s)", tt.available, result.Available, result.Error)
+			}
+			if tt.body != "" && result.HTTP.BodyMatched != tt.available {
+				t.Errorf("Expected body matched=%v", tt.available)
+			}
+		})
+	}
+}
+
+func TestProbeHTTPSCertExpiry(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	c := NewPingCollector(nil)
+	c.httpClient.Transport.(*http.Transport).TLSClientConfig =
+		server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	result := c.pingTarget(PingTarget{Name: "tls", URL: server.URL, Protocol: ProbeHTTP})
+	if !result.Available {
+		t.Fatalf("Expected HTTPS target to be available, got error %q", result.Error)
+	}
+	if !result.HTTP.HasCert() {
+		t.Fatal("Expected certificate info")
+	}
+
+	notAfter := server.Certificate().NotAfter
+	if !result.HTTP.CertExpiry.Equal(notAfter) {
+		t.Errorf("Expected expiry %v, got %v", notAfter, result.HTTP.CertExpiry)
+	}
+	wantDays := time.Until(notAfter).Hours() / 24
+	if math.Abs(result.HTTP.CertDaysLeft-wantDays) > 1 {
+		t.Errorf("Expected ~%.0f days left, got %.0f", wantDays, result.HTTP.CertDaysLeft)
+	}
+	if result.HTTP.TLSMs <= 0 {
+		t.Error("Expected TLS handshake timing")
+	}
+}
+
+func TestProbeICMP(t *testing.T) {
+	latency, err := probeICMP("127.0.0.1", time.Second)
+	if err != nil {
+		// Unprivileged ICMP sockets may be disabled (net.ipv4.ping_group_range)
+		t.Skipf("ICMP probe unavailable: %v", err)
+	}
+	if latency <= 0 {
+		t.Errorf("Expected positive latency, got %v", latency)
+	}
+}
+
+func TestProbeStatus(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"ok", nil, ProbeStatusOK},
+		{"icmp timeout", fmt.Errorf("icmp: %w", os.ErrDeadlineExceeded), ProbeStatusTimeout},
+		{"nxdomain", &net.DNSError{Err: "no such host", Name: "x.invalid", IsNotFound: true}, ProbeStatusNXDomain},
+		{"error", errors.New("icmp send: network is unreachable"), ProbeStatusError},
+	}
+
+	for _, tt := range tests {
+		if got := probeStatus(tt.err); got != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestICMPChecksum(t *testing.T) {
+	msg := icmpEchoRequest(0x1234, 1, []byte("abc"))
+	// A message including its own checksum sums to zero
+	if sum := icmpChecksum(msg); sum != 0 {
+		t.Errorf("Expected checksum of full message to verify, got %#x", sum)
+	}
+}
+
+func TestNewPingCollectorFromConfig(t *testing.T) {
+	cfg := &config.PingConfig{
+		Interval: 10 * time.Second,
+		Timeout:  500 * time.Millisecond,
+		Targets: []config.PingTargetConfig{
+			{Name: "LAN", Host: "192.168.1.1", Protocol: "ICMP"},
+			{Name: "Off", Host: "10.0.0.1", Disabled: true},
+		},
+	}
+
+	c := NewPingCollector(cfg)
+	if c.interval != 10*time.Second || c.timeout != 500*time.Millisecond {
+		t.Errorf("Expected interval/timeout from config, got %v/%v", c.interval, c.timeout)
+	}
+	if len(c.targets) != 2 {
+		t.Fatalf("Expected 2 targets, got %d", len(c.targets))
+	}
+	if c.targets[0].Protocol != ProbeICMP || !c.targets[0].Enabled {
+		t.Errorf("Unexpected first target: %+v", c.targets[0])
+	}
+	if c.targets[1].Enabled {
+		t.Error("Expected disabled target")
+	}
+
+	// No targets in config falls back to defaults
+	if c := NewPingCollector(&config.PingConfig{}); len(c.targets) != len(DefaultPingTargets()) {
+		t.Errorf("Expected default targets, got %d", len(c.targets))
+	}
+}
+
+// listenerPort returns the port of a local listener address.
+func listenerPort(t *testing.T, addr net.Addr) int {
+	t.Helper()
+	_, portStr, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		t.Fatalf("Failed to parse address %s: %v", addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatalf("Failed to parse port %s: %v", portStr, err)
+	}
+	return port
+}
+
+func TestComputePingStats(t *testing.T) {
+	ms := time.Millisecond
+	samples := []pingSample{
+		{latency: 10 * ms, available: true},
+		{latency: 20 * ms, available: true},
+		{available: false},
+		{latency: 10 * ms, available: true},
+		{latency: 40 * ms, available: true},
+	}
+
+	stats := computePingStats(samples)
+	if stats.Samples != 5 {
+		t.Errorf("Expected 5 samples, got %d", stats.Samples)
+	}
+	if stats.LossPercent != 20 {
+		t.Errorf("Expected 20%% loss, got %f", stats.LossPercent)
+	}
+	// |20-10| + |10-20| + |40-10| = 50 over 3 pairs
+	if want := 50.0 / 3; math.Abs(stats.JitterMs-want) > 1e-9 {
+		t.Errorf("Expected jitter %f, got %f", want, stats.JitterMs)
+	}
+	if stats.MinMs != 10 || stats.MaxMs != 40 || stats.AvgMs != 20 {
+		t.Errorf("Unexpected min/max/avg: %f/%f/%f", stats.MinMs, stats.MaxMs, stats.AvgMs)
+	}
+	// Sorted: 10, 10, 20, 40
+	if stats.P50Ms != 15 {
+		t.Errorf("Expected p50 15, got %f", stats.P50Ms)
+	}
+	if want := 20 + (40-20)*0.85; math.Abs(stats.P95Ms-want) > 1e-9 {
+		t.Errorf("Expected p95 %f, got %f", want, stats.P95Ms)
+	}
+
+	// All probes lost
+	stats = computePingStats([]pingSample{{}, {}})
+	if stats.LossPercent != 100 || stats.P50Ms != 0 {
+		t.Errorf("Expected 100%% loss and no latency, got %+v", stats)
+	}
+}
+
+func TestPingStatsWindow(t *testing.T) {
+	c := NewPingCollector(&config.PingConfig{Window: 3})
+	c.targets = []PingTarget{{Name: "a", Enabled: true}}
+
+	for i := 1; i <= 5; i++ {
+		results := map[string]*PingResult{
+			"a": {Name: "a", Available: true, Latency: time.Duration(i) * time.Millisecond},
+		}
+		c.results = results
+		c.recordSamples(results)
+	}
+
+	stats := c.GetStats()
+	if len(stats) != 1 {
+		t.Fatalf("Expected 1 target, got %d", len(stats))
+	}
+	// Only the last 3 samples (3, 4, 5 ms) are kept
+	if stats[0].Samples != 3 || stats[0].MinMs != 3 || stats[0].LastMs != 5 {
+		t.Errorf("Unexpected window stats: %+v", stats[0])
+	}
+}
+
+// startDNSServer runs a minimal UDP DNS stand-in that answers A queries for
+// known names with 127.0.0.1, AAAA queries with an empty answer and unknown
+// names with NXDOMAIN.
+func startDNSServer(t *testing.T, known string) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if resp := dnsResponse(buf[:n], known); resp != nil {
+				conn.WriteTo(resp, addr)
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func dnsResponse(query []byte, known string) []byte {
+	if len(query) < 12 {
+		return nil
+	}
+
+	// Walk the question name
+	var labels []string
+	off := 12
+	for off < len(query) && query[off] != 0 {
+		l := int(query[off])
+		if off+1+l > len(query) {
+			return nil
+		}
+		labels = append(labels, string(query[off+1:off+1+l]))
+		off += 1 + l
+	}
+	off++ // terminating zero
+	if off+4 > len(query) {
+		return nil
+	}
+	qtype := int(query[off])<<8 | int(query[off+1])
+	question := query[12 : off+4]
+	name := strings.Join(labels, ".")
+
+	flags := uint16(0x8180) // response, recursion desired and available
+	var answers int
+	switch {
+	case !strings.EqualFold(name, known):
+		flags |= 3 // NXDOMAIN
+	case qtype == 1:
+		answers = 1
+	}
+
+	resp := []byte{
+		query[0], query[1],
+		byte(flags >> 8), byte(flags),
+		0, 1, 0, byte(answers), 0, 0, 0, 0,
+	}
+	resp = append(resp, question...)
+	if answers > 0 {
+		resp = append(resp,
+			0xC0, 12, // pointer to the question name
+			0, 1, 0, 1, // type A, class IN
+			0, 0, 0, 60, // TTL
+			0, 4, 127, 0, 0, 1,
+		)
+	}
+
+	return resp
+}
+
+func TestProbeDNS(t *testing.T) {
+	server := startDNSServer(t, "game.test")
+
+	c := NewPingCollector(nil)
+	result := c.pingTarget(PingTarget{
+		Name:     "dns",
+		Host:     "game.test.",
+		Protocol: ProbeDNS,
+		Server:   server,
+		Timeout:  time.Second,
+	})
+	if !result.Available {
+		t.Fatalf("Expected name to resolve, got error: %s", result.Error)
+	}
+	if result.Status != ProbeStatusOK {
+		t.Errorf("Expected status %q, got %q", ProbeStatusOK, result.Status)
+	}
+	if result.Latency <= 0 {
+		t.Error("Expected positive resolution time")
+	}
+}
+
+func TestProbeDNSNXDomain(t *testing.T) {
+	server := startDNSServer(t, "game.test")
+
+	c := NewPingCollector(nil)
+	result := c.pingTarget(PingTarget{
+		Name:     "dns",
+		Host:     "missing.test.",
+		Protocol: ProbeDNS,
+		Server:   server,
+		Timeout:  time.Second,
+	})
+	if result.Available {
+		t.Fatal("Expected unknown name to fail")
+	}
+	if result.Status != ProbeStatusNXDomain {
+		t.Errorf("Expected status %q, got %q (%s)", ProbeStatusNXDomain, result.Status, result.Error)
+	}
+}
+
+func TestProbeDNSTimeout(t *testing.T) {
+	// Server that never answers
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer conn.Close()
+
+	c := NewPingCollector(nil)
+	result := c.pingTarget(PingTarget{
+		Name:     "dns",
+		Host:     "game.test.",
+		Protocol: ProbeDNS,
+		Server:   conn.LocalAddr().String(),
+		Timeout:  200 * time.Millisecond,
+	})
+	if result.Available {
+		t.Fatal("Expected silent server to fail")
+	}
+	if result.Status != ProbeStatusTimeout {
+		t.Errorf("Expected status %q, got %q (%s)", ProbeStatusTimeout, result.Status, result.Error)
+	}
+}
+
+func TestPingStatsDNSCounts(t *testing.T) {
+	stats := computePingStats([]pingSample{
+		{latency: 5 * time.Millisecond, available: true, status: ProbeStatusOK},
+		{status: ProbeStatusNXDomain},
+		{status: ProbeStatusTimeout},
+		{status: ProbeStatusNXDomain},
+	})
+	if stats.NXDomainCount != 2 {
+		t.Errorf("Expected 2 NXDOMAIN answers, got %d", stats.NXDomainCount)
+	}
+	if stats.TimeoutCount != 1 {
+		t.Errorf("Expected 1 timeout, got %d", stats.TimeoutCount)
+	}
+	// Only the timeout is lost, NXDOMAIN is an answer
+	if stats.LossPercent != 25 {
+		t.Errorf("Expected 25%% loss, got %v", stats.LossPercent)
+	}
+}
+
+func TestBestRoundTrip(t *testing.T) {
+	ms := time.Millisecond
+	results := map[string]*PingResult{
+		"DNS (system)": {Name: "DNS (system)", Protocol: ProbeDNS, Latency: 0, Available: true},
+		"Dev API":      {Name: "Dev API", Protocol: ProbeHTTP, Latency: 2 * ms, Available: true},
+		"Cloudflare":   {Name: "Cloudflare", Protocol: ProbeTCP, Latency: 15 * ms, Available: true},
+		"Router":       {Name: "Router", Protocol: ProbeICMP, Latency: 1 * ms, Available: false},
+		"Echo":         {Name: "Echo", Protocol: ProbeUDP, Latency: 30 * ms, Available: true},
+	}
+
+	latency, name := bestRoundTrip(results)
+	if latency != 15*ms || name != "Cloudflare" {
+		t.Errorf("Expected Cloudflare at 15ms, got %s at %v", name, latency)
+	}
+
+	delete(results, "Cloudflare")
+	delete(results, "Echo")
+	if latency, name := bestRoundTrip(results); name != "" || latency != time.Hour {
+		t.Errorf("Expected no round-trip target, got %s at %v", name, latency)
+	}
+}
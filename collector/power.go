@@ -0,0 +1,233 @@
+package collector
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// defaultPowerSupplyRoot is the sysfs directory listing power supplies.
+const defaultPowerSupplyRoot = "/sys/class/power_supply"
+
+// PowerCollector collects battery and AC adapter state.
+type PowerCollector struct {
+	root string
+}
+
+// NewPowerCollector creates a new power collector reading power supplies
+// from root (defaults to /sys/class/power_supply).
+func NewPowerCollector(root string) *PowerCollector {
+	if root == "" {
+		root = defaultPowerSupplyRoot
+	}
+	return &PowerCollector{root: root}
+}
+
+// Collect gathers current power supply metrics. When sysfs has no power
+// supplies, the platform power status is used where available.
+func (c *PowerCollector) Collect() models.PowerMetrics {
+	metrics, found := readPowerSupplies(c.root)
+	if !found {
+		if status, ok := systemPowerStatus(); ok {
+			return status
+		}
+	}
+	return metrics
+}
+
+// readPowerSupplies reads all power supplies under root. It returns false
+// if no power supply was found.
+func readPowerSupplies(root string) (models.PowerMetrics, bool) {
+	metrics := models.PowerMetrics{}
+
+	entries, err := os.ReadDir(root)
+	if err != nil || len(entries) == 0 {
+		return metrics, false
+	}
+
+	var sawAdapter, adapterOnline bool
+	for _, entry := range entries {
+		dir := filepath.Join(root, entry.Name())
+		supplyType := readSysfsString(dir, "type")
+
+		if supplyType != "Battery" {
+			// Mains, USB, USB_C, ... adapters report "online"
+			if online, ok := readSysfsInt(dir, "online"); ok {
+				sawAdapter = true
+				adapterOnline = adapterOnline || online == 1
+			}
+			continue
+		}
+
+		// Skip absent batteries and peripheral batteries (mice, keyboards)
+		if present, ok := readSysfsInt(dir, "present"); ok && present == 0 {
+			continue
+		}
+		if readSysfsString(dir, "scope") == "Device" {
+			continue
+		}
+
+		metrics.Batteries = append(metrics.Batteries, readBattery(entry.Name(), dir))
+	}
+
+	if len(metrics.Batteries) == 0 {
+		metrics.OnAC = sawAdapter && adapterOnline
+		return metrics, sawAdapter
+	}
+
+	metrics.Available = true
+	aggregateBatteries(&metrics)
+
+	if sawAdapter {
+		metrics.OnAC = adapterOnline
+	} else {
+		metrics.OnAC = metrics.Status != models.BatteryDischarging
+	}
+
+	return metrics, true
+}
+
+// readBattery reads a single battery directory.
+func readBattery(name, dir string) models.BatteryInfo {
+	battery := models.BatteryInfo{
+		Name:   name,
+		Status: normalizeBatteryStatus(readSysfsString(dir, "status")),
+	}
+
+	voltage, hasVoltage := readSysfsInt(dir, "voltage_now") // µV
+
+	// Energy is reported either in µWh or as charge in µAh
+	if now, ok := readSysfsInt(dir, "energy_now"); ok {
+		battery.EnergyNowWh = float64(now) / 1e6
+		if full, ok := readSysfsInt(dir, "energy_full"); ok {
+			battery.EnergyFullWh = float64(full) / 1e6
+		}
+	} else if now, ok := readSysfsInt(dir, "charge_now"); ok && hasVoltage {
+		battery.EnergyNowWh = float64(now) / 1e6 * float64(voltage) / 1e6
+		if full, ok := readSysfsInt(dir, "charge_full"); ok {
+			battery.EnergyFullWh = float64(full) / 1e6 * float64(voltage) / 1e6
+		}
+	}
+
+	// Power is reported either in µW or as current in µA
+	if power, ok := readSysfsInt(dir, "power_now"); ok {
+		battery.PowerW = math.Abs(float64(power)) / 1e6
+	} else if current, ok := readSysfsInt(dir, "current_now"); ok && hasVoltage {
+		battery.PowerW = math.Abs(float64(current)) / 1e6 * float64(voltage) / 1e6
+	}
+
+	if capacity, ok := readSysfsInt(dir, "capacity"); ok {
+		battery.CapacityPercent = float64(capacity)
+	} else if battery.EnergyFullWh > 0 {
+		battery.CapacityPercent = battery.EnergyNowWh / battery.EnergyFullWh * 100
+	}
+
+	switch battery.Status {
+	case models.BatteryDischarging:
+		if seconds, ok := readSysfsInt(dir, "time_to_empty_now"); ok {
+			battery.TimeToEmptyMin = float64(seconds) / 60
+		} else if battery.PowerW > 0 {
+			battery.TimeToEmptyMin = battery.EnergyNowWh / battery.PowerW * 60
+		}
+	case models.BatteryCharging:
+		if seconds, ok := readSysfsInt(dir, "time_to_full_now"); ok {
+			battery.TimeToFullMin = float64(seconds) / 60
+		} else if battery.PowerW > 0 && battery.EnergyFullWh > battery.EnergyNowWh {
+			battery.TimeToFullMin = (battery.EnergyFullWh - battery.EnergyNowWh) / battery.PowerW * 60
+		}
+	}
+
+	return battery
+}
+
+// aggregateBatteries fills the totals of metrics from its batteries.
+func aggregateBatteries(metrics *models.PowerMetrics) {
+	var energyNow, energyFull, capacitySum float64
+	energyKnown := true
+
+	metrics.Status = metrics.Batteries[0].Status
+	for _, b := range metrics.Batteries {
+		energyNow += b.EnergyNowWh
+		energyFull += b.EnergyFullWh
+		capacitySum += b.CapacityPercent
+		metrics.PowerDrawW += b.PowerW
+		if b.EnergyFullWh <= 0 {
+			energyKnown = false
+		}
+
+		switch {
+		case b.Status == models.BatteryDischarging:
+			metrics.Status = models.BatteryDischarging
+		case b.Status == models.BatteryCharging && metrics.Status != models.BatteryDischarging:
+			metrics.Status = models.BatteryCharging
+		}
+	}
+
+	metrics.EnergyNowWh = energyNow
+	metrics.EnergyFullWh = energyFull
+	if energyKnown {
+		metrics.BatteryPercent = energyNow / energyFull * 100
+	} else {
+		metrics.BatteryPercent = capacitySum / float64(len(metrics.Batteries))
+	}
+
+	// Single battery: trust its own estimate (may come from the firmware)
+	if len(metrics.Batteries) == 1 {
+		metrics.TimeToEmptyMin = metrics.Batteries[0].TimeToEmptyMin
+		metrics.TimeToFullMin = metrics.Batteries[0].TimeToFullMin
+		return
+	}
+
+	if metrics.PowerDrawW > 0 {
+		switch metrics.Status {
+		case models.BatteryDischarging:
+			metrics.TimeToEmptyMin = energyNow / metrics.PowerDrawW * 60
+		case models.BatteryCharging:
+			if energyFull > energyNow {
+				metrics.TimeToFullMin = (energyFull - energyNow) / metrics.PowerDrawW * 60
+			}
+		}
+	}
+}
+
+// normalizeBatteryStatus converts a sysfs status to a models battery status.
+func normalizeBatteryStatus(status string) string {
+	switch strings.ToLower(status) {
+	case "charging":
+		return models.BatteryCharging
+	case "discharging":
+		return models.BatteryDischarging
+	case "full":
+		return models.BatteryFull
+	case "not charging":
+		return models.BatteryNotCharging
+	default:
+		return models.BatteryUnknown
+	}
+}
+
+// readSysfsString reads a trimmed sysfs attribute, or "" if missing.
+func readSysfsString(dir, name string) string {
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readSysfsInt reads an integer sysfs attribute.
+func readSysfsInt(dir, name string) (int64, bool) {
+	value := readSysfsString(dir, name)
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
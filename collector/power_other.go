@@ -0,0 +1,10 @@
+//go:build !windows
+
+package collector
+
+import "github.com/NaveLIL/erez-monitor/models"
+
+// systemPowerStatus is only needed where sysfs is unavailable.
+func systemPowerStatus() (models.PowerMetrics, bool) {
+	return models.PowerMetrics{}, false
+}
@@ -0,0 +1,99 @@
+package collector
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+func powerFixture(name string) string {
+	return filepath.Join("testdata", "power_supply", name)
+}
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
+
+func TestPowerCollectorDischarging(t *testing.T) {
+	m := NewPowerCollector(powerFixture("discharging")).Collect()
+
+	if !m.Available || m.OnAC || !m.OnBattery() {
+		t.Fatalf("Expected laptop on battery, got %+v", m)
+	}
+	if len(m.Batteries) != 1 {
+		t.Fatalf("Expected peripheral battery to be skipped, got %d batteries", len(m.Batteries))
+	}
+	if m.Status != models.BatteryDischarging {
+		t.Errorf("Expected status %q, got %q", models.BatteryDischarging, m.Status)
+	}
+	if !approxEqual(m.BatteryPercent, 42) {
+		t.Errorf("Expected 42%%, got %.2f", m.BatteryPercent)
+	}
+	if !approxEqual(m.PowerDrawW, 10.5) {
+		t.Errorf("Expected 10.5 W, got %.2f", m.PowerDrawW)
+	}
+	if !approxEqual(m.TimeToEmptyMin, 120) {
+		t.Errorf("Expected 120 min to empty, got %.2f", m.TimeToEmptyMin)
+	}
+}
+
+func TestPowerCollectorChargeBased(t *testing.T) {
+	m := NewPowerCollector(powerFixture("charging")).Collect()
+
+	if !m.Available || !m.OnAC {
+		t.Fatalf("Expected battery on AC, got %+v", m)
+	}
+	b := m.Batteries[0]
+	if b.Name != "BAT1" || b.Status != models.BatteryCharging {
+		t.Errorf("Unexpected battery %+v", b)
+	}
+	// 2 Ah / 4 Ah at 12 V
+	if !approxEqual(b.EnergyNowWh, 24) || !approxEqual(b.EnergyFullWh, 48) {
+		t.Errorf("Expected 24/48 Wh, got %.2f/%.2f", b.EnergyNowWh, b.EnergyFullWh)
+	}
+	if !approxEqual(b.CapacityPercent, 50) {
+		t.Errorf("Expected capacity derived from energy (50%%), got %.2f", b.CapacityPercent)
+	}
+	if !approxEqual(b.PowerW, 12) {
+		t.Errorf("Expected 12 W from current and voltage, got %.2f", b.PowerW)
+	}
+	if !approxEqual(m.TimeToFullMin, 120) {
+		t.Errorf("Expected firmware time to full (120 min), got %.2f", m.TimeToFullMin)
+	}
+}
+
+func TestPowerCollectorDesktop(t *testing.T) {
+	m, found := readPowerSupplies(powerFixture("desktop"))
+	if !found {
+		t.Fatal("Expected AC adapter to be found")
+	}
+	if m.Available || !m.OnAC || m.OnBattery() {
+		t.Errorf("Expected no battery on AC power, got %+v", m)
+	}
+}
+
+func TestPowerCollectorMissingRoot(t *testing.T) {
+	if _, found := readPowerSupplies(powerFixture("missing")); found {
+		t.Error("Expected no power supplies in missing root")
+	}
+}
+
+func TestAggregateMultipleBatteries(t *testing.T) {
+	m := models.PowerMetrics{Batteries: []models.BatteryInfo{
+		{Status: models.BatteryFull, EnergyNowWh: 20, EnergyFullWh: 20},
+		{Status: models.BatteryDischarging, EnergyNowWh: 10, EnergyFullWh: 40, PowerW: 15},
+	}}
+	aggregateBatteries(&m)
+
+	if m.Status != models.BatteryDischarging {
+		t.Errorf("Expected discharging, got %q", m.Status)
+	}
+	if !approxEqual(m.BatteryPercent, 50) {
+		t.Errorf("Expected 50%%, got %.2f", m.BatteryPercent)
+	}
+	if !approxEqual(m.TimeToEmptyMin, 120) {
+		t.Errorf("Expected 120 min to empty, got %.2f", m.TimeToEmptyMin)
+	}
+}
@@ -0,0 +1,73 @@
+//go:build windows
+
+package collector
+
+import (
+	"syscall"
+	"unsafe"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+var (
+	kernel32DLL              = syscall.NewLazyDLL("kernel32.dll")
+	procGetSystemPowerStatus = kernel32DLL.NewProc("GetSystemPowerStatus")
+)
+
+// systemPowerStatusInfo is the SYSTEM_POWER_STATUS structure.
+type systemPowerStatusInfo struct {
+	ACLineStatus        byte
+	BatteryFlag         byte
+	BatteryLifePercent  byte
+	SystemStatusFlag    byte
+	BatteryLifeTime     uint32
+	BatteryFullLifeTime uint32
+}
+
+const (
+	batteryFlagCharging  = 8
+	batteryFlagNoBattery = 128
+	batteryFlagUnknown   = 255
+	batteryUnknownValue  = 255
+	batteryUnknownTime   = 0xFFFFFFFF
+)
+
+// systemPowerStatus returns the power status reported by Windows.
+func systemPowerStatus() (models.PowerMetrics, bool) {
+	var status systemPowerStatusInfo
+	ret, _, _ := procGetSystemPowerStatus.Call(uintptr(unsafe.Pointer(&status)))
+	if ret == 0 {
+		return models.PowerMetrics{}, false
+	}
+
+	metrics := models.PowerMetrics{OnAC: status.ACLineStatus == 1}
+	if status.BatteryFlag&batteryFlagNoBattery != 0 || status.BatteryFlag == batteryFlagUnknown ||
+		status.BatteryLifePercent == batteryUnknownValue {
+		return metrics, true
+	}
+
+	metrics.Available = true
+	metrics.BatteryPercent = float64(status.BatteryLifePercent)
+	switch {
+	case status.BatteryFlag&batteryFlagCharging != 0:
+		metrics.Status = models.BatteryCharging
+	case metrics.OnAC && status.BatteryLifePercent >= 100:
+		metrics.Status = models.BatteryFull
+	case metrics.OnAC:
+		metrics.Status = models.BatteryNotCharging
+	default:
+		metrics.Status = models.BatteryDischarging
+	}
+	if !metrics.OnAC && status.BatteryLifeTime != batteryUnknownTime {
+		metrics.TimeToEmptyMin = float64(status.BatteryLifeTime) / 60
+	}
+
+	metrics.Batteries = []models.BatteryInfo{{
+		Name:            "BAT0",
+		Status:          metrics.Status,
+		CapacityPercent: metrics.BatteryPercent,
+		TimeToEmptyMin:  metrics.TimeToEmptyMin,
+	}}
+
+	return metrics, true
+}
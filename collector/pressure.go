@@ -0,0 +1,172 @@
+package collector
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// ioPressure is the paging and disk queue pressure between two samples.
+type ioPressure struct {
+	hardFaultsPerSec float64
+	queueLength      float64
+}
+
+// pressureSampler reads the platform paging and disk queue counters.
+type pressureSampler interface {
+	// sample returns the pressure since the previous call; ok is false on
+	// the first call and when the counters are unavailable.
+	sample(now time.Time) (p ioPressure, ok bool)
+	// reset discards the previous counters.
+	reset()
+}
+
+// PressureCollector measures hard page faults and the disk queue length,
+// which show whether the system is stalled on I/O.
+type PressureCollector struct {
+	sampler pressureSampler
+	mu      sync.Mutex
+}
+
+// NewPressureCollector creates a new pressure collector. procRoot is the
+// procfs mount point (ignored on Windows).
+func NewPressureCollector(procRoot string) *PressureCollector {
+	if procRoot == "" {
+		procRoot = defaultProcRoot
+	}
+	return &PressureCollector{sampler: newPressureSampler(procRoot)}
+}
+
+// Apply samples the counters and fills the paging and disk queue fields.
+func (c *PressureCollector) Apply(memory *models.MemoryMetrics, disk *models.DiskMetrics) {
+	if c == nil || c.sampler == nil {
+		return
+	}
+	c.mu.Lock()
+	p, ok := c.sampler.sample(time.Now())
+	c.mu.Unlock()
+
+	if ok {
+		memory.HardFaultsPerSec = p.hardFaultsPerSec
+		disk.QueueLength = p.queueLength
+	}
+}
+
+// Reset discards the previous counters so that the next rates are not
+// computed across a collection gap.
+func (c *PressureCollector) Reset() {
+	if c == nil || c.sampler == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sampler.reset()
+}
+
+// procPressureReader reads major page faults from /proc/vmstat and the
+// weighted I/O time of each block device from /proc/diskstats. The growth
+// of the weighted time (ms spent by all outstanding requests) divided by
+// the elapsed time is the average queue length.
+type procPressureReader struct {
+	procRoot string
+
+	primed   bool
+	faults   uint64
+	weighted map[string]uint64
+	lastAt   time.Time
+}
+
+// newProcPressureReader creates a reader for the given procfs root.
+func newProcPressureReader(procRoot string) *procPressureReader {
+	return &procPressureReader{procRoot: procRoot}
+}
+
+func (r *procPressureReader) sample(now time.Time) (ioPressure, bool) {
+	faults, err := readVMStatCounter(filepath.Join(r.procRoot, "vmstat"), "pgmajfault")
+	if err != nil {
+		return ioPressure{}, false
+	}
+	weighted, err := readDiskstatsWeighted(filepath.Join(r.procRoot, "diskstats"))
+	if err != nil {
+		return ioPressure{}, false
+	}
+
+	var p ioPressure
+	elapsed := now.Sub(r.lastAt).Seconds()
+	ok := r.primed && elapsed > 0
+	if ok {
+		if faults >= r.faults {
+			p.hardFaultsPerSec = float64(faults-r.faults) / elapsed
+		}
+		for name, ms := range weighted {
+			if last, seen := r.weighted[name]; seen && ms >= last {
+				p.queueLength = max(p.queueLength, float64(ms-last)/(elapsed*1000))
+			}
+		}
+	}
+
+	r.primed = true
+	r.faults = faults
+	r.weighted = weighted
+	r.lastAt = now
+	return p, ok
+}
+
+func (r *procPressureReader) reset() {
+	r.primed = false
+}
+
+// readVMStatCounter returns a counter from /proc/vmstat.
+func readVMStatCounter(path, name string) (uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[0] == name {
+			return strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+	return 0, os.ErrNotExist
+}
+
+// readDiskstatsWeighted returns the weighted I/O time in ms per block
+// device. Partitions are included; their queue never exceeds the disk's.
+func readDiskstatsWeighted(path string) (map[string]uint64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	weighted := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		// major minor name reads ... io_ticks weighted_io_ticks [...]
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 14 {
+			continue
+		}
+		name := fields[2]
+		if strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram") {
+			continue
+		}
+		if ms, err := strconv.ParseUint(fields[13], 10, 64); err == nil {
+			weighted[name] = ms
+		}
+	}
+	return weighted, scanner.Err()
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package collector
+
+// newPressureSampler returns the pressure sampler, which reads procfs.
+func newPressureSampler(procRoot string) pressureSampler {
+	return newProcPressureReader(procRoot)
+}
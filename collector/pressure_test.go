@@ -0,0 +1,66 @@
+package collector
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+// writeProcPressure writes a fake /proc/vmstat and /proc/diskstats.
+func writeProcPressure(t *testing.T, root string, majFaults, sdaWeighted, sda1Weighted, loopWeighted uint64) {
+	t.Helper()
+
+	diskstats := fmt.Sprintf(`   7       0 loop0 10 0 20 5 0 0 0 0 0 5 %d 0 0 0 0
+   8       0 sda 1200 30 90000 800 500 20 40000 900 2 1500 %d 0 0 0 0 0 0
+   8       1 sda1 1100 30 88000 700 450 20 39000 800 2 1400 %d 0 0 0 0 0 0
+`, loopWeighted, sdaWeighted, sda1Weighted)
+	writeSysfsFiles(t, root, map[string]string{
+		"vmstat":    fmt.Sprintf("pgfault 123456\npgmajfault %d\npswpin 0\n", majFaults),
+		"diskstats": diskstats,
+	})
+}
+
+func TestProcPressureReader(t *testing.T) {
+	root := t.TempDir()
+	r := newProcPressureReader(root)
+	start := time.Now()
+
+	writeProcPressure(t, root, 1000, 5000, 4000, 100)
+	if _, ok := r.sample(start); ok {
+		t.Fatal("Expected no rates from the first sample")
+	}
+
+	// 2 s later: 400 faults, sda 6 s and sda1 3 s of weighted I/O time,
+	// loop devices are ignored
+	writeProcPressure(t, root, 1400, 11000, 7000, 100000)
+	p, ok := r.sample(start.Add(2 * time.Second))
+	if !ok {
+		t.Fatal("Expected rates from the second sample")
+	}
+	if math.Abs(p.hardFaultsPerSec-200) > 1e-9 {
+		t.Errorf("Expected 200 faults/s, got %.2f", p.hardFaultsPerSec)
+	}
+	if math.Abs(p.queueLength-3) > 1e-9 {
+		t.Errorf("Expected a queue of 3 on the busiest disk, got %.2f", p.queueLength)
+	}
+
+	// Counters that went backwards are not turned into rates
+	writeProcPressure(t, root, 10, 11000, 7000, 100000)
+	p, _ = r.sample(start.Add(3 * time.Second))
+	if p.hardFaultsPerSec != 0 || p.queueLength != 0 {
+		t.Errorf("Expected no rates after a counter reset, got %+v", p)
+	}
+
+	r.reset()
+	if _, ok := r.sample(start.Add(4 * time.Second)); ok {
+		t.Error("Expected no rates right after a reset")
+	}
+}
+
+func TestProcPressureReaderMissing(t *testing.T) {
+	r := newProcPressureReader(t.TempDir())
+	if _, ok := r.sample(time.Now()); ok {
+		t.Error("Expected no pressure without procfs")
+	}
+}
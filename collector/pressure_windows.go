@@ -0,0 +1,71 @@
+//go:build windows
+
+package collector
+
+import (
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// pdhPressure reads the hard fault rate and the per-disk queue lengths from
+// the performance counters. Both are averaged by PDH between collections.
+type pdhPressure struct {
+	query  uintptr
+	faults uintptr
+	queue  uintptr
+	primed bool
+}
+
+// newPressureSampler returns the pressure sampler, which reads the memory
+// and physical disk performance counters. Returns nil when unavailable.
+func newPressureSampler(procRoot string) pressureSampler {
+	p := &pdhPressure{}
+	ret, _, _ := procPdhOpenQuery.Call(0, 0, uintptr(unsafe.Pointer(&p.query)))
+	if ret != 0 {
+		return nil
+	}
+
+	// Page Reads/sec counts disk reads to resolve hard faults
+	procPdhAddCounterW.Call(p.query,
+		uintptr(unsafe.Pointer(utf16PtrFromString(`\Memory\Page Reads/sec`))),
+		0, uintptr(unsafe.Pointer(&p.faults)))
+	procPdhAddCounterW.Call(p.query,
+		uintptr(unsafe.Pointer(utf16PtrFromString(`\PhysicalDisk(*)\Avg. Disk Queue Length`))),
+		0, uintptr(unsafe.Pointer(&p.queue)))
+	if p.faults == 0 && p.queue == 0 {
+		procPdhCloseQuery.Call(p.query)
+		return nil
+	}
+	return p
+}
+
+func (p *pdhPressure) sample(now time.Time) (ioPressure, bool) {
+	if ret, _, _ := procPdhCollectQueryData.Call(p.query); ret != 0 {
+		return ioPressure{}, false
+	}
+	// Rate counters need two collections
+	if !p.primed {
+		p.primed = true
+		return ioPressure{}, false
+	}
+
+	var pressure ioPressure
+	if p.faults != 0 {
+		var value PDH_FMT_COUNTERVALUE
+		ret, _, _ := procPdhGetFormattedValue.Call(p.faults, PDH_FMT_DOUBLE, 0, uintptr(unsafe.Pointer(&value)))
+		if ret == 0 && value.CStatus == 0 {
+			pressure.hardFaultsPerSec = value.DoubleValue
+		}
+	}
+	for _, v := range readCounterArray(p.queue) {
+		if !strings.EqualFold(v.instance, "_Total") {
+			pressure.queueLength = max(pressure.queueLength, v.value)
+		}
+	}
+	return pressure, true
+}
+
+func (p *pdhPressure) reset() {
+	p.primed = false
+}
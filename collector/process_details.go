@@ -0,0 +1,169 @@
+package collector
+
+import (
+	"sort"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// processDetails holds rarely-changing process attributes cached per PID.
+// Basic details (parent, start time) are fetched for every process; extended
+// details (command line, owner, executable path) only when first needed.
+type processDetails struct {
+	name      string // used to detect PID reuse
+	parentPID int32
+	startTime time.Time
+
+	extended bool
+	cmdline  string
+	username string
+	exePath  string
+}
+
+// getDetails returns the cached details of a process, fetching them on first
+// use. If extended is true, the command line, owner and executable path are
+// fetched as well.
+func (c *ProcessCollector) getDetails(p *process.Process, name string, extended bool) processDetails {
+	c.mu.Lock()
+	d, ok := c.details[p.Pid]
+	c.mu.Unlock()
+
+	if !ok || d.name != name {
+		d = &processDetails{name: name}
+		if ppid, err := p.Ppid(); err == nil {
+			d.parentPID = ppid
+		}
+		if created, err := p.CreateTime(); err == nil && created > 0 {
+			d.startTime = time.UnixMilli(created)
+		}
+	} else if d.extended || !extended {
+		return *d
+	} else {
+		// Copy before filling extended fields outside the lock
+		copied := *d
+		d = &copied
+	}
+
+	if extended && !d.extended {
+		d.cmdline, _ = p.Cmdline()
+		d.username, _ = p.Username()
+		d.exePath, _ = p.Exe()
+		d.extended = true
+	}
+
+	c.mu.Lock()
+	c.details[p.Pid] = d
+	c.mu.Unlock()
+
+	return *d
+}
+
+// applyDetails copies the details into the process info.
+func applyDetails(info *models.ProcessInfo, d processDetails) {
+	info.ParentPID = d.parentPID
+	info.StartTime = d.startTime
+	if d.extended {
+		info.Cmdline = d.cmdline
+		info.Username = d.username
+		info.ExePath = d.exePath
+	}
+}
+
+// pruneDetails drops cached details of processes that no longer exist.
+func (c *ProcessCollector) pruneDetails(alive map[int32]bool) {
+	for pid := range c.details {
+		if !alive[pid] {
+			delete(c.details, pid)
+		}
+	}
+}
+
+// GetProcessTree returns the process hierarchy from the last Collect call.
+// Each node carries the resource usage aggregated over its subtree. Processes
+// whose parent is unknown or gone become roots. Roots and children are sorted
+// by subtree CPU usage (descending).
+func (c *ProcessCollector) GetProcessTree() []*models.ProcessTreeNode {
+	c.mu.Lock()
+	infos := make([]models.ProcessInfo, len(c.lastAll))
+	copy(infos, c.lastAll)
+	c.mu.Unlock()
+
+	return buildProcessTree(infos)
+}
+
+// buildProcessTree builds the parent/child hierarchy of the given processes.
+func buildProcessTree(infos []models.ProcessInfo) []*models.ProcessTreeNode {
+	nodes := make(map[int32]*models.ProcessTreeNode, len(infos))
+	for _, info := range infos {
+		nodes[info.PID] = &models.ProcessTreeNode{Process: info}
+	}
+
+	var roots []*models.ProcessTreeNode
+	for _, info := range infos {
+		node := nodes[info.PID]
+		parent, ok := nodes[info.ParentPID]
+		if !ok || info.ParentPID == info.PID || createsCycle(nodes, info.PID, info.ParentPID) {
+			roots = append(roots, node)
+			continue
+		}
+		parent.Children = append(parent.Children, node)
+	}
+
+	for _, root := range roots {
+		aggregateSubtree(root)
+	}
+	sortTreeNodes(roots)
+
+	return roots
+}
+
+// createsCycle reports whether attaching pid under parentPID would form a
+// cycle, i.e. whether pid is an ancestor of parentPID. The walk stops at a
+// root or at an ancestor already visited (a cycle pid is not part of).
+func createsCycle(nodes map[int32]*models.ProcessTreeNode, pid, parentPID int32) bool {
+	visited := make(map[int32]bool)
+	for current := parentPID; !visited[current]; {
+		if current == pid {
+			return true
+		}
+		visited[current] = true
+		node, ok := nodes[current]
+		if !ok {
+			return false
+		}
+		current = node.Process.ParentPID
+	}
+	return false
+}
+
+// aggregateSubtree fills the subtree totals of node and its descendants.
+func aggregateSubtree(node *models.ProcessTreeNode) {
+	node.SubtreeProcessCount = 1
+	node.SubtreeCPUPercent = node.Process.CPUPercent
+	node.SubtreeMemoryMB = node.Process.MemoryMB
+	node.SubtreeThreads = node.Process.Threads
+
+	for _, child := range node.Children {
+		aggregateSubtree(child)
+		node.SubtreeProcessCount += child.SubtreeProcessCount
+		node.SubtreeCPUPercent += child.SubtreeCPUPercent
+		node.SubtreeMemoryMB += child.SubtreeMemoryMB
+		node.SubtreeThreads += child.SubtreeThreads
+	}
+}
+
+// sortTreeNodes sorts nodes and their children by subtree CPU usage.
+func sortTreeNodes(nodes []*models.ProcessTreeNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].SubtreeCPUPercent != nodes[j].SubtreeCPUPercent {
+			return nodes[i].SubtreeCPUPercent > nodes[j].SubtreeCPUPercent
+		}
+		return nodes[i].Process.PID < nodes[j].Process.PID
+	})
+	for _, node := range nodes {
+		sortTreeNodes(node.Children)
+	}
+}
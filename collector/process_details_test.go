@@ -0,0 +1,110 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// treeRootPIDs returns the PIDs of the roots in order.
+func treeRootPIDs(roots []*models.ProcessTreeNode) []int32 {
+	pids := make([]int32, len(roots))
+	for i, root := range roots {
+		pids[i] = root.Process.PID
+	}
+	return pids
+}
+
+func TestBuildProcessTree(t *testing.T) {
+	infos := []models.ProcessInfo{
+		{PID: 1, Name: "init", CPUPercent: 1, MemoryMB: 10, Threads: 1},
+		{PID: 10, Name: "steam.exe", ParentPID: 1, CPUPercent: 2, MemoryMB: 200, Threads: 30},
+		{PID: 11, Name: "steamwebhelper.exe", ParentPID: 10, CPUPercent: 3, MemoryMB: 300, Threads: 20},
+		{PID: 20, Name: "game.exe", ParentPID: 1, CPUPercent: 40, MemoryMB: 4000, Threads: 60},
+		// Reparented to a parent that has exited
+		{PID: 30, Name: "orphan.exe", ParentPID: 999, CPUPercent: 5, MemoryMB: 50, Threads: 2},
+		{PID: 31, Name: "self", ParentPID: 31},
+	}
+
+	roots := buildProcessTree(infos)
+	if got := treeRootPIDs(roots); len(got) != 3 || got[0] != 1 || got[1] != 30 || got[2] != 31 {
+		t.Fatalf("Expected roots 1, 30, 31 by subtree CPU, got %v", got)
+	}
+
+	initNode := roots[0]
+	if initNode.SubtreeProcessCount != 4 || initNode.SubtreeCPUPercent != 46 || initNode.SubtreeMemoryMB != 4510 || initNode.SubtreeThreads != 111 {
+		t.Errorf("Unexpected subtree totals: %+v", initNode)
+	}
+	// Children sorted by subtree CPU: game (40) before steam (2 + 3)
+	if len(initNode.Children) != 2 || initNode.Children[0].Process.PID != 20 || initNode.Children[1].Process.PID != 10 {
+		t.Fatalf("Unexpected children of init: %+v", initNode.Children)
+	}
+	steam := initNode.Children[1]
+	if steam.SubtreeProcessCount != 2 || steam.SubtreeCPUPercent != 5 || len(steam.Children) != 1 {
+		t.Errorf("Unexpected steam subtree: %+v", steam)
+	}
+}
+
+func TestBuildProcessTreeDeepChain(t *testing.T) {
+	const depth = 3 * maxTreeDepth
+	infos := make([]models.ProcessInfo, depth)
+	for i := range infos {
+		infos[i] = models.ProcessInfo{PID: int32(i + 1), ParentPID: int32(i), CPUPercent: 1}
+	}
+
+	roots := buildProcessTree(infos)
+	if len(roots) != 1 || roots[0].Process.PID != 1 {
+		t.Fatalf("Expected a single chain rooted at 1, got roots %v", treeRootPIDs(roots))
+	}
+	if roots[0].SubtreeProcessCount != depth || roots[0].SubtreeCPUPercent != depth {
+		t.Errorf("Expected %d processes in the chain, got %+v", depth, roots[0])
+	}
+}
+
+func TestBuildProcessTreeCycle(t *testing.T) {
+	// 10 -> 11 -> 12 -> 10 is a cycle (inconsistent snapshot of reused PIDs);
+	// 20 hangs off the cycle without being part of it
+	infos := []models.ProcessInfo{
+		{PID: 10, ParentPID: 12},
+		{PID: 11, ParentPID: 10},
+		{PID: 12, ParentPID: 11},
+		{PID: 20, ParentPID: 11, CPUPercent: 1},
+	}
+
+	roots := buildProcessTree(infos)
+	if len(roots) != 3 {
+		t.Fatalf("Expected every cycle member to become a root, got %v", treeRootPIDs(roots))
+	}
+	for _, root := range roots {
+		if root.Process.PID == 11 {
+			if len(root.Children) != 1 || root.Children[0].Process.PID != 20 {
+				t.Errorf("Expected 20 under 11, got %+v", root.Children)
+			}
+		} else if len(root.Children) != 0 {
+			t.Errorf("Expected no children under %d, got %+v", root.Process.PID, root.Children)
+		}
+	}
+}
+
+func TestCreatesCycle(t *testing.T) {
+	nodes := make(map[int32]*models.ProcessTreeNode)
+	for pid, ppid := range map[int32]int32{1: 0, 2: 1, 3: 2, 10: 11, 11: 10} {
+		nodes[pid] = &models.ProcessTreeNode{Process: models.ProcessInfo{PID: pid, ParentPID: ppid}}
+	}
+
+	tests := []struct {
+		pid, parent int32
+		want        bool
+	}{
+		{3, 2, false},   // regular chain
+		{1, 3, true},    // 1 is an ancestor of 3
+		{5, 999, false}, // missing parent
+		{5, 10, false},  // parent in a cycle pid is not part of
+		{10, 11, true},
+	}
+	for _, tt := range tests {
+		if got := createsCycle(nodes, tt.pid, tt.parent); got != tt.want {
+			t.Errorf("createsCycle(%d, %d) = %t, want %t", tt.pid, tt.parent, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package collector
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// maxProcessEvents is the number of recent process events kept in memory.
+const maxProcessEvents = 500
+
+// exitStatusSource reads the exit status of processes after they exit.
+type exitStatusSource interface {
+	// track starts following a live process.
+	track(pid int32)
+	// exitCode returns the exit status of a process that is gone and stops
+	// following it; ok is false if the status is unknown.
+	exitCode(pid int32) (code uint32, ok bool)
+}
+
+// crashExitCode reports whether an exit code is an exception status rather
+// than a value passed to exit(): NTSTATUS errors such as 0xC0000005 (access
+// violation) or 0xC0000409 (stack buffer overrun), and 0xE0434352 for
+// unhandled .NET exceptions, all have both severity bits set.
+func crashExitCode(code uint32) bool {
+	return code >= 0xC0000000
+}
+
+// ProcessEventTracker detects process starts and exits by diffing the PID
+// sets of consecutive ProcessCollector scans. Where the platform allows it,
+// exit events carry the exit code and crashes are flagged.
+type ProcessEventTracker struct {
+	mu          sync.Mutex
+	known       map[int32]*trackedProcess
+	initialized bool
+	exits       exitStatusSource
+
+	// Recent events
+	history   []*models.ProcessEvent
+	historyMu sync.RWMutex
+
+	subscribers []chan<- *models.ProcessEvent
+	subMu       sync.RWMutex
+}
+
+// trackedProcess holds what is known about a live process between scans.
+type trackedProcess struct {
+	name      string
+	parentPID int32
+	startTime time.Time
+	peakMB    uint64
+	lastMB    uint64
+	watchIDs  []string
+}
+
+// NewProcessEventTracker creates a new process event tracker.
+func NewProcessEventTracker() *ProcessEventTracker {
+	return &ProcessEventTracker{
+		known:   make(map[int32]*trackedProcess),
+		history: make([]*models.ProcessEvent, 0, 64),
+		exits:   newExitStatusSource(),
+	}
+}
+
+// Update diffs the current scan against the previous one and emits start and
+// exit events. The first call only records a baseline. watchIDs maps PIDs to
+// the watchlist entries they matched.
+func (t *ProcessEventTracker) Update(infos []models.ProcessInfo, watchIDs map[int32][]string, now time.Time) {
+	var events []*models.ProcessEvent
+
+	t.mu.Lock()
+	seen := make(map[int32]bool, len(infos))
+
+	for _, info := range infos {
+		seen[info.PID] = true
+
+		tp, ok := t.known[info.PID]
+		if ok && tp.name != info.Name {
+			// PID was reused by a different executable
+			events = append(events, t.exitEvent(tp, info.PID, now))
+			ok = false
+		}
+
+		if !ok {
+			tp = &trackedProcess{
+				name:      info.Name,
+				parentPID: info.ParentPID,
+				startTime: info.StartTime,
+			}
+			t.known[info.PID] = tp
+			if t.exits != nil {
+				t.exits.track(info.PID)
+			}
+		}
+
+		tp.lastMB = info.MemoryMB
+		if info.MemoryMB > tp.peakMB {
+			tp.peakMB = info.MemoryMB
+		}
+		for _, id := range watchIDs[info.PID] {
+			if !containsString(tp.watchIDs, id) {
+				tp.watchIDs = append(tp.watchIDs, id)
+			}
+		}
+
+		// After the watch IDs, so that a restart clears exit alerts
+		if !ok && t.initialized {
+			events = append(events, tp.startEvent(info.PID, now))
+		}
+	}
+
+	for pid, tp := range t.known {
+		if !seen[pid] {
+			events = append(events, t.exitEvent(tp, pid, now))
+			delete(t.known, pid)
+		}
+	}
+
+	t.initialized = true
+	t.mu.Unlock()
+
+	if len(events) == 0 {
+		return
+	}
+
+	// Deterministic order: exits before starts, then by PID
+	sort.Slice(events, func(i, j int) bool {
+		if events[i].Type != events[j].Type {
+			return events[i].Type == models.ProcessEventExit
+		}
+		return events[i].PID < events[j].PID
+	})
+
+	t.historyMu.Lock()
+	t.history = append(t.history, events...)
+	if len(t.history) > maxProcessEvents {
+		t.history = t.history[len(t.history)-maxProcessEvents:]
+	}
+	t.historyMu.Unlock()
+
+	for _, event := range events {
+		t.notifySubscribers(event)
+	}
+}
+
+// startEvent builds a start event for the process.
+func (tp *trackedProcess) startEvent(pid int32, now time.Time) *models.ProcessEvent {
+	return &models.ProcessEvent{
+		Type:      models.ProcessEventStart,
+		Timestamp: now,
+		Name:      tp.name,
+		PID:       pid,
+		ParentPID: tp.parentPID,
+		StartTime: tp.startTime,
+		WatchIDs:  tp.watchIDs,
+	}
+}
+
+// exitEvent builds an exit event for the process.
+func (tp *trackedProcess) exitEvent(pid int32, now time.Time) *models.ProcessEvent {
+	event := &models.ProcessEvent{
+		Type:         models.ProcessEventExit,
+		Timestamp:    now,
+		Name:         tp.name,
+		PID:          pid,
+		ParentPID:    tp.parentPID,
+		StartTime:    tp.startTime,
+		PeakMemoryMB: tp.peakMB,
+		LastMemoryMB: tp.lastMB,
+		WatchIDs:     tp.watchIDs,
+	}
+	if !tp.startTime.IsZero() && now.After(tp.startTime) {
+		event.Runtime = now.Sub(tp.startTime)
+	}
+	return event
+}
+
+// exitEvent builds an exit event with the exit status of the process.
+func (t *ProcessEventTracker) exitEvent(tp *trackedProcess, pid int32, now time.Time) *models.ProcessEvent {
+	event := tp.exitEvent(pid, now)
+	if t.exits != nil {
+		if code, ok := t.exits.exitCode(pid); ok {
+			event.ExitCode = &code
+			event.Crashed = crashExitCode(code)
+		}
+	}
+	return event
+}
+
+// notifySubscribers sends the event to all subscribed channels.
+func (t *ProcessEventTracker) notifySubscribers(event *models.ProcessEvent) {
+	t.subMu.RLock()
+	defer t.subMu.RUnlock()
+
+	for _, ch := range t.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Channel full, skip
+		}
+	}
+}
+
+// Subscribe adds a channel to receive process events.
+func (t *ProcessEventTracker) Subscribe(ch chan<- *models.ProcessEvent) {
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+	t.subscribers = append(t.subscribers, ch)
+}
+
+// Unsubscribe removes a channel from receiving process events.
+func (t *ProcessEventTracker) Unsubscribe(ch chan<- *models.ProcessEvent) {
+	t.subMu.Lock()
+	defer t.subMu.Unlock()
+
+	for i, sub := range t.subscribers {
+		if sub == ch {
+			t.subscribers = append(t.subscribers[:i], t.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+// GetHistory returns the recent process events in chronological order.
+func (t *ProcessEventTracker) GetHistory() []*models.ProcessEvent {
+	t.historyMu.RLock()
+	defer t.historyMu.RUnlock()
+
+	result := make([]*models.ProcessEvent, len(t.history))
+	copy(result, t.history)
+	return result
+}
+
+// containsString reports whether s is in list.
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
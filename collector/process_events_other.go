@@ -0,0 +1,9 @@
+//go:build !windows
+
+package collector
+
+// newExitStatusSource returns nil: the exit status of processes that are not
+// our children cannot be read once they are gone.
+func newExitStatusSource() exitStatusSource {
+	return nil
+}
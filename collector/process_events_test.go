@@ -0,0 +1,215 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// fakeExitStatus returns preset exit codes for tracked processes.
+type fakeExitStatus struct {
+	tracked map[int32]int
+	codes   map[int32]uint32
+}
+
+func (f *fakeExitStatus) track(pid int32) {
+	f.tracked[pid]++
+}
+
+func (f *fakeExitStatus) exitCode(pid int32) (uint32, bool) {
+	code, ok := f.codes[pid]
+	return code, ok && f.tracked[pid] > 0
+}
+
+func newTestEventTracker() (*ProcessEventTracker, *fakeExitStatus) {
+	exits := &fakeExitStatus{tracked: make(map[int32]int), codes: make(map[int32]uint32)}
+	t := NewProcessEventTracker()
+	t.exits = exits
+	return t, exits
+}
+
+// eventScan is a process list with the given names by PID.
+func eventScan(names map[int32]string) []models.ProcessInfo {
+	infos := make([]models.ProcessInfo, 0, len(names))
+	for pid, name := range names {
+		infos = append(infos, models.ProcessInfo{PID: pid, Name: name})
+	}
+	return infos
+}
+
+func TestProcessEventTracker(t *testing.T) {
+	tracker, _ := newTestEventTracker()
+	start := time.Now()
+
+	// The first scan is the baseline
+	tracker.Update(eventScan(map[int32]string{1: "init", 10: "game.exe"}), nil, start)
+	if events := tracker.GetHistory(); len(events) != 0 {
+		t.Fatalf("Expected no events from the baseline, got %d", len(events))
+	}
+
+	scan := []models.ProcessInfo{
+		{PID: 1, Name: "init"},
+		{PID: 10, Name: "game.exe", MemoryMB: 4000},
+		{PID: 20, Name: "helper.exe", ParentPID: 10, StartTime: start, MemoryMB: 100},
+	}
+	tracker.Update(scan, map[int32][]string{20: {"helper"}}, start.Add(time.Second))
+	scan[2].MemoryMB = 300
+	tracker.Update(scan, nil, start.Add(2*time.Second))
+	scan[2].MemoryMB = 200
+	tracker.Update(scan, nil, start.Add(3*time.Second))
+
+	tracker.Update(eventScan(map[int32]string{1: "init", 10: "game.exe"}), nil, start.Add(10*time.Second))
+
+	events := tracker.GetHistory()
+	if len(events) != 2 {
+		t.Fatalf("Expected a start and an exit, got %+v", events)
+	}
+	started, exited := events[0], events[1]
+	if started.Type != models.ProcessEventStart || started.PID != 20 || started.ParentPID != 10 {
+		t.Errorf("Unexpected start event: %+v", started)
+	}
+	if exited.Type != models.ProcessEventExit || exited.Name != "helper.exe" || exited.WatchIDs[0] != "helper" {
+		t.Errorf("Unexpected exit event: %+v", exited)
+	}
+	if exited.PeakMemoryMB != 300 || exited.LastMemoryMB != 200 || exited.Runtime != 10*time.Second {
+		t.Errorf("Unexpected exit details: %+v", exited)
+	}
+	if exited.ExitCode != nil || exited.Crashed {
+		t.Errorf("Expected an unknown exit status, got %+v", exited)
+	}
+}
+
+func TestProcessEventTrackerPIDReuse(t *testing.T) {
+	tracker, _ := newTestEventTracker()
+	now := time.Now()
+
+	tracker.Update(eventScan(map[int32]string{10: "game.exe"}), nil, now)
+	tracker.Update(eventScan(map[int32]string{10: "notepad.exe"}), nil, now.Add(time.Second))
+	// Same name again is the same process
+	tracker.Update(eventScan(map[int32]string{10: "notepad.exe"}), nil, now.Add(2*time.Second))
+
+	events := tracker.GetHistory()
+	if len(events) != 2 {
+		t.Fatalf("Expected an exit and a start for the reused PID, got %+v", events)
+	}
+	if events[0].Type != models.ProcessEventExit || events[0].Name != "game.exe" {
+		t.Errorf("Expected the old process to exit first, got %+v", events[0])
+	}
+	if events[1].Type != models.ProcessEventStart || events[1].Name != "notepad.exe" {
+		t.Errorf("Expected the new process to start, got %+v", events[1])
+	}
+}
+
+func TestProcessEventTrackerExitCodes(t *testing.T) {
+	tracker, exits := newTestEventTracker()
+	now := time.Now()
+
+	tracker.Update(eventScan(map[int32]string{10: "game.exe", 20: "tool.exe", 30: "svc.exe"}), nil, now)
+	exits.codes[10] = 0xC0000005
+	exits.codes[20] = 1
+	tracker.Update(nil, nil, now.Add(time.Second))
+
+	byPID := make(map[int32]*models.ProcessEvent)
+	for _, e := range tracker.GetHistory() {
+		byPID[e.PID] = e
+	}
+	if e := byPID[10]; e.ExitCode == nil || *e.ExitCode != 0xC0000005 || !e.Crashed {
+		t.Errorf("Expected an access violation crash, got %+v", e)
+	}
+	if e := byPID[20]; e.ExitCode == nil || *e.ExitCode != 1 || e.Crashed {
+		t.Errorf("Expected a normal exit with code 1, got %+v", e)
+	}
+	if e := byPID[30]; e.ExitCode != nil || e.Crashed {
+		t.Errorf("Expected an unknown exit status, got %+v", e)
+	}
+}
+
+func TestCrashExitCode(t *testing.T) {
+	tests := []struct {
+		code  uint32
+		crash bool
+	}{
+		{0, false},
+		{1, false},
+		{0x40010004, false}, // DBG_TERMINATE_PROCESS
+		{0xC0000005, true},  // access violation
+		{0xC0000409, true},  // stack buffer overrun
+		{0xE0434352, true},  // unhandled .NET exception
+	}
+	for _, tt := range tests {
+		if got := crashExitCode(tt.code); got != tt.crash {
+			t.Errorf("0x%08X: expected crash=%t", tt.code, tt.crash)
+		}
+	}
+}
+
+func TestProcessEventTrackerHistoryLimit(t *testing.T) {
+	tracker, _ := newTestEventTracker()
+	now := time.Now()
+
+	tracker.Update(nil, nil, now)
+	for i := 0; i < maxProcessEvents; i++ {
+		// Each scan replaces the previous process: one exit and one start
+		tracker.Update(eventScan(map[int32]string{int32(1000 + i): "worker"}), nil, now.Add(time.Duration(i+1)*time.Second))
+	}
+
+	events := tracker.GetHistory()
+	if len(events) != maxProcessEvents {
+		t.Fatalf("Expected %d events, got %d", maxProcessEvents, len(events))
+	}
+	if last := events[len(events)-1]; last.Type != models.ProcessEventStart || last.PID != 1000+maxProcessEvents-1 {
+		t.Errorf("Expected the newest event last, got %+v", last)
+	}
+	if first := events[0]; first.PID < 1000+maxProcessEvents/2-1 {
+		t.Errorf("Expected the oldest events to be dropped, got %+v", first)
+	}
+}
+
+func TestProcessEventTrackerSubscribe(t *testing.T) {
+	tracker, _ := newTestEventTracker()
+	now := time.Now()
+	tracker.Update(nil, nil, now)
+
+	ch := make(chan *models.ProcessEvent, 1)
+	tracker.Subscribe(ch)
+
+	// A full channel does not block the tracker
+	tracker.Update(eventScan(map[int32]string{1: "a", 2: "b"}), nil, now.Add(time.Second))
+	if len(ch) != 1 {
+		t.Fatalf("Expected one buffered event, got %d", len(ch))
+	}
+	if e := <-ch; e.PID != 1 {
+		t.Errorf("Expected events in PID order, got %+v", e)
+	}
+
+	tracker.Unsubscribe(ch)
+	tracker.Update(nil, nil, now.Add(2*time.Second))
+	if len(ch) != 0 {
+		t.Error("Expected no events after unsubscribing")
+	}
+}
+
+func TestProcessEventTrackerRestartWatchIDs(t *testing.T) {
+	tracker, _ := newTestEventTracker()
+	now := time.Now()
+	watched := map[int32][]string{10: {"game"}, 11: {"game"}}
+
+	tracker.Update(eventScan(map[int32]string{10: "game.exe"}), watched, now)
+	tracker.Update(eventScan(nil), watched, now.Add(time.Second))
+	tracker.Update(eventScan(map[int32]string{11: "game.exe"}), watched, now.Add(2*time.Second))
+	tracker.Update(eventScan(nil), watched, now.Add(3*time.Second))
+
+	events := tracker.GetHistory()
+	if len(events) != 3 {
+		t.Fatalf("Expected an exit, a start and an exit, got %+v", events)
+	}
+	for _, event := range events {
+		if len(event.WatchIDs) != 1 || event.WatchIDs[0] != "game" {
+			t.Errorf("Expected the watch ID on the %s event of PID %d, got %v", event.Type, event.PID, event.WatchIDs)
+		}
+	}
+	if events[1].Type != models.ProcessEventStart || events[1].PID != 11 {
+		t.Errorf("Expected the restart in the middle, got %+v", events[1])
+	}
+}
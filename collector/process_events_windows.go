@@ -0,0 +1,50 @@
+//go:build windows
+
+package collector
+
+import "syscall"
+
+const (
+	processQueryLimitedInformation = 0x1000
+	// stillActive is returned by GetExitCodeProcess for running processes.
+	stillActive = 259
+)
+
+// handleExitStatus keeps a handle to every tracked process. The process
+// object, and with it the exit code, lives as long as a handle is open, and
+// Windows does not reuse the PID meanwhile.
+type handleExitStatus struct {
+	handles map[int32]syscall.Handle
+}
+
+// newExitStatusSource returns the exit status source using process handles.
+func newExitStatusSource() exitStatusSource {
+	return &handleExitStatus{handles: make(map[int32]syscall.Handle)}
+}
+
+func (s *handleExitStatus) track(pid int32) {
+	if old, ok := s.handles[pid]; ok {
+		syscall.CloseHandle(old)
+		delete(s.handles, pid)
+	}
+	// Fails for protected system processes; their exit code stays unknown
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err == nil {
+		s.handles[pid] = h
+	}
+}
+
+func (s *handleExitStatus) exitCode(pid int32) (uint32, bool) {
+	h, ok := s.handles[pid]
+	if !ok {
+		return 0, false
+	}
+	delete(s.handles, pid)
+	defer syscall.CloseHandle(h)
+
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil || code == stillActive {
+		return 0, false
+	}
+	return code, true
+}
@@ -0,0 +1,465 @@
+package collector
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// defaultProcRoot is the procfs mount point.
+const defaultProcRoot = "/proc"
+
+// processGPUUsage is the GPU usage of a single process.
+type processGPUUsage struct {
+	percent  float64
+	engine   string
+	memoryMB uint64
+}
+
+// ProcessGPUCollector collects per-process GPU engine utilisation and video
+// memory usage.
+type ProcessGPUCollector struct {
+	sample func(now time.Time) map[int32]processGPUUsage
+	mu     sync.Mutex
+}
+
+// NewProcessGPUCollector creates a new per-process GPU collector.
+func NewProcessGPUCollector(cfg *config.ProcessGPUConfig) *ProcessGPUCollector {
+	root := cfg.Root
+	if root == "" {
+		root = defaultProcRoot
+	}
+	return &ProcessGPUCollector{sample: newProcessGPUSampler(root)}
+}
+
+// Collect returns the GPU usage of all processes using the GPU by PID.
+func (c *ProcessGPUCollector) Collect() map[int32]processGPUUsage {
+	if c == nil || c.sample == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sample(time.Now())
+}
+
+// SetGPU sets the per-process GPU collector. Passing nil turns per-process
+// GPU monitoring off.
+func (c *ProcessCollector) SetGPU(gpu *ProcessGPUCollector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.gpu = gpu
+	if gpu == nil {
+		c.topGPU = nil
+	}
+}
+
+// GetTopByGPU returns the processes using the GPU computed by the last
+// Collect call, busiest first. Returns nil if per-process GPU monitoring is
+// disabled.
+func (c *ProcessCollector) GetTopByGPU() []models.ProcessInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.topGPU == nil {
+		return nil
+	}
+
+	top := make([]models.ProcessInfo, len(c.topGPU))
+	copy(top, c.topGPU)
+	return top
+}
+
+// applyGPUUsage fills the GPU fields of a process.
+func applyGPUUsage(info *models.ProcessInfo, usage map[int32]processGPUUsage) {
+	if u, ok := usage[info.PID]; ok {
+		info.GPUPercent = u.percent
+		info.GPUEngine = u.engine
+		info.GPUMemoryMB = u.memoryMB
+	}
+}
+
+// topByGPU returns up to limit processes using the GPU, ordered by engine
+// utilisation and then by video memory.
+func topByGPU(infos []models.ProcessInfo, limit int) []models.ProcessInfo {
+	var top []models.ProcessInfo
+	for _, info := range infos {
+		if info.GPUPercent > 0 || info.GPUMemoryMB > 0 {
+			top = append(top, info)
+		}
+	}
+
+	sort.SliceStable(top, func(i, j int) bool {
+		if top[i].GPUPercent != top[j].GPUPercent {
+			return top[i].GPUPercent > top[j].GPUPercent
+		}
+		return top[i].GPUMemoryMB > top[j].GPUMemoryMB
+	})
+
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+// engineCounter is a cumulative busy counter of a GPU engine. DRM drivers
+// report either busy time (ns, compared to wall time) or busy cycles
+// (compared to the total cycles elapsed).
+type engineCounter struct {
+	busy     uint64
+	total    uint64
+	capacity uint64
+}
+
+// drmClient is the parsed fdinfo of an open DRM file.
+type drmClient struct {
+	key     string
+	engines map[string]engineCounter
+	vramKB  uint64
+}
+
+// drmProcessSample is the summed usage of all DRM clients of a process.
+type drmProcessSample struct {
+	engines map[string]engineCounter
+	vramKB  uint64
+}
+
+// drmGPUReader computes per-process GPU usage from DRM fdinfo
+// (drm-engine-*, drm-cycles-*, drm-memory-* keys).
+type drmGPUReader struct {
+	procRoot string
+	last     map[int32]drmProcessSample
+	lastAt   time.Time
+}
+
+// newDRMGPUReader creates a DRM fdinfo reader for the given procfs root.
+func newDRMGPUReader(procRoot string) *drmGPUReader {
+	return &drmGPUReader{
+		procRoot: procRoot,
+		last:     make(map[int32]drmProcessSample),
+	}
+}
+
+// read samples all processes and returns the usage since the previous call.
+// The first sample of a process only reports its memory usage.
+func (r *drmGPUReader) read(now time.Time) map[int32]processGPUUsage {
+	entries, err := os.ReadDir(r.procRoot)
+	if err != nil {
+		return nil
+	}
+
+	elapsed := uint64(now.Sub(r.lastAt).Nanoseconds())
+	current := make(map[int32]drmProcessSample)
+	usage := make(map[int32]processGPUUsage)
+
+	for _, entry := range entries {
+		pid, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if err != nil {
+			continue
+		}
+		sample, ok := readDRMProcess(filepath.Join(r.procRoot, entry.Name()))
+		if !ok {
+			continue
+		}
+		current[int32(pid)] = sample
+
+		u := processGPUUsage{memoryMB: sample.vramKB / 1024}
+		if prev, ok := r.last[int32(pid)]; ok && !r.lastAt.IsZero() {
+			u.percent, u.engine = busiestEngine(prev.engines, sample.engines, elapsed)
+		}
+		usage[int32(pid)] = u
+	}
+
+	r.last = current
+	r.lastAt = now
+	return usage
+}
+
+// busiestEngine returns the utilisation of the busiest engine between two
+// samples taken elapsed nanoseconds apart.
+func busiestEngine(prev, cur map[string]engineCounter, elapsed uint64) (float64, string) {
+	var best float64
+	var bestName string
+
+	names := make([]string, 0, len(cur))
+	for name := range cur {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c := cur[name]
+		p, ok := prev[name]
+		if !ok || c.busy < p.busy {
+			continue
+		}
+
+		// Time-based counters are compared to wall time
+		span := elapsed
+		if c.total > 0 {
+			if c.total <= p.total {
+				continue
+			}
+			span = c.total - p.total
+		}
+		if span == 0 {
+			continue
+		}
+
+		percent := float64(c.busy-p.busy) / float64(span) * 100
+		if c.capacity > 1 {
+			percent /= float64(c.capacity)
+		}
+		if percent > 100 {
+			percent = 100
+		}
+		if percent > best {
+			best = percent
+			bestName = name
+		}
+	}
+
+	return best, bestName
+}
+
+// readDRMProcess sums the fdinfo of all DRM files a process has open.
+// Files sharing a DRM client (duplicated descriptors) are counted once.
+func readDRMProcess(dir string) (drmProcessSample, bool) {
+	fds, err := os.ReadDir(filepath.Join(dir, "fd"))
+	if err != nil {
+		return drmProcessSample{}, false
+	}
+
+	sample := drmProcessSample{engines: make(map[string]engineCounter)}
+	seen := make(map[string]bool)
+
+	for _, fd := range fds {
+		target, err := os.Readlink(filepath.Join(dir, "fd", fd.Name()))
+		if err != nil || !strings.HasPrefix(target, "/dev/dri/") {
+			continue
+		}
+		client, ok := readDRMFdinfo(filepath.Join(dir, "fdinfo", fd.Name()))
+		if !ok || seen[client.key] {
+			continue
+		}
+		seen[client.key] = true
+
+		for name, e := range client.engines {
+			sum := sample.engines[name]
+			sum.busy += e.busy
+			sum.total = max(sum.total, e.total)
+			sum.capacity = max(sum.capacity, e.capacity)
+			sample.engines[name] = sum
+		}
+		sample.vramKB += client.vramKB
+	}
+
+	return sample, len(seen) > 0
+}
+
+// readDRMFdinfo parses a DRM fdinfo file, e.g.:
+//
+//	drm-driver:	amdgpu
+//	drm-pdev:	0000:03:00.0
+//	drm-client-id:	42
+//	drm-engine-gfx:	1234567 ns
+//	drm-memory-vram:	204800 KiB
+func readDRMFdinfo(path string) (drmClient, bool) {
+	file, err := os.Open(path)
+	if err != nil {
+		return drmClient{}, false
+	}
+	defer file.Close()
+
+	client := drmClient{engines: make(map[string]engineCounter)}
+	var pdev, clientID string
+	var resident, memory, total uint64
+	var hasResident, hasMemory bool
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || !strings.HasPrefix(key, "drm-") {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch {
+		case key == "drm-pdev":
+			pdev = value
+		case key == "drm-client-id":
+			clientID = value
+		case strings.HasPrefix(key, "drm-engine-capacity-"):
+			name := strings.TrimPrefix(key, "drm-engine-capacity-")
+			e := client.engines[name]
+			e.capacity = parseDRMUint(value)
+			client.engines[name] = e
+		case strings.HasPrefix(key, "drm-engine-"):
+			name := strings.TrimPrefix(key, "drm-engine-")
+			e := client.engines[name]
+			e.busy = parseDRMUint(value)
+			client.engines[name] = e
+		case strings.HasPrefix(key, "drm-total-cycles-"):
+			name := strings.TrimPrefix(key, "drm-total-cycles-")
+			e := client.engines[name]
+			e.total = parseDRMUint(value)
+			client.engines[name] = e
+		case strings.HasPrefix(key, "drm-cycles-"):
+			name := strings.TrimPrefix(key, "drm-cycles-")
+			e := client.engines[name]
+			e.busy = parseDRMUint(value)
+			client.engines[name] = e
+		case strings.HasPrefix(key, "drm-resident-"):
+			if isVRAMRegion(strings.TrimPrefix(key, "drm-resident-")) {
+				resident += parseDRMSizeKB(value)
+				hasResident = true
+			}
+		case strings.HasPrefix(key, "drm-memory-"):
+			if isVRAMRegion(strings.TrimPrefix(key, "drm-memory-")) {
+				memory += parseDRMSizeKB(value)
+				hasMemory = true
+			}
+		case strings.HasPrefix(key, "drm-total-"):
+			if isVRAMRegion(strings.TrimPrefix(key, "drm-total-")) {
+				total += parseDRMSizeKB(value)
+			}
+		}
+	}
+
+	// Without a client ID the file carries no usage statistics
+	if clientID == "" {
+		return drmClient{}, false
+	}
+	client.key = pdev + "/" + clientID
+
+	// Prefer memory actually resident in VRAM over allocated memory
+	switch {
+	case hasResident:
+		client.vramKB = resident
+	case hasMemory:
+		client.vramKB = memory
+	default:
+		client.vramKB = total
+	}
+
+	return client, true
+}
+
+// isVRAMRegion reports whether a DRM memory region is dedicated video memory
+// ("vram" on amdgpu and xe, "local" on discrete i915).
+func isVRAMRegion(region string) bool {
+	return strings.HasPrefix(region, "vram") || strings.HasPrefix(region, "local")
+}
+
+// parseDRMUint parses the leading number of a DRM fdinfo value.
+func parseDRMUint(value string) uint64 {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+	v, _ := strconv.ParseUint(fields[0], 10, 64)
+	return v
+}
+
+// parseDRMSizeKB parses a DRM memory value ("1024 KiB", "4 MiB" or bytes)
+// into KiB.
+func parseDRMSizeKB(value string) uint64 {
+	fields := strings.Fields(value)
+	if len(fields) == 0 {
+		return 0
+	}
+	v, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	if len(fields) < 2 {
+		return v / 1024
+	}
+	switch fields[1] {
+	case "KiB":
+		return v
+	case "MiB":
+		return v * 1024
+	case "GiB":
+		return v * 1024 * 1024
+	}
+	return v / 1024
+}
+
+// gpuCounterValue is a value of a per-instance GPU performance counter.
+type gpuCounterValue struct {
+	instance string
+	value    float64
+}
+
+// parseGPUInstance extracts the PID and engine type from a Windows GPU
+// performance counter instance, e.g.
+// "pid_1234_luid_0x00000000_0x0000C2A4_phys_0_eng_0_engtype_3D".
+func parseGPUInstance(instance string) (pid int32, engine string, ok bool) {
+	rest, found := strings.CutPrefix(instance, "pid_")
+	if !found {
+		return 0, "", false
+	}
+	digits, _, _ := strings.Cut(rest, "_")
+	v, err := strconv.ParseInt(digits, 10, 32)
+	if err != nil {
+		return 0, "", false
+	}
+	if _, e, found := strings.Cut(rest, "_engtype_"); found {
+		engine = e
+	}
+	return int32(v), engine, true
+}
+
+// gpuUsageFromCounters combines "GPU Engine" utilisation (percent) and
+// "GPU Process Memory" dedicated usage (bytes) counters into per-process
+// usage. Utilisation is summed per engine type, as Task Manager does, and
+// the busiest engine type is reported.
+func gpuUsageFromCounters(engines, memory []gpuCounterValue) map[int32]processGPUUsage {
+	perEngine := make(map[int32]map[string]float64)
+	for _, c := range engines {
+		pid, engine, ok := parseGPUInstance(c.instance)
+		if !ok || pid == 0 || engine == "" {
+			continue
+		}
+		if perEngine[pid] == nil {
+			perEngine[pid] = make(map[string]float64)
+		}
+		perEngine[pid][engine] += c.value
+	}
+
+	usage := make(map[int32]processGPUUsage)
+	for pid, byEngine := range perEngine {
+		var u processGPUUsage
+		for engine, percent := range byEngine {
+			if percent > u.percent || (percent == u.percent && engine < u.engine) {
+				u.percent = percent
+				u.engine = engine
+			}
+		}
+		if u.percent > 100 {
+			u.percent = 100
+		}
+		usage[pid] = u
+	}
+
+	for _, c := range memory {
+		pid, _, ok := parseGPUInstance(c.instance)
+		if !ok || pid == 0 {
+			continue
+		}
+		u := usage[pid]
+		u.memoryMB += uint64(c.value) / (1024 * 1024)
+		usage[pid] = u
+	}
+
+	return usage
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package collector
+
+import "time"
+
+// newProcessGPUSampler returns the per-process GPU sampler, which reads DRM
+// fdinfo from procfs.
+func newProcessGPUSampler(procRoot string) func(now time.Time) map[int32]processGPUUsage {
+	return newDRMGPUReader(procRoot).read
+}
@@ -0,0 +1,231 @@
+package collector
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// writeDRMProcess creates a fake /proc/<pid> with the given descriptors.
+// Each descriptor maps to its link target and fdinfo content.
+func writeDRMProcess(t *testing.T, root string, pid int, fds map[string][2]string) {
+	t.Helper()
+
+	dir := filepath.Join(root, fmt.Sprint(pid))
+	files := make(map[string]string)
+	for fd, entry := range fds {
+		files[filepath.Join("fdinfo", fd)] = entry[1]
+	}
+	writeSysfsFiles(t, dir, files)
+
+	if err := os.MkdirAll(filepath.Join(dir, "fd"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for fd, entry := range fds {
+		link := filepath.Join(dir, "fd", fd)
+		os.Remove(link)
+		if err := os.Symlink(entry[0], link); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func amdgpuFdinfo(clientID int, gfxNs uint64, vramKB uint64) string {
+	return fmt.Sprintf(`pos:	0
+flags:	02100002
+drm-driver:	amdgpu
+drm-pdev:	0000:03:00.0
+drm-client-id:	%d
+drm-engine-gfx:	%d ns
+drm-engine-compute:	0 ns
+drm-memory-vram:	%d KiB
+drm-memory-gtt:	4096 KiB`, clientID, gfxNs, vramKB)
+}
+
+func TestReadDRMFdinfo(t *testing.T) {
+	dir := t.TempDir()
+	writeSysfsFiles(t, dir, map[string]string{
+		"amdgpu": amdgpuFdinfo(7, 5000000, 204800),
+		"i915": `drm-driver:	i915
+drm-pdev:	0000:00:02.0
+drm-client-id:	12
+drm-engine-render:	25662044495 ns
+drm-engine-video:	0 ns
+drm-engine-capacity-video:	2
+drm-total-system0:	1024 KiB
+drm-total-local0:	64 MiB
+drm-resident-local0:	32 MiB`,
+		"xe": `drm-driver:	xe
+drm-client-id:	3
+drm-cycles-rcs:	1000
+drm-total-cycles-rcs:	5000
+drm-total-vram0:	2097152`,
+		"noclient": `drm-driver:	i915
+drm-pdev:	0000:00:02.0`,
+	})
+
+	amd, ok := readDRMFdinfo(filepath.Join(dir, "amdgpu"))
+	if !ok || amd.key != "0000:03:00.0/7" {
+		t.Fatalf("Unexpected amdgpu client: %+v", amd)
+	}
+	if amd.engines["gfx"].busy != 5000000 || amd.vramKB != 204800 {
+		t.Errorf("Unexpected amdgpu values: %+v", amd)
+	}
+
+	// Resident VRAM is preferred over the allocated total; system memory is ignored
+	i915, ok := readDRMFdinfo(filepath.Join(dir, "i915"))
+	if !ok || i915.vramKB != 32*1024 {
+		t.Errorf("Expected 32 MiB resident VRAM, got %+v", i915)
+	}
+	if i915.engines["video"].capacity != 2 || i915.engines["render"].busy != 25662044495 {
+		t.Errorf("Unexpected i915 engines: %+v", i915.engines)
+	}
+
+	// Cycle counters and sizes in bytes
+	xe, ok := readDRMFdinfo(filepath.Join(dir, "xe"))
+	if !ok || xe.engines["rcs"] != (engineCounter{busy: 1000, total: 5000}) || xe.vramKB != 2048 {
+		t.Errorf("Unexpected xe client: %+v", xe)
+	}
+
+	if _, ok := readDRMFdinfo(filepath.Join(dir, "noclient")); ok {
+		t.Error("Expected fdinfo without a client ID to be skipped")
+	}
+}
+
+func TestDRMGPUReader(t *testing.T) {
+	root := t.TempDir()
+	render := "/dev/dri/renderD128"
+
+	// The game has two descriptors for the same client plus a regular file
+	writeDRMProcess(t, root, 100, map[string][2]string{
+		"3": {render, amdgpuFdinfo(1, 0, 1048576)},
+		"4": {render, amdgpuFdinfo(1, 0, 1048576)},
+		"5": {"/tmp/log.txt", "pos:	0"},
+	})
+	// The browser has two separate clients
+	writeDRMProcess(t, root, 200, map[string][2]string{
+		"10": {render, amdgpuFdinfo(2, 0, 102400)},
+		"11": {render, amdgpuFdinfo(3, 0, 102400)},
+	})
+	// A process without DRM files
+	writeDRMProcess(t, root, 300, map[string][2]string{
+		"0": {"/dev/null", ""},
+	})
+	writeSysfsFiles(t, root, map[string]string{"self/status": "x"})
+
+	r := newDRMGPUReader(root)
+	start := time.Now()
+	usage := r.read(start)
+	if len(usage) != 2 {
+		t.Fatalf("Expected 2 GPU processes, got %+v", usage)
+	}
+	if usage[100].memoryMB != 1024 || usage[100].percent != 0 {
+		t.Errorf("Expected memory only on the first sample, got %+v", usage[100])
+	}
+	if usage[200].memoryMB != 200 {
+		t.Errorf("Expected both browser clients to be summed, got %+v", usage[200])
+	}
+
+	// One second later the game kept gfx busy for 750 ms, the browser
+	// clients for 100 ms each
+	writeDRMProcess(t, root, 100, map[string][2]string{
+		"3": {render, amdgpuFdinfo(1, 750000000, 1048576)},
+		"4": {render, amdgpuFdinfo(1, 750000000, 1048576)},
+	})
+	writeDRMProcess(t, root, 200, map[string][2]string{
+		"10": {render, amdgpuFdinfo(2, 100000000, 102400)},
+		"11": {render, amdgpuFdinfo(3, 100000000, 102400)},
+	})
+
+	usage = r.read(start.Add(time.Second))
+	if u := usage[100]; u.percent < 74.9 || u.percent > 75.1 || u.engine != "gfx" {
+		t.Errorf("Expected the game at 75%% gfx, got %+v", u)
+	}
+	if u := usage[200]; u.percent < 19.9 || u.percent > 20.1 {
+		t.Errorf("Expected the browser at 20%% gfx, got %+v", u)
+	}
+}
+
+func TestBusiestEngine(t *testing.T) {
+	prev := map[string]engineCounter{
+		"render": {busy: 0},
+		"video":  {busy: 0, capacity: 2},
+		"rcs":    {busy: 100, total: 1000},
+		"gone":   {busy: 500},
+	}
+	cur := map[string]engineCounter{
+		"render": {busy: 200000000},
+		"video":  {busy: 1000000000, capacity: 2},
+		"rcs":    {busy: 700, total: 2000},
+		"gone":   {busy: 100},
+		"new":    {busy: 999999999999},
+	}
+
+	// video: 1 s over 1 s on 2 engines = 50%; rcs: 600/1000 cycles = 60%
+	percent, engine := busiestEngine(prev, cur, uint64(time.Second))
+	if engine != "rcs" || percent < 59.9 || percent > 60.1 {
+		t.Errorf("Expected rcs at 60%%, got %s at %.1f%%", engine, percent)
+	}
+
+	if percent, _ := busiestEngine(prev, cur, 0); percent != 60 {
+		t.Errorf("Expected only cycle counters without elapsed time, got %.1f%%", percent)
+	}
+}
+
+func TestGPUUsageFromCounters(t *testing.T) {
+	engines := []gpuCounterValue{
+		{"pid_1234_luid_0x00000000_0x0000C2A4_phys_0_eng_0_engtype_3D", 40},
+		{"pid_1234_luid_0x00000000_0x0000C2A4_phys_0_eng_1_engtype_3D", 25},
+		{"pid_1234_luid_0x00000000_0x0000C2A4_phys_0_eng_4_engtype_VideoDecode", 10},
+		{"pid_5678_luid_0x00000000_0x0000C2A4_phys_0_eng_5_engtype_VideoDecode", 30},
+		{"pid_0_luid_0x00000000_0x0000C2A4_phys_0_eng_0_engtype_3D", 5},
+		{"_Total", 80},
+	}
+	memory := []gpuCounterValue{
+		{"pid_1234_luid_0x00000000_0x0000C2A4_phys_0", 2 * 1024 * 1024 * 1024},
+		{"pid_9999_luid_0x00000000_0x0000C2A4_phys_0", 300 * 1024 * 1024},
+	}
+
+	usage := gpuUsageFromCounters(engines, memory)
+	if len(usage) != 3 {
+		t.Fatalf("Expected 3 processes, got %+v", usage)
+	}
+	if u := usage[1234]; u.percent != 65 || u.engine != "3D" || u.memoryMB != 2048 {
+		t.Errorf("Unexpected game usage: %+v", u)
+	}
+	if u := usage[5678]; u.percent != 30 || u.engine != "VideoDecode" {
+		t.Errorf("Unexpected browser usage: %+v", u)
+	}
+	if u := usage[9999]; u.percent != 0 || u.memoryMB != 300 {
+		t.Errorf("Unexpected memory-only usage: %+v", u)
+	}
+}
+
+func TestTopByGPU(t *testing.T) {
+	usage := map[int32]processGPUUsage{
+		1: {percent: 10, engine: "3D", memoryMB: 100},
+		2: {percent: 60, engine: "3D", memoryMB: 4000},
+		3: {memoryMB: 500},
+	}
+	infos := []models.ProcessInfo{{PID: 1}, {PID: 2}, {PID: 3}, {PID: 4}}
+	for i := range infos {
+		applyGPUUsage(&infos[i], usage)
+	}
+
+	top := topByGPU(infos, 2)
+	if len(top) != 2 || top[0].PID != 2 || top[1].PID != 1 {
+		t.Fatalf("Unexpected ranking: %+v", top)
+	}
+	if top[0].GPUEngine != "3D" || top[0].GPUMemoryMB != 4000 {
+		t.Errorf("Expected GPU fields to be applied, got %+v", top[0])
+	}
+
+	// Processes without GPU usage are not ranked
+	if all := topByGPU(infos, 10); len(all) != 3 || all[2].PID != 3 {
+		t.Errorf("Expected 3 GPU processes, memory-only last, got %+v", all)
+	}
+}
@@ -0,0 +1,102 @@
+//go:build windows
+
+package collector
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var procPdhGetFormattedCounterArrayW = pdh.NewProc("PdhGetFormattedCounterArrayW")
+
+// pdhFmtCounterValueItem mirrors PDH_FMT_COUNTERVALUE_ITEM_W.
+type pdhFmtCounterValueItem struct {
+	Name  *uint16
+	Value PDH_FMT_COUNTERVALUE
+}
+
+// pdhProcessGPU reads per-process GPU usage from the wildcard "GPU Engine"
+// and "GPU Process Memory" counters, whose instances carry the process ID.
+type pdhProcessGPU struct {
+	query   uintptr
+	engines uintptr
+	memory  uintptr
+}
+
+// newProcessGPUSampler returns the per-process GPU sampler, which reads the
+// GPU performance counters. Returns nil when they are unavailable.
+func newProcessGPUSampler(procRoot string) func(now time.Time) map[int32]processGPUUsage {
+	p := &pdhProcessGPU{}
+	ret, _, _ := procPdhOpenQuery.Call(0, 0, uintptr(unsafe.Pointer(&p.query)))
+	if ret != 0 {
+		return nil
+	}
+
+	ret, _, _ = procPdhAddCounterW.Call(p.query,
+		uintptr(unsafe.Pointer(utf16PtrFromString(`\GPU Engine(*)\Utilization Percentage`))),
+		0, uintptr(unsafe.Pointer(&p.engines)))
+	if ret != 0 {
+		procPdhCloseQuery.Call(p.query)
+		return nil
+	}
+	// Dedicated memory counters are missing on some drivers
+	procPdhAddCounterW.Call(p.query,
+		uintptr(unsafe.Pointer(utf16PtrFromString(`\GPU Process Memory(*)\Dedicated Usage`))),
+		0, uintptr(unsafe.Pointer(&p.memory)))
+
+	// Prime the rate counters
+	procPdhCollectQueryData.Call(p.query)
+	return p.sample
+}
+
+// sample collects the counters and combines them per process.
+func (p *pdhProcessGPU) sample(now time.Time) map[int32]processGPUUsage {
+	if ret, _, _ := procPdhCollectQueryData.Call(p.query); ret != 0 {
+		return nil
+	}
+	return gpuUsageFromCounters(readCounterArray(p.engines), readCounterArray(p.memory))
+}
+
+// readCounterArray reads all instances of a wildcard counter.
+func readCounterArray(counter uintptr) []gpuCounterValue {
+	if counter == 0 {
+		return nil
+	}
+
+	var size, count uint32
+	ret, _, _ := procPdhGetFormattedCounterArrayW.Call(counter, PDH_FMT_DOUBLE,
+		uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&count)), 0)
+	if ret != PDH_MORE_DATA || size == 0 {
+		return nil
+	}
+
+	buf := make([]byte, size)
+	ret, _, _ = procPdhGetFormattedCounterArrayW.Call(counter, PDH_FMT_DOUBLE,
+		uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&count)), uintptr(unsafe.Pointer(&buf[0])))
+	if ret != 0 {
+		return nil
+	}
+
+	items := unsafe.Slice((*pdhFmtCounterValueItem)(unsafe.Pointer(&buf[0])), count)
+	values := make([]gpuCounterValue, 0, count)
+	for _, item := range items {
+		if item.Value.CStatus != 0 || item.Name == nil {
+			continue
+		}
+		values = append(values, gpuCounterValue{
+			instance: syscall.UTF16ToString(unsafe.Slice(item.Name, utf16Len(item.Name))),
+			value:    item.Value.DoubleValue,
+		})
+	}
+	return values
+}
+
+// utf16Len returns the length of a NUL-terminated UTF-16 string.
+func utf16Len(p *uint16) int {
+	n := 0
+	for ptr := unsafe.Pointer(p); *(*uint16)(ptr) != 0; ptr = unsafe.Add(ptr, 2) {
+		n++
+	}
+	return n
+}
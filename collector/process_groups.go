@@ -0,0 +1,203 @@
+package collector
+
+import (
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// maxTreeDepth limits the parent walk when resolving a process tree root.
+const maxTreeDepth = 64
+
+// processIOSample is a previous I/O counter reading used for rate calculation.
+type processIOSample struct {
+	readBytes  uint64
+	writeBytes uint64
+	time       time.Time
+}
+
+// SetGrouping sets the process grouping configuration.
+// Passing nil or a disabled config turns grouping off.
+func (c *ProcessCollector) SetGrouping(cfg *config.ProcessGroupingConfig) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.grouping = cfg
+	if cfg == nil || !cfg.Enabled {
+		c.groups = nil
+		c.lastIO = make(map[int32]processIOSample)
+	}
+}
+
+// GetGroups returns the process groups computed by the last Collect call.
+// Returns nil if grouping is disabled.
+func (c *ProcessCollector) GetGroups() []models.ProcessGroupInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.groups == nil {
+		return nil
+	}
+
+	groups := make([]models.ProcessGroupInfo, len(c.groups))
+	copy(groups, c.groups)
+	return groups
+}
+
+// addGroupingDetails fills the thread count and disk I/O rates of a process.
+// These calls are expensive, so they are only made when grouping is enabled.
+func (c *ProcessCollector) addGroupingDetails(p *process.Process, info *models.ProcessInfo, now time.Time) {
+	if threads, err := p.NumThreads(); err == nil {
+		info.Threads = threads
+	}
+
+	io, err := p.IOCounters()
+	if err != nil || io == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if last, ok := c.lastIO[p.Pid]; ok {
+		elapsed := now.Sub(last.time).Seconds()
+		if elapsed > 0 && io.ReadBytes >= last.readBytes && io.WriteBytes >= last.writeBytes {
+			info.DiskReadKBps = float64(io.ReadBytes-last.readBytes) / elapsed / 1024
+			info.DiskWriteKBps = float64(io.WriteBytes-last.writeBytes) / elapsed / 1024
+		}
+	}
+
+	c.lastIO[p.Pid] = processIOSample{
+		readBytes:  io.ReadBytes,
+		writeBytes: io.WriteBytes,
+		time:       now,
+	}
+}
+
+// ResetIOSamples discards per-process I/O samples so that the next rates
+// are not computed across a collection gap.
+func (c *ProcessCollector) ResetIOSamples() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastIO = make(map[int32]processIOSample)
+}
+
+// pruneIOSamples drops I/O samples of processes that no longer exist.
+func (c *ProcessCollector) pruneIOSamples(alive map[int32]bool) {
+	for pid := range c.lastIO {
+		if !alive[pid] {
+			delete(c.lastIO, pid)
+		}
+	}
+}
+
+// groupKey normalizes an executable name for grouping (lowercase, without ".exe").
+func groupKey(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".exe")
+}
+
+// matchGroupRule returns the name of the first rule matching the executable key.
+func matchGroupRule(key string, rules []config.ProcessGroupRule) (string, bool) {
+	for _, rule := range rules {
+		for _, pattern := range rule.Match {
+			if ok, _ := path.Match(groupKey(pattern), key); ok {
+				return rule.Name, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findTreeRoot walks up the parent chain of pid and returns the topmost
+// ancestor that is still a known process and not a tree boundary.
+func findTreeRoot(pid int32, byPID map[int32]*models.ProcessInfo, boundaries map[string]bool) int32 {
+	root := pid
+	for depth := 0; depth < maxTreeDepth; depth++ {
+		ppid := byPID[root].ParentPID
+		if ppid <= 0 || ppid == root {
+			break
+		}
+		parent, ok := byPID[ppid]
+		if !ok || boundaries[groupKey(parent.Name)] {
+			break
+		}
+		root = ppid
+	}
+	return root
+}
+
+// groupProcesses aggregates processes into groups according to cfg and returns
+// the top groups by CPU usage.
+func groupProcesses(infos []models.ProcessInfo, cfg *config.ProcessGroupingConfig, limit int) []models.ProcessGroupInfo {
+	byPID := make(map[int32]*models.ProcessInfo, len(infos))
+	for i := range infos {
+		byPID[infos[i].PID] = &infos[i]
+	}
+
+	boundaries := make(map[string]bool, len(cfg.TreeBoundaries))
+	for _, name := range cfg.TreeBoundaries {
+		boundaries[groupKey(name)] = true
+	}
+
+	groups := make(map[string]*models.ProcessGroupInfo)
+	order := make([]string, 0)
+
+	for i := range infos {
+		info := &infos[i]
+		key := groupKey(info.Name)
+
+		var id, name string
+		var rootPID int32
+		if ruleName, ok := matchGroupRule(key, cfg.Rules); ok {
+			id, name = "rule:"+ruleName, ruleName
+		} else if cfg.Mode == "tree" {
+			rootPID = findTreeRoot(info.PID, byPID, boundaries)
+			id, name = "tree:"+strconv.Itoa(int(rootPID)), byPID[rootPID].Name
+		} else {
+			id, name = "exe:"+key, info.Name
+		}
+
+		group, ok := groups[id]
+		if !ok {
+			group = &models.ProcessGroupInfo{Name: name, RootPID: rootPID}
+			groups[id] = group
+			order = append(order, id)
+		}
+
+		group.ProcessCount++
+		group.PIDs = append(group.PIDs, info.PID)
+		group.CPUPercent += info.CPUPercent
+		group.MemoryMB += info.MemoryMB
+		group.Threads += info.Threads
+		group.DiskReadKBps += info.DiskReadKBps
+		group.DiskWriteKBps += info.DiskWriteKBps
+	}
+
+	result := make([]models.ProcessGroupInfo, 0, len(groups))
+	for _, id := range order {
+		group := groups[id]
+		sort.Slice(group.PIDs, func(i, j int) bool { return group.PIDs[i] < group.PIDs[j] })
+		result = append(result, *group)
+	}
+
+	// Sort by CPU usage, then memory (descending)
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].CPUPercent != result[j].CPUPercent {
+			return result[i].CPUPercent > result[j].CPUPercent
+		}
+		return result[i].MemoryMB > result[j].MemoryMB
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+
+	return result
+}
@@ -0,0 +1,173 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// groupingProcesses is a small process tree:
+//
+//	1 init
+//	└─ 10 explorer.exe
+//	   ├─ 20 chrome.exe ─┬─ 21 chrome.exe
+//	   │                 └─ 22 Chrome.EXE
+//	   └─ 30 steam.exe ─── 31 steamwebhelper.exe
+//	40 game.exe (parent 99 is gone)
+func groupingProcesses() []models.ProcessInfo {
+	return []models.ProcessInfo{
+		{PID: 1, Name: "init", CPUPercent: 0.1, MemoryMB: 10},
+		{PID: 10, Name: "explorer.exe", ParentPID: 1, CPUPercent: 1, MemoryMB: 100},
+		{PID: 20, Name: "chrome.exe", ParentPID: 10, CPUPercent: 5, MemoryMB: 300, Threads: 20},
+		{PID: 21, Name: "chrome.exe", ParentPID: 20, CPUPercent: 10, MemoryMB: 200, Threads: 10},
+		{PID: 22, Name: "Chrome.EXE", ParentPID: 20, CPUPercent: 2, MemoryMB: 100, Threads: 5},
+		{PID: 30, Name: "steam.exe", ParentPID: 10, CPUPercent: 3, MemoryMB: 150},
+		{PID: 31, Name: "steamwebhelper.exe", ParentPID: 30, CPUPercent: 4, MemoryMB: 250},
+		{PID: 40, Name: "game.exe", ParentPID: 99, CPUPercent: 50, MemoryMB: 4000},
+	}
+}
+
+// groupsByName maps group names to their sorted PIDs.
+func groupsByName(groups []models.ProcessGroupInfo) map[string][]int32 {
+	result := make(map[string][]int32, len(groups))
+	for _, g := range groups {
+		result[g.Name] = g.PIDs
+	}
+	return result
+}
+
+func TestGroupProcessesByExecutable(t *testing.T) {
+	groups := groupProcesses(groupingProcesses(), &config.ProcessGroupingConfig{Mode: "executable"}, 0)
+
+	if len(groups) != 6 {
+		t.Fatalf("Expected 6 groups, got %+v", groups)
+	}
+	// Sorted by CPU usage; names differing in case and ".exe" are merged
+	if groups[0].Name != "game.exe" || groups[1].Name != "chrome.exe" {
+		t.Errorf("Unexpected order: %s, %s", groups[0].Name, groups[1].Name)
+	}
+	chrome := groups[1]
+	if chrome.ProcessCount != 3 || chrome.CPUPercent != 17 || chrome.MemoryMB != 600 || chrome.Threads != 35 {
+		t.Errorf("Unexpected chrome group: %+v", chrome)
+	}
+	if !reflect.DeepEqual(chrome.PIDs, []int32{20, 21, 22}) || chrome.RootPID != 0 {
+		t.Errorf("Unexpected chrome PIDs or root: %+v", chrome)
+	}
+
+	// The limit keeps the busiest groups
+	if limited := groupProcesses(groupingProcesses(), &config.ProcessGroupingConfig{}, 2); len(limited) != 2 {
+		t.Errorf("Expected 2 groups with a limit, got %d", len(limited))
+	}
+}
+
+func TestGroupProcessesByTree(t *testing.T) {
+	tests := []struct {
+		name       string
+		boundaries []string
+		want       map[string][]int32
+	}{
+		{
+			name: "no boundaries",
+			want: map[string][]int32{
+				"init":     {1, 10, 20, 21, 22, 30, 31},
+				"game.exe": {40},
+			},
+		},
+		{
+			// Children of a boundary form their own trees; the boundary and
+			// its ancestors keep theirs
+			name:       "explorer boundary",
+			boundaries: []string{"Explorer"},
+			want: map[string][]int32{
+				"init":       {1, 10},
+				"chrome.exe": {20, 21, 22},
+				"steam.exe":  {30, 31},
+				"game.exe":   {40},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.ProcessGroupingConfig{Mode: "tree", TreeBoundaries: tt.boundaries}
+			groups := groupProcesses(groupingProcesses(), cfg, 0)
+			if got := groupsByName(groups); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			for _, g := range groups {
+				if g.RootPID != g.PIDs[0] {
+					t.Errorf("Expected %s to be rooted at %d, got %d", g.Name, g.PIDs[0], g.RootPID)
+				}
+			}
+		})
+	}
+}
+
+func TestGroupProcessesRules(t *testing.T) {
+	cfg := &config.ProcessGroupingConfig{
+		Mode: "tree",
+		Rules: []config.ProcessGroupRule{
+			{Name: "Steam", Match: []string{"STEAM*.exe"}},
+			{Name: "Browser", Match: []string{"chrome", "firefox"}},
+		},
+	}
+	want := map[string][]int32{
+		"Steam":    {30, 31},
+		"Browser":  {20, 21, 22},
+		"init":     {1, 10},
+		"game.exe": {40},
+	}
+
+	// Rules take precedence over the tree
+	groups := groupProcesses(groupingProcesses(), cfg, 0)
+	if got := groupsByName(groups); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestMatchGroupRule(t *testing.T) {
+	rules := []config.ProcessGroupRule{
+		{Name: "Discord", Match: []string{"Discord.exe"}},
+		{Name: "Helpers", Match: []string{"*helper?"}},
+		{Name: "Any", Match: []string{"*"}},
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"discord", "Discord"},
+		{"steamwebhelper", "Any"},
+		{"gpuhelper1", "Helpers"},
+		{"notepad", "Any"},
+	}
+	for _, tt := range tests {
+		if got, ok := matchGroupRule(tt.key, rules); !ok || got != tt.want {
+			t.Errorf("%s: expected %s, got %q", tt.key, tt.want, got)
+		}
+	}
+
+	if _, ok := matchGroupRule("discord", nil); ok {
+		t.Error("Expected no match without rules")
+	}
+}
+
+func TestFindTreeRoot(t *testing.T) {
+	infos := groupingProcesses()
+	byPID := make(map[int32]*models.ProcessInfo, len(infos))
+	for i := range infos {
+		byPID[infos[i].PID] = &infos[i]
+	}
+
+	if root := findTreeRoot(31, byPID, nil); root != 1 {
+		t.Errorf("Expected the topmost ancestor, got %d", root)
+	}
+	if root := findTreeRoot(31, byPID, map[string]bool{"steam": true}); root != 31 {
+		t.Errorf("Expected a child of a boundary to be its own root, got %d", root)
+	}
+	if root := findTreeRoot(40, byPID, nil); root != 40 {
+		t.Errorf("Expected a process with a missing parent to be its own root, got %d", root)
+	}
+}
@@ -1,16 +1,49 @@
 package collector
 
 import (
+	"fmt"
 	"sort"
+	"sync"
+	"time"
 
 	"github.com/shirou/gopsutil/v3/process"
 
+	"github.com/NaveLIL/erez-monitor/config"
 	"github.com/NaveLIL/erez-monitor/models"
 )
 
 // ProcessCollector collects process metrics.
 type ProcessCollector struct {
 	topCount int
+
+	// Grouping state
+	grouping *config.ProcessGroupingConfig
+	groups   []models.ProcessGroupInfo
+	lastIO   map[int32]processIOSample
+
+	// Watchlist state
+	watchlist []watchEntry
+	watched   []models.WatchedProcessInfo
+
+	// Cached per-PID details and the last full process list
+	details map[int32]*processDetails
+	lastAll []models.ProcessInfo
+
+	// Lifecycle events
+	events *ProcessEventTracker
+
+	// Per-process GPU usage state
+	gpu    *ProcessGPUCollector
+	topGPU []models.ProcessInfo
+
+	// Top processes by memory and disk I/O from the last Collect call
+	topMemory []models.ProcessInfo
+	topIO     []models.ProcessInfo
+
+	// Long-term memory history of all processes
+	leaks *LeakDetector
+
+	mu sync.Mutex
 }
 
 // NewProcessCollector creates a new process collector.
@@ -20,24 +53,65 @@ func NewProcessCollector(topCount int) *ProcessCollector {
 	}
 	return &ProcessCollector{
 		topCount: topCount,
+		lastIO:   make(map[int32]processIOSample),
+		details:  make(map[int32]*processDetails),
+		events:   NewProcessEventTracker(),
 	}
 }
 
 // Collect gathers current process metrics.
+// It also refreshes the watchlist state returned by GetWatched, feeds the
+// lifecycle event tracker and, when grouping is enabled, refreshes the groups
+// returned by GetGroups.
 func (c *ProcessCollector) Collect() []models.ProcessInfo {
 	processes, err := process.Processes()
 	if err != nil {
 		return nil
 	}
 
+	c.mu.Lock()
+	grouping := c.grouping
+	watchlist := c.watchlist
+	gpu := c.gpu
+	leaks := c.leaks
+	c.mu.Unlock()
+	grouped := grouping != nil && grouping.Enabled
+	watched := newWatchedState(watchlist)
+	gpuUsage := gpu.Collect()
+
 	// Collect info for all processes
 	processInfos := make([]models.ProcessInfo, 0, len(processes))
+	procs := make(map[int32]*process.Process, len(processes))
+	watchIDs := make(map[int32][]string)
+	now := time.Now()
 
 	for _, p := range processes {
 		info := c.getProcessInfo(p)
-		if info != nil {
-			processInfos = append(processInfos, *info)
+		if info == nil {
+			continue
 		}
+		procs[p.Pid] = p
+		applyDetails(info, c.getDetails(p, info.Name, false))
+		applyGPUUsage(info, gpuUsage)
+		if grouped {
+			c.addGroupingDetails(p, info, now)
+		}
+		if watched != nil {
+			if ids := c.matchWatchlist(p, info, watchlist, watched); ids != nil {
+				watchIDs[p.Pid] = ids
+			}
+		}
+		processInfos = append(processInfos, *info)
+	}
+
+	var groups []models.ProcessGroupInfo
+	if grouped {
+		groups = groupProcesses(processInfos, grouping, c.topCount)
+	}
+
+	c.events.Update(processInfos, watchIDs, now)
+	if leaks != nil {
+		leaks.Add(processInfos, now)
 	}
 
 	// Sort by CPU usage (descending)
@@ -45,12 +119,99 @@ func (c *ProcessCollector) Collect() []models.ProcessInfo {
 		return processInfos[i].CPUPercent > processInfos[j].CPUPercent
 	})
 
-	// Return top N processes
-	if len(processInfos) > c.topCount {
-		processInfos = processInfos[:c.topCount]
+	// Take top N processes and fill their extended details
+	n := len(processInfos)
+	if n > c.topCount {
+		n = c.topCount
+	}
+	top := make([]models.ProcessInfo, n)
+	copy(top, processInfos[:n])
+	for i := range top {
+		applyDetails(&top[i], c.getDetails(procs[top[i].PID], top[i].Name, true))
 	}
 
-	return processInfos
+	var topGPU []models.ProcessInfo
+	if gpu != nil {
+		topGPU = topByGPU(processInfos, c.topCount)
+		for i := range topGPU {
+			applyDetails(&topGPU[i], c.getDetails(procs[topGPU[i].PID], topGPU[i].Name, true))
+		}
+	}
+
+	topMemory := topBy(processInfos, c.topCount, func(info *models.ProcessInfo) float64 {
+		return float64(info.MemoryMB)
+	})
+	topIO := topBy(processInfos, c.topCount, func(info *models.ProcessInfo) float64 {
+		return info.DiskReadKBps + info.DiskWriteKBps
+	})
+
+	alive := pidSet(processInfos)
+
+	c.mu.Lock()
+	c.groups = groups
+	c.watched = watched
+	c.topGPU = topGPU
+	c.topMemory = topMemory
+	c.topIO = topIO
+	c.lastAll = processInfos
+	c.pruneIOSamples(alive)
+	c.pruneDetails(alive)
+	c.mu.Unlock()
+
+	return top
+}
+
+// SetLeakDetector sets the memory leak detector fed with every process on
+// each Collect call. Passing nil turns leak detection off.
+func (c *ProcessCollector) SetLeakDetector(leaks *LeakDetector) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.leaks = leaks
+}
+
+// GetTopMemory returns the processes with the most resident memory computed
+// by the last Collect call, largest first.
+func (c *ProcessCollector) GetTopMemory() []models.ProcessInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]models.ProcessInfo(nil), c.topMemory...)
+}
+
+// GetTopIO returns the processes with the highest disk I/O rate computed by
+// the last Collect call, busiest first. Per-process I/O rates are only
+// measured when process grouping is enabled, so the list is empty otherwise.
+func (c *ProcessCollector) GetTopIO() []models.ProcessInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]models.ProcessInfo(nil), c.topIO...)
+}
+
+// topBy returns up to limit processes with a positive value, highest first.
+func topBy(infos []models.ProcessInfo, limit int, value func(*models.ProcessInfo) float64) []models.ProcessInfo {
+	var top []models.ProcessInfo
+	for i := range infos {
+		if value(&infos[i]) > 0 {
+			top = append(top, infos[i])
+		}
+	}
+
+	sort.SliceStable(top, func(i, j int) bool {
+		return value(&top[i]) > value(&top[j])
+	})
+
+	if len(top) > limit {
+		top = top[:limit]
+	}
+	return top
+}
+
+// pidSet returns the set of PIDs in the given process list.
+func pidSet(infos []models.ProcessInfo) map[int32]bool {
+	set := make(map[int32]bool, len(infos))
+	for _, info := range infos {
+		set[info.PID] = true
+	}
+	return set
 }
 
 // getProcessInfo extracts information from a process.
@@ -89,6 +250,28 @@ func (c *ProcessCollector) getProcessInfo(p *process.Process) *models.ProcessInf
 	return info
 }
 
+// Events returns the process lifecycle event tracker.
+func (c *ProcessCollector) Events() *ProcessEventTracker {
+	return c.events
+}
+
+// GetProcessDetails returns information about a process including its
+// command line, owner and executable path.
+func (c *ProcessCollector) GetProcessDetails(pid int32) (*models.ProcessInfo, error) {
+	p, err := process.NewProcess(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	info := c.getProcessInfo(p)
+	if info == nil {
+		return nil, fmt.Errorf("process %d is not accessible", pid)
+	}
+	applyDetails(info, c.getDetails(p, info.Name, true))
+
+	return info, nil
+}
+
 // GetTopByCPU returns the top N processes by CPU usage.
 func (c *ProcessCollector) GetTopByCPU(n int) []models.ProcessInfo {
 	processes, err := process.Processes()
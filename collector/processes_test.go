@@ -0,0 +1,27 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+func TestTopBy(t *testing.T) {
+	infos := []models.ProcessInfo{
+		{PID: 1, MemoryMB: 100, DiskWriteKBps: 10},
+		{PID: 2, MemoryMB: 4000},
+		{PID: 3, MemoryMB: 500, DiskReadKBps: 300, DiskWriteKBps: 20},
+		{PID: 4},
+	}
+
+	memory := topBy(infos, 2, func(info *models.ProcessInfo) float64 { return float64(info.MemoryMB) })
+	if len(memory) != 2 || memory[0].PID != 2 || memory[1].PID != 3 {
+		t.Errorf("Unexpected memory ranking: %+v", memory)
+	}
+
+	// Processes without I/O are not ranked
+	io := topBy(infos, 10, func(info *models.ProcessInfo) float64 { return info.DiskReadKBps + info.DiskWriteKBps })
+	if len(io) != 2 || io[0].PID != 3 || io[1].PID != 1 {
+		t.Errorf("Unexpected I/O ranking: %+v", io)
+	}
+}
@@ -0,0 +1,137 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// defaultPowercapRoot is the sysfs directory listing powercap zones.
+const defaultPowercapRoot = "/sys/class/powercap"
+
+// raplSample is the energy counter of a zone at a point in time.
+type raplSample struct {
+	energyUJ uint64
+	at       time.Time
+}
+
+// RAPLCollector computes CPU package and DRAM power from the RAPL energy
+// counters exposed by the powercap framework (Intel and recent AMD CPUs).
+//
+// Reading energy_uj usually requires root; zones that cannot be read are
+// skipped.
+type RAPLCollector struct {
+	root string
+
+	mu   sync.Mutex
+	last map[string]raplSample // zone directory -> previous sample
+}
+
+// NewRAPLCollector creates a new RAPL collector reading zones from root
+// (defaults to /sys/class/powercap).
+func NewRAPLCollector(root string) *RAPLCollector {
+	if root == "" {
+		root = defaultPowercapRoot
+	}
+	return &RAPLCollector{
+		root: root,
+		last: make(map[string]raplSample),
+	}
+}
+
+// Reset discards the previous energy readings so that the next power is
+// not computed across a collection gap.
+func (c *RAPLCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.last = make(map[string]raplSample)
+}
+
+// Apply fills the package and DRAM power of metrics.
+func (c *RAPLCollector) Apply(metrics *models.CPUMetrics) {
+	metrics.PackagePowerWatts, metrics.DRAMPowerWatts = c.collectAt(time.Now())
+}
+
+// collectAt reads all zones and returns the package and DRAM power in watts
+// since the previous call. The first call only records the counters.
+func (c *RAPLCollector) collectAt(now time.Time) (packageW, dramW float64) {
+	entries, err := os.ReadDir(c.root)
+	if err != nil {
+		return 0, 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		// Zones are intel-rapl:N (packages, psys) and intel-rapl:N:M (core,
+		// uncore, dram); "intel-rapl" itself is the control type
+		if !strings.HasPrefix(entry.Name(), "intel-rapl:") {
+			continue
+		}
+
+		dir := filepath.Join(c.root, entry.Name())
+		energy, ok := readSysfsInt(dir, "energy_uj")
+		if !ok || energy < 0 {
+			continue
+		}
+		seen[dir] = true
+
+		sample := raplSample{energyUJ: uint64(energy), at: now}
+		prev, hasPrev := c.last[dir]
+		c.last[dir] = sample
+		if !hasPrev {
+			continue
+		}
+
+		elapsed := now.Sub(prev.at).Seconds()
+		if elapsed <= 0 {
+			continue
+		}
+
+		var maxRange uint64
+		if v, ok := readSysfsInt(dir, "max_energy_range_uj"); ok && v > 0 {
+			maxRange = uint64(v)
+		}
+		delta, ok := energyDelta(prev.energyUJ, sample.energyUJ, maxRange)
+		if !ok {
+			continue
+		}
+		watts := float64(delta) / 1e6 / elapsed
+
+		name := readSysfsString(dir, "name")
+		switch {
+		case strings.HasPrefix(name, "package"):
+			packageW += watts
+		case name == "dram":
+			dramW += watts
+		}
+	}
+
+	// Forget zones that disappeared
+	for dir := range c.last {
+		if !seen[dir] {
+			delete(c.last, dir)
+		}
+	}
+
+	return packageW, dramW
+}
+
+// energyDelta returns the energy consumed between two counter readings,
+// accounting for the counter wrapping around at maxRange. It returns false
+// if the counter went backwards and the range is unknown.
+func energyDelta(prev, cur, maxRange uint64) (uint64, bool) {
+	if cur >= prev {
+		return cur - prev, true
+	}
+	if maxRange == 0 || prev > maxRange {
+		return 0, false
+	}
+	return maxRange - prev + cur, true
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// writeRAPLZone creates or updates a fake powercap zone.
+func writeRAPLZone(t *testing.T, root, zone, name string, energyUJ, maxRangeUJ uint64) {
+	t.Helper()
+
+	dir := filepath.Join(root, zone)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"name":                name,
+		"energy_uj":           strconv.FormatUint(energyUJ, 10),
+		"max_energy_range_uj": strconv.FormatUint(maxRangeUJ, 10),
+	}
+	for file, value := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), []byte(value+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRAPLCollectorPower(t *testing.T) {
+	root := t.TempDir()
+	const maxRange = 262143328850
+	writeRAPLZone(t, root, "intel-rapl:0", "package-0", 1000000, maxRange)
+	writeRAPLZone(t, root, "intel-rapl:0:0", "core", 500000, maxRange)
+	writeRAPLZone(t, root, "intel-rapl:0:2", "dram", 200000, maxRange)
+	writeRAPLZone(t, root, "intel-rapl:1", "psys", 3000000, maxRange)
+
+	c := NewRAPLCollector(root)
+	start := time.Now()
+	if pkg, dram := c.collectAt(start); pkg != 0 || dram != 0 {
+		t.Errorf("Expected no power on first sample, got %.1f/%.1f", pkg, dram)
+	}
+
+	// 2 seconds later: package used 90 J, DRAM 8 J
+	writeRAPLZone(t, root, "intel-rapl:0", "package-0", 91000000, maxRange)
+	writeRAPLZone(t, root, "intel-rapl:0:0", "core", 60500000, maxRange)
+	writeRAPLZone(t, root, "intel-rapl:0:2", "dram", 8200000, maxRange)
+	writeRAPLZone(t, root, "intel-rapl:1", "psys", 203000000, maxRange)
+
+	pkg, dram := c.collectAt(start.Add(2 * time.Second))
+	if pkg != 45 {
+		t.Errorf("Expected package power 45 W, got %.2f", pkg)
+	}
+	if dram != 4 {
+		t.Errorf("Expected DRAM power 4 W, got %.2f", dram)
+	}
+}
+
+func TestRAPLCollectorWraparound(t *testing.T) {
+	root := t.TempDir()
+	const maxRange = 100000000 // 100 J
+	writeRAPLZone(t, root, "intel-rapl:0", "package-0", 95000000, maxRange)
+
+	c := NewRAPLCollector(root)
+	start := time.Now()
+	c.collectAt(start)
+
+	// Counter wrapped: 5 J to the end of the range plus 15 J after it
+	writeRAPLZone(t, root, "intel-rapl:0", "package-0", 15000000, maxRange)
+	if pkg, _ := c.collectAt(start.Add(time.Second)); pkg != 20 {
+		t.Errorf("Expected 20 W across wraparound, got %.2f", pkg)
+	}
+}
+
+func TestRAPLCollectorUnreadable(t *testing.T) {
+	c := NewRAPLCollector(filepath.Join(t.TempDir(), "missing"))
+	now := time.Now()
+	c.collectAt(now)
+	if pkg, dram := c.collectAt(now.Add(time.Second)); pkg != 0 || dram != 0 {
+		t.Errorf("Expected no power without powercap, got %.1f/%.1f", pkg, dram)
+	}
+}
+
+func TestEnergyDelta(t *testing.T) {
+	tests := []struct {
+		prev, cur, maxRange uint64
+		want                uint64
+		ok                  bool
+	}{
+		{100, 250, 1000, 150, true},
+		{900, 100, 1000, 200, true},
+		{900, 100, 0, 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := energyDelta(tt.prev, tt.cur, tt.maxRange)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("energyDelta(%d, %d, %d) = %d, %v; want %d, %v",
+				tt.prev, tt.cur, tt.maxRange, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+const (
+	// resumeGapThreshold is the unexplained delay between two samples after
+	// which a collection gap is assumed.
+	resumeGapThreshold = 10 * time.Second
+	// maxCollectorEvents is the number of collector events kept for exports.
+	maxCollectorEvents = 100
+)
+
+// detectGap decides whether the time between two samples was a collection
+// gap. wall and mono are the wall-clock and monotonic time elapsed since the
+// previous sample, expected is the longest configured interval.
+//
+// On Linux the monotonic clock stops during suspend, so sleep shows up as
+// wall time running ahead of monotonic time. On Windows the monotonic clock
+// keeps running, so sleep shows up as a sample that is far too late.
+func detectGap(wall, mono, expected time.Duration) (suspended, gap bool) {
+	suspended = wall-mono > resumeGapThreshold
+	late := mono-expected > resumeGapThreshold
+	return suspended, suspended || late
+}
+
+// checkResume detects a gap since the previous sample. On a gap it resets
+// the delta baselines of all sub-collectors and returns the resume event.
+func (c *Collector) checkResume(now time.Time) *models.CollectorEvent {
+	prev := c.lastSampleAt
+	if prev.IsZero() {
+		return nil
+	}
+
+	// Round(0) strips the monotonic reading, leaving pure wall-clock time
+	wall := now.Round(0).Sub(prev.Round(0))
+	mono := now.Sub(prev)
+	suspended, gap := detectGap(wall, mono, c.maxInterval())
+	if !gap {
+		return nil
+	}
+
+	c.resetBaselines()
+
+	event := &models.CollectorEvent{
+		Type:      models.CollectorEventResumed,
+		Timestamp: now,
+		GapStart:  prev,
+		GapSec:    wall.Seconds(),
+		Suspended: suspended,
+	}
+
+	c.eventsMu.Lock()
+	c.events = append(c.events, event)
+	if len(c.events) > maxCollectorEvents {
+		c.events = c.events[len(c.events)-maxCollectorEvents:]
+	}
+	c.eventsMu.Unlock()
+
+	c.log.Infof("Collection resumed after a %v gap (suspended: %v)", wall.Round(time.Second), suspended)
+	return event
+}
+
+// maxInterval returns the longest collection interval the configuration
+// allows.
+func (c *Collector) maxInterval() time.Duration {
+	interval := c.config.UpdateInterval
+	if c.config.Power.BatteryInterval > interval {
+		interval = c.config.Power.BatteryInterval
+	}
+	if c.sampler != nil && c.config.Adaptive.MaxInterval > interval {
+		interval = c.config.Adaptive.MaxInterval
+	}
+	return interval
+}
+
+// resetBaselines discards the previous samples of all delta-based
+// sub-collectors, so that rates after a gap are not averaged over it.
+func (c *Collector) resetBaselines() {
+	c.diskCollector.Reset()
+	c.networkCollector.Reset()
+	c.pressureCollector.Reset()
+	c.processCollector.ResetIOSamples()
+	if c.cgroupCollector != nil {
+		c.cgroupCollector.Reset()
+	}
+	if c.raplCollector != nil {
+		c.raplCollector.Reset()
+	}
+	if c.throttleDetector != nil {
+		c.throttleDetector.Reset()
+	}
+	if c.wirelessCollector != nil {
+		c.wirelessCollector.Reset()
+	}
+	if c.bottleneck != nil {
+		c.bottleneck.Reset()
+	}
+}
+
+// GetEvents returns the recent collector events (e.g., resume after sleep).
+func (c *Collector) GetEvents() []*models.CollectorEvent {
+	c.eventsMu.Lock()
+	defer c.eventsMu.Unlock()
+
+	events := make([]*models.CollectorEvent, len(c.events))
+	copy(events, c.events)
+	return events
+}
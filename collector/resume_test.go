@@ -0,0 +1,85 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+func TestDetectGap(t *testing.T) {
+	tests := []struct {
+		name          string
+		wall, mono    time.Duration
+		wantSuspended bool
+		wantGap       bool
+	}{
+		{"regular sample", time.Second, time.Second, false, false},
+		{"slow sample", 3 * time.Second, 3 * time.Second, false, false},
+		{"linux suspend", 2 * time.Hour, time.Second, true, true},
+		{"windows suspend", 2 * time.Hour, 2 * time.Hour, false, true},
+		{"small clock step", 5 * time.Second, time.Second, false, false},
+		{"clock set back", -time.Hour, time.Second, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suspended, gap := detectGap(tt.wall, tt.mono, 5*time.Second)
+			if suspended != tt.wantSuspended || gap != tt.wantGap {
+				t.Errorf("detectGap(%v, %v) = %v, %v; want %v, %v",
+					tt.wall, tt.mono, suspended, gap, tt.wantSuspended, tt.wantGap)
+			}
+		})
+	}
+}
+
+func TestCheckResume(t *testing.T) {
+	root := t.TempDir()
+	writeCgroup(t, root, "system.slice/docker.service", "usage_usec 1000", "0", "max", "", "1")
+
+	c := New(&config.MonitoringConfig{
+		UpdateInterval:  time.Second,
+		HistoryDuration: time.Minute,
+		TopProcessCount: 5,
+		Cgroups:         config.CgroupsConfig{Enabled: true, Root: root, Slices: []string{"system.slice"}},
+	})
+	c.cgroupCollector.Collect()
+
+	// No previous sample, no gap
+	now := time.Now()
+	if event := c.checkResume(now); event != nil {
+		t.Fatalf("Expected no event without a previous sample, got %+v", event)
+	}
+
+	// Regular interval
+	c.lastSampleAt = now.Add(-time.Second)
+	if event := c.checkResume(now); event != nil {
+		t.Fatalf("Expected no event for a regular interval, got %+v", event)
+	}
+
+	// Both clocks advanced by an hour (monotonic includes sleep)
+	c.lastSampleAt = now.Add(-time.Hour)
+	event := c.checkResume(now)
+	if event == nil {
+		t.Fatal("Expected a resume event")
+	}
+	if event.Type != models.CollectorEventResumed || event.Suspended {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if event.GapSec < 3599 || event.GapSec > 3601 {
+		t.Errorf("Expected a gap of one hour, got %.1f s", event.GapSec)
+	}
+	if !event.GapStart.Equal(c.lastSampleAt) {
+		t.Errorf("Expected gap start at the previous sample")
+	}
+
+	// Baselines were reset: the first cgroup sample after the gap has no rate
+	if len(c.cgroupCollector.last) != 0 {
+		t.Errorf("Expected cgroup baselines to be reset, got %d", len(c.cgroupCollector.last))
+	}
+
+	if events := c.GetEvents(); len(events) != 1 || events[0] != event {
+		t.Errorf("Expected the event in the event history, got %d events", len(events))
+	}
+}
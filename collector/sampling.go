@@ -0,0 +1,57 @@
+package collector
+
+import (
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+)
+
+// adaptiveSampler picks the collection interval from the current activity.
+// It speeds up immediately when the system gets busy or an alert fires, and
+// slows down only after several consecutive idle samples so that short
+// pauses don't make the interval oscillate.
+type adaptiveSampler struct {
+	cfg       *config.AdaptiveSamplingConfig
+	base      time.Duration
+	current   time.Duration
+	idleCount int
+}
+
+// newAdaptiveSampler creates a sampler starting at the base interval,
+// clamped to the configured bounds.
+func newAdaptiveSampler(cfg *config.AdaptiveSamplingConfig, base time.Duration) *adaptiveSampler {
+	s := &adaptiveSampler{cfg: cfg}
+	s.base = s.clamp(base)
+	s.current = s.base
+	return s
+}
+
+// next returns the interval for the next sample. load is the highest of the
+// CPU and GPU usage percentages.
+func (s *adaptiveSampler) next(load float64, overlayVisible, alertActive bool) time.Duration {
+	switch {
+	case alertActive || load >= s.cfg.BusyPercent:
+		s.idleCount = 0
+		s.current = s.cfg.MinInterval
+	case load <= s.cfg.IdlePercent && !overlayVisible:
+		s.idleCount++
+		if s.idleCount >= s.cfg.IdleSamples {
+			s.current = s.cfg.MaxInterval
+		}
+	default:
+		s.idleCount = 0
+		s.current = s.base
+	}
+	return s.current
+}
+
+// clamp limits d to the configured bounds.
+func (s *adaptiveSampler) clamp(d time.Duration) time.Duration {
+	if d < s.cfg.MinInterval {
+		return s.cfg.MinInterval
+	}
+	if d > s.cfg.MaxInterval {
+		return s.cfg.MaxInterval
+	}
+	return d
+}
@@ -0,0 +1,73 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+)
+
+func testAdaptiveConfig() *config.AdaptiveSamplingConfig {
+	return &config.AdaptiveSamplingConfig{
+		Enabled:     true,
+		MinInterval: 500 * time.Millisecond,
+		MaxInterval: 5 * time.Second,
+		IdlePercent: 15,
+		BusyPercent: 60,
+		IdleSamples: 3,
+	}
+}
+
+func TestAdaptiveSampler(t *testing.T) {
+	s := newAdaptiveSampler(testAdaptiveConfig(), time.Second)
+
+	// Moderate load keeps the base interval
+	if got := s.next(30, false, false); got != time.Second {
+		t.Errorf("Expected base interval under moderate load, got %v", got)
+	}
+
+	// Busy speeds up immediately
+	if got := s.next(85, false, false); got != 500*time.Millisecond {
+		t.Errorf("Expected min interval when busy, got %v", got)
+	}
+
+	// Idle slows down only after IdleSamples consecutive idle samples
+	for i := 1; i < 3; i++ {
+		if got := s.next(5, false, false); got != 500*time.Millisecond {
+			t.Errorf("Idle sample %d: expected interval to hold, got %v", i, got)
+		}
+	}
+	if got := s.next(5, false, false); got != 5*time.Second {
+		t.Errorf("Expected max interval after idle samples, got %v", got)
+	}
+
+	// An active alert speeds up even when idle
+	if got := s.next(5, false, true); got != 500*time.Millisecond {
+		t.Errorf("Expected min interval with active alert, got %v", got)
+	}
+}
+
+func TestAdaptiveSamplerOverlayVisible(t *testing.T) {
+	s := newAdaptiveSampler(testAdaptiveConfig(), time.Second)
+
+	// Idle with the overlay visible never slows down below the base rate
+	for i := 0; i < 10; i++ {
+		if got := s.next(2, true, false); got != time.Second {
+			t.Fatalf("Expected base interval with visible overlay, got %v", got)
+		}
+	}
+
+	// Hiding the overlay restarts the idle count
+	s.next(2, false, false)
+	s.next(2, false, false)
+	if got := s.next(2, false, false); got != 5*time.Second {
+		t.Errorf("Expected max interval after hiding overlay, got %v", got)
+	}
+}
+
+func TestAdaptiveSamplerClampsBase(t *testing.T) {
+	s := newAdaptiveSampler(testAdaptiveConfig(), 100*time.Millisecond)
+	if got := s.next(30, false, false); got != 500*time.Millisecond {
+		t.Errorf("Expected base clamped to min interval, got %v", got)
+	}
+}
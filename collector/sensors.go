@@ -0,0 +1,268 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// defaultHwmonRoot is the sysfs directory listing hardware monitor chips.
+const defaultHwmonRoot = "/sys/class/hwmon"
+
+// hwmonInputPattern matches hwmon sensor value files, e.g. temp1_input.
+// amdgpu reports power as power1_average instead of power1_input.
+var hwmonInputPattern = regexp.MustCompile(`^(temp|fan|in|power)(\d+)_(input|average)$`)
+
+// hwmonSensorTypes maps hwmon file prefixes to sensor types, units and scale
+// (sysfs values are in millidegrees, millivolts and microwatts).
+var hwmonSensorTypes = map[string]struct {
+	sensorType models.SensorType
+	unit       string
+	scale      float64
+}{
+	"temp":  {models.SensorTemperature, "°C", 1e3},
+	"fan":   {models.SensorFan, "RPM", 1},
+	"in":    {models.SensorVoltage, "V", 1e3},
+	"power": {models.SensorPower, "W", 1e6},
+}
+
+// SensorCollector collects hardware sensor readings from hwmon chips.
+type SensorCollector struct {
+	root string
+
+	mu     sync.RWMutex
+	names  map[string]string // sensor ID -> friendly name
+	hidden map[string]bool
+}
+
+// NewSensorCollector creates a new sensor collector.
+func NewSensorCollector(cfg *config.SensorsConfig) *SensorCollector {
+	c := &SensorCollector{root: defaultHwmonRoot}
+	if cfg != nil {
+		if cfg.Root != "" {
+			c.root = cfg.Root
+		}
+		c.SetSensors(cfg.Sensors)
+	}
+	return c
+}
+
+// SetSensors updates the friendly names and hidden sensors.
+func (c *SensorCollector) SetSensors(sensors []config.SensorConfig) {
+	names := make(map[string]string)
+	hidden := make(map[string]bool)
+	for _, s := range sensors {
+		if s.Name != "" {
+			names[s.ID] = s.Name
+		}
+		if s.Hidden {
+			hidden[s.ID] = true
+		}
+	}
+
+	c.mu.Lock()
+	c.names = names
+	c.hidden = hidden
+	c.mu.Unlock()
+}
+
+// Collect reads all sensors.
+func (c *SensorCollector) Collect() []models.SensorReading {
+	readings := readHwmon(c.root)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := readings[:0]
+	for _, r := range readings {
+		if c.hidden[r.ID] {
+			continue
+		}
+		if name, ok := c.names[r.ID]; ok {
+			r.Name = name
+		}
+		result = append(result, r)
+	}
+
+	return result
+}
+
+// readHwmon reads all sensors of all hwmon chips under root.
+func readHwmon(root string) []models.SensorReading {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	type chip struct {
+		dir, name, device string
+	}
+	chips := make([]chip, 0, len(entries))
+	nameCount := make(map[string]int)
+	for _, entry := range entries {
+		dir := filepath.Join(root, entry.Name())
+		name := readSysfsString(dir, "name")
+		if name == "" {
+			name = entry.Name()
+		}
+		device := entry.Name()
+		if target, err := os.Readlink(filepath.Join(dir, "device")); err == nil {
+			device = filepath.Base(target)
+		}
+		chips = append(chips, chip{dir: dir, name: name, device: device})
+		nameCount[name]++
+	}
+	sort.SliceStable(chips, func(i, j int) bool {
+		return hwmonIndex(chips[i].dir) < hwmonIndex(chips[j].dir)
+	})
+
+	var readings []models.SensorReading
+	for _, ch := range chips {
+		// Chips with the same driver (e.g. two NVMe drives) are told apart by device
+		chipID := ch.name
+		if nameCount[ch.name] > 1 {
+			chipID = ch.name + "@" + ch.device
+		}
+		readings = append(readings, readHwmonChip(ch.dir, ch.name, chipID)...)
+	}
+
+	return readings
+}
+
+// readHwmonChip reads the sensors of a single chip.
+func readHwmonChip(dir, name, chipID string) []models.SensorReading {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	// Older drivers keep the attributes in the device directory
+	if !hasHwmonInputs(files) {
+		deviceDir := filepath.Join(dir, "device")
+		if deviceFiles, err := os.ReadDir(deviceDir); err == nil && hasHwmonInputs(deviceFiles) {
+			dir, files = deviceDir, deviceFiles
+		}
+	}
+
+	var readings []models.SensorReading
+	seen := make(map[string]bool)
+	for _, f := range files {
+		m := hwmonInputPattern.FindStringSubmatch(f.Name())
+		if m == nil {
+			continue
+		}
+		prefix := m[1] + m[2] // e.g. temp1
+		if seen[prefix] {
+			continue // power1_input and power1_average
+		}
+
+		raw, ok := readSysfsInt(dir, f.Name())
+		if !ok {
+			continue
+		}
+		seen[prefix] = true
+
+		kind := hwmonSensorTypes[m[1]]
+		reading := models.SensorReading{
+			ID:    chipID + "/" + prefix,
+			Chip:  name,
+			Label: readSysfsString(dir, prefix+"_label"),
+			Type:  kind.sensorType,
+			Value: float64(raw) / kind.scale,
+			Unit:  kind.unit,
+		}
+		if reading.Label == "" {
+			reading.Label = prefix
+		}
+		reading.Name = reading.Label
+		if v, ok := readSysfsInt(dir, prefix+"_max"); ok && v > 0 {
+			reading.Max = float64(v) / kind.scale
+		}
+		if v, ok := readSysfsInt(dir, prefix+"_crit"); ok && v > 0 {
+			reading.Crit = float64(v) / kind.scale
+		}
+
+		readings = append(readings, reading)
+	}
+
+	sort.SliceStable(readings, func(i, j int) bool {
+		a, b := readings[i], readings[j]
+		if a.Type != b.Type {
+			return sensorTypeOrder(a.Type) < sensorTypeOrder(b.Type)
+		}
+		return sensorIndex(a.ID) < sensorIndex(b.ID)
+	})
+
+	return readings
+}
+
+// hasHwmonInputs returns true if files contain sensor value files.
+func hasHwmonInputs(files []os.DirEntry) bool {
+	for _, f := range files {
+		if hwmonInputPattern.MatchString(f.Name()) {
+			return true
+		}
+	}
+	return false
+}
+
+// hwmonIndex returns N of a hwmonN directory, for numeric ordering.
+func hwmonIndex(dir string) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "hwmon"))
+	if err != nil {
+		return 1 << 30
+	}
+	return n
+}
+
+// sensorIndex returns the numeric index of a sensor ID such as "coretemp/temp12".
+func sensorIndex(id string) int {
+	name := id[strings.LastIndex(id, "/")+1:]
+	n, _ := strconv.Atoi(strings.TrimLeft(name, "abcdefghijklmnopqrstuvwxyz"))
+	return n
+}
+
+func sensorTypeOrder(t models.SensorType) int {
+	switch t {
+	case models.SensorTemperature:
+		return 0
+	case models.SensorFan:
+		return 1
+	case models.SensorVoltage:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// cpuTemperatureFromSensors returns the CPU package temperature from the
+// CPU temperature chip (Intel coretemp or AMD k10temp/zenpower), or 0.
+func cpuTemperatureFromSensors(readings []models.SensorReading) float64 {
+	var hottest float64
+	for _, r := range readings {
+		if r.Type != models.SensorTemperature {
+			continue
+		}
+		switch r.Chip {
+		case "coretemp", "k10temp", "zenpower":
+		default:
+			continue
+		}
+
+		label := strings.ToLower(r.Label)
+		if strings.HasPrefix(label, "package") || label == "tctl" || label == "tdie" {
+			return r.Value
+		}
+		if r.Value > hottest {
+			hottest = r.Value
+		}
+	}
+	return hottest
+}
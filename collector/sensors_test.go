@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+func hwmonFixture() string {
+	return filepath.Join("testdata", "hwmon")
+}
+
+func TestReadHwmon(t *testing.T) {
+	readings := readHwmon(hwmonFixture())
+
+	byID := make(map[string]models.SensorReading)
+	var ids []string
+	for _, r := range readings {
+		byID[r.ID] = r
+		ids = append(ids, r.ID)
+	}
+
+	want := []string{
+		"coretemp/temp1", "coretemp/temp2", "coretemp/temp10",
+		"nct6775/fan1", "nct6775/fan2", "nct6775/in0",
+		"amdgpu/temp1", "amdgpu/power1",
+		"nvme@hwmon3/temp1",
+		"legacy/temp1",
+		"nvme@hwmon10/temp1",
+	}
+	if len(ids) != len(want) {
+		t.Fatalf("Expected sensors %v, got %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Errorf("Sensor %d: expected %s, got %s", i, want[i], ids[i])
+		}
+	}
+
+	pkg := byID["coretemp/temp1"]
+	if pkg.Label != "Package id 0" || pkg.Value != 55 || pkg.Unit != "°C" || pkg.Max != 100 || pkg.Crit != 105 {
+		t.Errorf("Unexpected package sensor %+v", pkg)
+	}
+	if r := byID["nct6775/fan1"]; r.Type != models.SensorFan || r.Value != 1200 || r.Label != "fan1" {
+		t.Errorf("Unexpected fan sensor %+v", r)
+	}
+	if r := byID["nct6775/in0"]; r.Type != models.SensorVoltage || r.Value != 1.024 || r.Unit != "V" {
+		t.Errorf("Unexpected voltage sensor %+v", r)
+	}
+	if r := byID["amdgpu/power1"]; r.Type != models.SensorPower || r.Value != 45 || r.Unit != "W" {
+		t.Errorf("Unexpected power sensor %+v", r)
+	}
+}
+
+func TestSensorCollectorNamesAndHidden(t *testing.T) {
+	c := NewSensorCollector(&config.SensorsConfig{
+		Root: hwmonFixture(),
+		Sensors: []config.SensorConfig{
+			{ID: "nct6775/fan1", Name: "CPU fan"},
+			{ID: "nct6775/fan2", Hidden: true},
+		},
+	})
+
+	var found bool
+	for _, r := range c.Collect() {
+		switch r.ID {
+		case "nct6775/fan1":
+			found = true
+			if r.Name != "CPU fan" || r.Label != "fan1" {
+				t.Errorf("Expected friendly name with original label, got %+v", r)
+			}
+		case "nct6775/fan2":
+			t.Error("Expected hidden sensor to be removed")
+		}
+	}
+	if !found {
+		t.Error("Expected renamed sensor in readings")
+	}
+}
+
+func TestReadHwmonMissingRoot(t *testing.T) {
+	if readings := readHwmon(filepath.Join("testdata", "missing")); len(readings) != 0 {
+		t.Errorf("Expected no readings, got %d", len(readings))
+	}
+}
+
+func TestCPUTemperatureFromSensors(t *testing.T) {
+	readings := readHwmon(hwmonFixture())
+	if temp := cpuTemperatureFromSensors(readings); temp != 55 {
+		t.Errorf("Expected package temperature 55, got %.1f", temp)
+	}
+
+	amd := []models.SensorReading{
+		{Chip: "k10temp", Label: "Tccd1", Type: models.SensorTemperature, Value: 70},
+		{Chip: "k10temp", Label: "Tctl", Type: models.SensorTemperature, Value: 65},
+	}
+	if temp := cpuTemperatureFromSensors(amd); temp != 65 {
+		t.Errorf("Expected Tctl temperature 65, got %.1f", temp)
+	}
+}
@@ -0,0 +1,184 @@
+package collector
+
+import (
+	"encoding/binary"
+	"strings"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// SMBIOS structure types used for the hardware inventory.
+const (
+	smbiosTypeBIOS         = 0
+	smbiosTypeSystem       = 1
+	smbiosTypeBaseboard    = 2
+	smbiosTypeMemoryDevice = 17
+	smbiosTypeEnd          = 127
+)
+
+// smbiosMemoryTypes maps SMBIOS memory type codes to names.
+var smbiosMemoryTypes = map[byte]string{
+	0x12: "DDR",
+	0x13: "DDR2",
+	0x18: "DDR3",
+	0x1A: "DDR4",
+	0x1B: "LPDDR",
+	0x1C: "LPDDR2",
+	0x1D: "LPDDR3",
+	0x1E: "LPDDR4",
+	0x22: "DDR5",
+	0x23: "LPDDR5",
+}
+
+// smbiosStructure is a single SMBIOS structure with its string set.
+type smbiosStructure struct {
+	kind    byte
+	data    []byte // formatted area, including the 4-byte header
+	strings []string
+}
+
+// byteAt returns the formatted-area byte at offset, or 0 if the structure
+// is too short.
+func (s *smbiosStructure) byteAt(offset int) byte {
+	if offset >= len(s.data) {
+		return 0
+	}
+	return s.data[offset]
+}
+
+// wordAt returns the little-endian word at offset, or 0.
+func (s *smbiosStructure) wordAt(offset int) uint16 {
+	if offset+2 > len(s.data) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(s.data[offset:])
+}
+
+// dwordAt returns the little-endian double word at offset, or 0.
+func (s *smbiosStructure) dwordAt(offset int) uint32 {
+	if offset+4 > len(s.data) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(s.data[offset:])
+}
+
+// stringAt returns the string referenced by the index byte at offset.
+func (s *smbiosStructure) stringAt(offset int) string {
+	index := int(s.byteAt(offset))
+	if index == 0 || index > len(s.strings) {
+		return ""
+	}
+	return cleanSMBIOSString(s.strings[index-1])
+}
+
+// parseSMBIOSStructures splits a raw SMBIOS table into structures. Parsing
+// stops at the end-of-table structure or at the first malformed entry.
+func parseSMBIOSStructures(table []byte) []smbiosStructure {
+	var structures []smbiosStructure
+
+	for len(table) >= 4 {
+		kind := table[0]
+		length := int(table[1])
+		if length < 4 || length > len(table) {
+			break
+		}
+		s := smbiosStructure{kind: kind, data: table[:length]}
+
+		// The string set follows the formatted area and ends with a double NUL
+		rest := table[length:]
+		end := 0
+		for end+1 < len(rest) && !(rest[end] == 0 && rest[end+1] == 0) {
+			end++
+		}
+		if end+1 >= len(rest) {
+			break
+		}
+		if end > 0 {
+			s.strings = strings.Split(string(rest[:end]), "\x00")
+		}
+		structures = append(structures, s)
+
+		if kind == smbiosTypeEnd {
+			break
+		}
+		table = rest[end+2:]
+	}
+
+	return structures
+}
+
+// applySMBIOS fills firmware, board and memory module information from a
+// raw SMBIOS table.
+func applySMBIOS(info *models.SystemInfo, table []byte) {
+	for _, s := range parseSMBIOSStructures(table) {
+		switch s.kind {
+		case smbiosTypeBIOS:
+			info.BIOS = models.BIOSInfo{
+				Vendor:  s.stringAt(0x04),
+				Version: s.stringAt(0x05),
+				Date:    s.stringAt(0x08),
+			}
+		case smbiosTypeSystem:
+			info.SystemVendor = s.stringAt(0x04)
+			info.SystemProduct = s.stringAt(0x05)
+		case smbiosTypeBaseboard:
+			info.Board = models.BoardInfo{
+				Manufacturer: s.stringAt(0x04),
+				Product:      s.stringAt(0x05),
+				Version:      s.stringAt(0x06),
+			}
+		case smbiosTypeMemoryDevice:
+			if module, ok := smbiosMemoryModule(&s); ok {
+				info.MemoryModules = append(info.MemoryModules, module)
+			}
+		}
+	}
+}
+
+// smbiosMemoryModule decodes a memory device structure. Empty slots are
+// skipped.
+func smbiosMemoryModule(s *smbiosStructure) (models.MemoryModule, bool) {
+	size := s.wordAt(0x0C)
+	if size == 0 || size == 0xFFFF {
+		return models.MemoryModule{}, false
+	}
+
+	var sizeMB uint64
+	switch {
+	case size == 0x7FFF:
+		// Extended size in MB
+		sizeMB = uint64(s.dwordAt(0x1C) & 0x7FFFFFFF)
+	case size&0x8000 != 0:
+		// Size in KB
+		sizeMB = uint64(size&0x7FFF) / 1024
+	default:
+		sizeMB = uint64(size)
+	}
+
+	memType := smbiosMemoryTypes[s.byteAt(0x12)]
+	if memType == "" {
+		memType = "Unknown"
+	}
+
+	return models.MemoryModule{
+		Locator:            s.stringAt(0x10),
+		Bank:               s.stringAt(0x11),
+		SizeMB:             sizeMB,
+		Type:               memType,
+		SpeedMTs:           uint32(s.wordAt(0x15)),
+		ConfiguredSpeedMTs: uint32(s.wordAt(0x20)),
+		Manufacturer:       s.stringAt(0x17),
+		Serial:             s.stringAt(0x18),
+		PartNumber:         s.stringAt(0x1A),
+	}, true
+}
+
+// cleanSMBIOSString trims padding and drops common placeholder values.
+func cleanSMBIOSString(s string) string {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "", "default string", "to be filled by o.e.m.", "not specified", "unknown", "none", "0000000000000000":
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,153 @@
+package collector
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/host"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// defaultSysRoot is the sysfs mount point used for the hardware inventory.
+const defaultSysRoot = "/sys"
+
+// virtualBlockPrefixes lists block devices that are not physical disks.
+var virtualBlockPrefixes = []string{"loop", "ram", "zram", "dm-", "md", "sr", "fd", "nbd"}
+
+// collectInventory gathers host, firmware, disk and network inventory.
+// CPU, RAM and GPU fields are filled in by the Collector.
+func collectInventory(sysRoot string) *models.SystemInfo {
+	info := &models.SystemInfo{CollectedAt: time.Now()}
+
+	if hostInfo, err := host.Info(); err == nil {
+		info.Hostname = hostInfo.Hostname
+		info.Platform = hostInfo.OS
+		info.OS = strings.TrimSpace(hostInfo.Platform + " " + hostInfo.PlatformVersion)
+		info.KernelVersion = hostInfo.KernelVersion
+		info.Arch = hostInfo.KernelArch
+		if hostInfo.BootTime > 0 {
+			info.BootTime = time.Unix(int64(hostInfo.BootTime), 0)
+		}
+	}
+	if info.Hostname == "" {
+		info.Hostname, _ = os.Hostname()
+	}
+
+	if table, err := readSMBIOSTable(sysRoot); err == nil {
+		applySMBIOS(info, table)
+	}
+	if info.Board.Product == "" && info.BIOS.Version == "" {
+		// SMBIOS tables are root-only on Linux; DMI id attributes are not
+		readDMIID(info, filepath.Join(sysRoot, "class", "dmi", "id"))
+	}
+
+	info.DiskDevices = readPhysicalDisks(sysRoot)
+	info.NetworkInterfaces = readNetworkInterfaces()
+
+	return info
+}
+
+// readDMIID fills firmware and board information from /sys/class/dmi/id.
+// Fields already set are kept.
+func readDMIID(info *models.SystemInfo, dir string) {
+	read := func(name string) string {
+		return cleanSMBIOSString(readSysfsString(dir, name))
+	}
+	setIfEmpty := func(field *string, name string) {
+		if *field == "" {
+			*field = read(name)
+		}
+	}
+
+	setIfEmpty(&info.SystemVendor, "sys_vendor")
+	setIfEmpty(&info.SystemProduct, "product_name")
+	setIfEmpty(&info.Board.Manufacturer, "board_vendor")
+	setIfEmpty(&info.Board.Product, "board_name")
+	setIfEmpty(&info.Board.Version, "board_version")
+	setIfEmpty(&info.BIOS.Vendor, "bios_vendor")
+	setIfEmpty(&info.BIOS.Version, "bios_version")
+	setIfEmpty(&info.BIOS.Date, "bios_date")
+}
+
+// readBlockDevices lists physical disks under root (e.g., /sys/block).
+// Virtual devices and devices without a backing hardware device are skipped.
+func readBlockDevices(root string) []models.DiskDevice {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil
+	}
+
+	var disks []models.DiskDevice
+	for _, entry := range entries {
+		name := entry.Name()
+		if isVirtualBlockDevice(name) {
+			continue
+		}
+		dir := filepath.Join(root, name)
+		deviceDir := filepath.Join(dir, "device")
+		if _, err := os.Stat(deviceDir); err != nil {
+			continue
+		}
+
+		disk := models.DiskDevice{
+			Name:   name,
+			Model:  readSysfsString(deviceDir, "model"),
+			Serial: readSysfsString(deviceDir, "serial"),
+		}
+		if sectors, ok := readSysfsInt(dir, "size"); ok && sectors > 0 {
+			// sysfs reports size in 512-byte sectors regardless of block size
+			disk.SizeGB = uint64(sectors) * 512 / 1e9
+		}
+		if rotational, ok := readSysfsInt(filepath.Join(dir, "queue"), "rotational"); ok {
+			disk.Rotational = rotational == 1
+		}
+		disks = append(disks, disk)
+	}
+
+	sort.Slice(disks, func(i, j int) bool { return disks[i].Name < disks[j].Name })
+	return disks
+}
+
+// isVirtualBlockDevice reports whether a block device name is virtual.
+func isVirtualBlockDevice(name string) bool {
+	for _, prefix := range virtualBlockPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// readNetworkInterfaces lists network interfaces that have a hardware
+// address.
+func readNetworkInterfaces() []models.NetworkInterface {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil
+	}
+
+	var result []models.NetworkInterface
+	for _, iface := range ifaces {
+		if len(iface.HardwareAddr) == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		nic := models.NetworkInterface{
+			Name: iface.Name,
+			MAC:  iface.HardwareAddr.String(),
+			MTU:  iface.MTU,
+			Up:   iface.Flags&net.FlagUp != 0,
+		}
+		if addrs, err := iface.Addrs(); err == nil {
+			for _, addr := range addrs {
+				nic.Addresses = append(nic.Addresses, addr.String())
+			}
+		}
+		result = append(result, nic)
+	}
+	return result
+}
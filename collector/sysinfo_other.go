@@ -0,0 +1,21 @@
+//go:build !windows
+
+package collector
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// readSMBIOSTable reads the raw SMBIOS table exported by the kernel.
+// It usually requires root.
+func readSMBIOSTable(sysRoot string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(sysRoot, "firmware", "dmi", "tables", "DMI"))
+}
+
+// readPhysicalDisks lists physical disks from sysfs.
+func readPhysicalDisks(sysRoot string) []models.DiskDevice {
+	return readBlockDevices(filepath.Join(sysRoot, "block"))
+}
@@ -0,0 +1,196 @@
+package collector
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// smbiosEntry builds a raw SMBIOS structure from a formatted area (without
+// the header) and its strings.
+func smbiosEntry(kind byte, formatted []byte, strs ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte(kind)
+	buf.WriteByte(byte(4 + len(formatted)))
+	buf.Write([]byte{0x00, 0x10}) // handle
+	buf.Write(formatted)
+	for _, s := range strs {
+		buf.WriteString(s)
+		buf.WriteByte(0)
+	}
+	if len(strs) == 0 {
+		buf.WriteByte(0)
+	}
+	buf.WriteByte(0)
+	return buf.Bytes()
+}
+
+// memoryDeviceArea builds a type 17 formatted area (offsets 0x04-0x21).
+func memoryDeviceArea(size uint16, extended uint32, memType byte, speed, configured uint16) []byte {
+	area := make([]byte, 0x22-4)
+	put16 := func(offset int, v uint16) { binary.LittleEndian.PutUint16(area[offset-4:], v) }
+	put16(0x0C, size)
+	area[0x10-4] = 1 // device locator
+	area[0x11-4] = 2 // bank locator
+	area[0x12-4] = memType
+	put16(0x15, speed)
+	area[0x17-4] = 3 // manufacturer
+	area[0x18-4] = 4 // serial
+	area[0x1A-4] = 5 // part number
+	binary.LittleEndian.PutUint32(area[0x1C-4:], extended)
+	put16(0x20, configured)
+	return area
+}
+
+func TestApplySMBIOS(t *testing.T) {
+	var table []byte
+	// BIOS: vendor=1, version=2, date=3
+	table = append(table, smbiosEntry(smbiosTypeBIOS,
+		[]byte{1, 2, 0, 0, 3, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		"American Megatrends Inc.", "F12", "03/15/2024")...)
+	table = append(table, smbiosEntry(smbiosTypeSystem,
+		[]byte{1, 2, 0, 0}, "Gigabyte Technology Co., Ltd.", "To be filled by O.E.M.")...)
+	table = append(table, smbiosEntry(smbiosTypeBaseboard,
+		[]byte{1, 2, 3, 0}, "Gigabyte Technology Co., Ltd.", "B550 AORUS ELITE", "x.x")...)
+	table = append(table, smbiosEntry(smbiosTypeMemoryDevice,
+		memoryDeviceArea(16384, 0, 0x1A, 3600, 3200),
+		"DIMM_A2", "BANK 1", "G.Skill", "00000000", "F4-3600C16-16GVKC  ")...)
+	// Empty slot
+	table = append(table, smbiosEntry(smbiosTypeMemoryDevice,
+		memoryDeviceArea(0, 0, 0x02, 0, 0),
+		"DIMM_B1", "BANK 2", "Unknown", "Unknown", "Unknown")...)
+	// Extended size (64 GB)
+	table = append(table, smbiosEntry(smbiosTypeMemoryDevice,
+		memoryDeviceArea(0x7FFF, 65536, 0x22, 5600, 4800),
+		"DIMM_B2", "BANK 3", "Samsung", "1234ABCD", "M321R8GA0BB0")...)
+	table = append(table, smbiosEntry(smbiosTypeEnd, nil)...)
+	// Trailing data after the end structure must be ignored
+	table = append(table, smbiosEntry(smbiosTypeBIOS, []byte{1}, "garbage")...)
+
+	info := &models.SystemInfo{}
+	applySMBIOS(info, table)
+
+	if info.BIOS.Vendor != "American Megatrends Inc." || info.BIOS.Version != "F12" || info.BIOS.Date != "03/15/2024" {
+		t.Errorf("Unexpected BIOS info: %+v", info.BIOS)
+	}
+	if info.SystemVendor != "Gigabyte Technology Co., Ltd." {
+		t.Errorf("Expected system vendor, got %q", info.SystemVendor)
+	}
+	if info.SystemProduct != "" {
+		t.Errorf("Expected placeholder product to be dropped, got %q", info.SystemProduct)
+	}
+	if info.Board.Product != "B550 AORUS ELITE" || info.Board.Version != "x.x" {
+		t.Errorf("Unexpected board info: %+v", info.Board)
+	}
+
+	if len(info.MemoryModules) != 2 {
+		t.Fatalf("Expected 2 populated memory modules, got %d", len(info.MemoryModules))
+	}
+	ddr4 := info.MemoryModules[0]
+	if ddr4.Locator != "DIMM_A2" || ddr4.SizeMB != 16384 || ddr4.Type != "DDR4" ||
+		ddr4.SpeedMTs != 3600 || ddr4.ConfiguredSpeedMTs != 3200 {
+		t.Errorf("Unexpected DDR4 module: %+v", ddr4)
+	}
+	if ddr4.PartNumber != "F4-3600C16-16GVKC" || ddr4.Serial != "00000000" {
+		t.Errorf("Unexpected DDR4 module strings: %+v", ddr4)
+	}
+	ddr5 := info.MemoryModules[1]
+	if ddr5.SizeMB != 65536 || ddr5.Type != "DDR5" || ddr5.Manufacturer != "Samsung" {
+		t.Errorf("Unexpected DDR5 module: %+v", ddr5)
+	}
+}
+
+func TestParseSMBIOSTruncated(t *testing.T) {
+	table := smbiosEntry(smbiosTypeBIOS, []byte{1, 2}, "Vendor", "1.0")
+	// Cut off the string set terminator
+	structures := parseSMBIOSStructures(table[:len(table)-2])
+	if len(structures) != 0 {
+		t.Errorf("Expected truncated structure to be dropped, got %d", len(structures))
+	}
+}
+
+// writeSysfsFiles writes name/value files into dir.
+func writeSysfsFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(value+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestReadBlockDevices(t *testing.T) {
+	root := t.TempDir()
+	writeSysfsFiles(t, filepath.Join(root, "nvme0n1"), map[string]string{
+		"size":             "1953525168",
+		"queue/rotational": "0",
+		"device/model":     "Samsung SSD 980 PRO 1TB                 ",
+		"device/serial":    "S5GXNF0R123456A     ",
+	})
+	writeSysfsFiles(t, filepath.Join(root, "sda"), map[string]string{
+		"size":             "7814037168",
+		"queue/rotational": "1",
+		"device/model":     "WDC WD40EZAZ-00S",
+	})
+	writeSysfsFiles(t, filepath.Join(root, "loop0"), map[string]string{
+		"size":         "1024",
+		"device/model": "loop",
+	})
+	// No backing device (virtual)
+	writeSysfsFiles(t, filepath.Join(root, "vda-virtual"), map[string]string{"size": "2048"})
+
+	disks := readBlockDevices(root)
+	if len(disks) != 2 {
+		t.Fatalf("Expected 2 disks, got %d: %+v", len(disks), disks)
+	}
+
+	nvme := disks[0]
+	if nvme.Name != "nvme0n1" || nvme.Model != "Samsung SSD 980 PRO 1TB" || nvme.Serial != "S5GXNF0R123456A" {
+		t.Errorf("Unexpected NVMe disk: %+v", nvme)
+	}
+	if nvme.SizeGB != 1000 || nvme.Rotational {
+		t.Errorf("Expected 1000 GB SSD, got %d GB rotational=%v", nvme.SizeGB, nvme.Rotational)
+	}
+
+	hdd := disks[1]
+	if hdd.Name != "sda" || hdd.SizeGB != 4000 || !hdd.Rotational || hdd.Serial != "" {
+		t.Errorf("Unexpected HDD: %+v", hdd)
+	}
+}
+
+func TestReadDMIID(t *testing.T) {
+	dir := t.TempDir()
+	writeSysfsFiles(t, dir, map[string]string{
+		"sys_vendor":    "LENOVO",
+		"product_name":  "20XW0055GE",
+		"board_vendor":  "LENOVO",
+		"board_name":    "20XW0055GE",
+		"board_version": "Not Defined",
+		"bios_vendor":   "LENOVO",
+		"bios_version":  "N32ET75W (1.51 )",
+		"bios_date":     "01/05/2022",
+	})
+
+	// Values from SMBIOS take precedence
+	info := &models.SystemInfo{SystemProduct: "ThinkPad X1 Carbon Gen 9"}
+	readDMIID(info, dir)
+
+	if info.SystemProduct != "ThinkPad X1 Carbon Gen 9" {
+		t.Errorf("Expected existing product to be kept, got %q", info.SystemProduct)
+	}
+	if info.Board.Product != "20XW0055GE" || info.BIOS.Version != "N32ET75W (1.51 )" || info.BIOS.Date != "01/05/2022" {
+		t.Errorf("Unexpected DMI info: %+v %+v", info.Board, info.BIOS)
+	}
+}
@@ -0,0 +1,144 @@
+//go:build windows
+
+package collector
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+var procGetSystemFirmwareTable = kernel32DLL.NewProc("GetSystemFirmwareTable")
+
+const (
+	// firmwareTableRSMB is the 'RSMB' raw SMBIOS firmware table provider.
+	firmwareTableRSMB = 0x52534D42
+	// rawSMBIOSHeaderSize is the size of the RawSMBIOSData header preceding
+	// the table.
+	rawSMBIOSHeaderSize = 8
+
+	ioctlStorageQueryProperty = 0x2D1400
+	ioctlDiskGetLengthInfo    = 0x7405C
+
+	storageDeviceProperty            = 0
+	storageDeviceSeekPenaltyProperty = 7
+	propertyStandardQuery            = 0
+
+	// maxPhysicalDrives bounds the PhysicalDriveN probe.
+	maxPhysicalDrives = 32
+)
+
+// storagePropertyQuery is the STORAGE_PROPERTY_QUERY structure.
+type storagePropertyQuery struct {
+	PropertyID           uint32
+	QueryType            uint32
+	AdditionalParameters [1]byte
+}
+
+// readSMBIOSTable returns the raw SMBIOS table from the firmware.
+func readSMBIOSTable(sysRoot string) ([]byte, error) {
+	size, _, err := procGetSystemFirmwareTable.Call(firmwareTableRSMB, 0, 0, 0)
+	if size == 0 {
+		return nil, err
+	}
+
+	buf := make([]byte, size)
+	ret, _, err := procGetSystemFirmwareTable.Call(firmwareTableRSMB, 0,
+		uintptr(unsafe.Pointer(&buf[0])), size)
+	if ret == 0 {
+		return nil, err
+	}
+	if ret < rawSMBIOSHeaderSize {
+		return nil, fmt.Errorf("SMBIOS table too short")
+	}
+
+	length := binary.LittleEndian.Uint32(buf[4:8])
+	table := buf[rawSMBIOSHeaderSize:ret]
+	if int(length) < len(table) {
+		table = table[:length]
+	}
+	return table, nil
+}
+
+// readPhysicalDisks lists physical disks by querying \\.\PhysicalDriveN.
+func readPhysicalDisks(sysRoot string) []models.DiskDevice {
+	var disks []models.DiskDevice
+	for i := 0; i < maxPhysicalDrives; i++ {
+		if disk, ok := queryPhysicalDrive(i); ok {
+			disks = append(disks, disk)
+		}
+	}
+	return disks
+}
+
+// queryPhysicalDrive reads model, serial and size of a physical drive.
+// Zero access rights are enough for these queries, so no elevation is
+// needed.
+func queryPhysicalDrive(index int) (models.DiskDevice, bool) {
+	name := fmt.Sprintf("PhysicalDrive%d", index)
+	path, err := syscall.UTF16PtrFromString(`\\.\` + name)
+	if err != nil {
+		return models.DiskDevice{}, false
+	}
+
+	handle, err := syscall.CreateFile(path, 0,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE, nil,
+		syscall.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return models.DiskDevice{}, false
+	}
+	defer syscall.CloseHandle(handle)
+
+	disk := models.DiskDevice{Name: name}
+
+	// STORAGE_DEVICE_DESCRIPTOR with trailing vendor/product/serial strings
+	desc := make([]byte, 1024)
+	if storageQuery(handle, storageDeviceProperty, desc) {
+		vendor := descriptorString(desc, binary.LittleEndian.Uint32(desc[12:16]))
+		product := descriptorString(desc, binary.LittleEndian.Uint32(desc[16:20]))
+		disk.Model = strings.TrimSpace(vendor + " " + product)
+		disk.Serial = descriptorString(desc, binary.LittleEndian.Uint32(desc[24:28]))
+	}
+
+	// DEVICE_SEEK_PENALTY_DESCRIPTOR: Version, Size, IncursSeekPenalty
+	penalty := make([]byte, 12)
+	if storageQuery(handle, storageDeviceSeekPenaltyProperty, penalty) {
+		disk.Rotational = penalty[8] != 0
+	}
+
+	var length int64
+	var returned uint32
+	if err := syscall.DeviceIoControl(handle, ioctlDiskGetLengthInfo, nil, 0,
+		(*byte)(unsafe.Pointer(&length)), uint32(unsafe.Sizeof(length)), &returned, nil); err == nil {
+		disk.SizeGB = uint64(length) / 1e9
+	}
+
+	return disk, true
+}
+
+// storageQuery issues a standard IOCTL_STORAGE_QUERY_PROPERTY query.
+func storageQuery(handle syscall.Handle, property uint32, out []byte) bool {
+	query := storagePropertyQuery{PropertyID: property, QueryType: propertyStandardQuery}
+	var returned uint32
+	err := syscall.DeviceIoControl(handle, ioctlStorageQueryProperty,
+		(*byte)(unsafe.Pointer(&query)), uint32(unsafe.Sizeof(query)),
+		&out[0], uint32(len(out)), &returned, nil)
+	return err == nil && returned >= 8
+}
+
+// descriptorString reads a NUL-terminated string at offset in a storage
+// descriptor.
+func descriptorString(buf []byte, offset uint32) string {
+	if offset == 0 || int(offset) >= len(buf) {
+		return ""
+	}
+	s := buf[offset:]
+	if end := strings.IndexByte(string(s), 0); end >= 0 {
+		s = s[:end]
+	}
+	return strings.TrimSpace(string(s))
+}
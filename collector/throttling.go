@@ -0,0 +1,249 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// defaultCPUSysfsRoot is the sysfs directory listing CPUs.
+const defaultCPUSysfsRoot = "/sys/devices/system/cpu"
+
+// Defaults for the throttling heuristics.
+const (
+	defaultThrottleFrequencyRatio = 0.7
+	defaultThrottleLoadPercent    = 50
+	defaultThrottleCPUTempLimit   = 90
+	defaultThrottleGPUTempLimit   = 83
+)
+
+var cpuDirPattern = regexp.MustCompile(`^cpu(\d+)$`)
+
+// cpuFreqSample is the frequency and throttle counter state of all CPUs.
+type cpuFreqSample struct {
+	curMHz       []uint32
+	maxMHz       []uint32
+	coreThrottle map[int]uint64 // cpu -> core_throttle_count
+	pkgThrottle  map[int]uint64 // cpu -> package_throttle_count
+}
+
+// ThrottleDetector detects CPU and GPU throttling from live per-core
+// frequencies, the kernel thermal throttle counters and temperatures.
+//
+// A CPU running well below its maximum frequency under load is throttled:
+// thermally if it is hot (or the thermal counters increased), otherwise by
+// power or current limits.
+type ThrottleDetector struct {
+	root           string
+	frequencyRatio float64
+	loadPercent    float64
+	cpuTempLimit   float64
+	gpuTempLimit   float64
+
+	mu       sync.Mutex
+	last     *cpuFreqSample
+	since    time.Time // start of the current throttling episode
+	throttle bool
+}
+
+// NewThrottleDetector creates a new throttling detector.
+func NewThrottleDetector(cfg *config.ThrottlingConfig) *ThrottleDetector {
+	d := &ThrottleDetector{
+		root:           defaultCPUSysfsRoot,
+		frequencyRatio: defaultThrottleFrequencyRatio,
+		loadPercent:    defaultThrottleLoadPercent,
+		cpuTempLimit:   defaultThrottleCPUTempLimit,
+		gpuTempLimit:   defaultThrottleGPUTempLimit,
+	}
+
+	if cfg != nil {
+		if cfg.Root != "" {
+			d.root = cfg.Root
+		}
+		if cfg.FrequencyRatio > 0 {
+			d.frequencyRatio = cfg.FrequencyRatio
+		}
+		if cfg.LoadPercent > 0 {
+			d.loadPercent = cfg.LoadPercent
+		}
+		if cfg.CPUTempLimit > 0 {
+			d.cpuTempLimit = cfg.CPUTempLimit
+		}
+		if cfg.GPUTempLimit > 0 {
+			d.gpuTempLimit = cfg.GPUTempLimit
+		}
+	}
+
+	return d
+}
+
+// Reset discards the previous throttle counters and ends the current
+// episode, so that a collection gap is not counted as throttling time.
+func (d *ThrottleDetector) Reset() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.last = nil
+	d.throttle = false
+	d.since = time.Time{}
+}
+
+// Detect evaluates the throttling state. It also replaces the cached CPU
+// frequency with the live average and fills the per-core frequencies.
+func (d *ThrottleDetector) Detect(cpu *models.CPUMetrics, gpu models.GPUMetrics, now time.Time) models.ThrottlingState {
+	sample := readCPUFreqs(d.root)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state := models.ThrottlingState{}
+
+	if len(sample.curMHz) > 0 {
+		cpu.PerCoreFrequencyMHz = sample.curMHz
+		var sum uint64
+		ratios := make([]float64, len(sample.curMHz))
+		for i, cur := range sample.curMHz {
+			sum += uint64(cur)
+			if sample.maxMHz[i] > 0 {
+				ratios[i] = float64(cur) / float64(sample.maxMHz[i])
+			}
+			if sample.maxMHz[i] > cpu.MaxFrequencyMHz {
+				cpu.MaxFrequencyMHz = sample.maxMHz[i]
+			}
+		}
+		cpu.FrequencyMHz = uint32(sum / uint64(len(sample.curMHz)))
+		state.FrequencyRatio = loadedFrequencyRatio(ratios, cpu.PerCorePercent, d.loadPercent)
+	}
+
+	if d.last != nil {
+		state.CoreThrottleEvents = counterIncrease(d.last.coreThrottle, sample.coreThrottle, false)
+		state.PackageThrottleEvents = counterIncrease(d.last.pkgThrottle, sample.pkgThrottle, true)
+	}
+	d.last = &sample
+
+	thermal := state.CoreThrottleEvents > 0 || state.PackageThrottleEvents > 0
+	if state.FrequencyRatio > 0 && state.FrequencyRatio < d.frequencyRatio && cpu.UsagePercent >= d.loadPercent {
+		if cpu.Temperature >= d.cpuTempLimit {
+			thermal = true
+		} else if !thermal {
+			state.Reasons = append(state.Reasons, models.ThrottlePower)
+		}
+	}
+	if thermal {
+		state.Reasons = append([]string{models.ThrottleThermal}, state.Reasons...)
+	}
+	if gpu.Available && float64(gpu.TemperatureC) >= d.gpuTempLimit {
+		state.Reasons = append(state.Reasons, models.ThrottleGPUThermal)
+	}
+
+	state.Throttled = len(state.Reasons) > 0
+	if state.Throttled {
+		if !d.throttle {
+			d.since = now
+		}
+		state.Since = d.since
+		state.DurationSec = now.Sub(d.since).Seconds()
+	}
+	d.throttle = state.Throttled
+
+	return state
+}
+
+// loadedFrequencyRatio returns the average frequency ratio of the cores
+// whose usage reaches busyPercent. Idle cores are parked at low clocks, so
+// they are left out. Without per-core usage (or busy cores) the ratio of the
+// fastest core is used, which stays low only if every core is held back.
+// Cores with an unknown maximum have a ratio of 0 and are skipped.
+func loadedFrequencyRatio(ratios, usage []float64, busyPercent float64) float64 {
+	if len(usage) == len(ratios) {
+		var sum float64
+		var count int
+		for i, ratio := range ratios {
+			if ratio > 0 && usage[i] >= busyPercent {
+				sum += ratio
+				count++
+			}
+		}
+		if count > 0 {
+			return sum / float64(count)
+		}
+	}
+
+	var fastest float64
+	for _, ratio := range ratios {
+		fastest = max(fastest, ratio)
+	}
+	return fastest
+}
+
+// counterIncrease sums the increase of the counters since the previous
+// sample. Package counters are shared by all CPUs of a package, so for
+// them the largest increase is used instead of the sum.
+func counterIncrease(prev, cur map[int]uint64, shared bool) uint64 {
+	var total uint64
+	for cpu, value := range cur {
+		old, ok := prev[cpu]
+		if !ok || value < old {
+			continue
+		}
+		delta := value - old
+		if shared {
+			if delta > total {
+				total = delta
+			}
+		} else {
+			total += delta
+		}
+	}
+	return total
+}
+
+// readCPUFreqs reads the current and maximum frequency and the throttle
+// counters of every CPU under root.
+func readCPUFreqs(root string) cpuFreqSample {
+	sample := cpuFreqSample{
+		coreThrottle: make(map[int]uint64),
+		pkgThrottle:  make(map[int]uint64),
+	}
+
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return sample
+	}
+
+	var cpus []int
+	for _, entry := range entries {
+		if m := cpuDirPattern.FindStringSubmatch(entry.Name()); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			cpus = append(cpus, n)
+		}
+	}
+	sort.Ints(cpus)
+
+	for _, n := range cpus {
+		dir := filepath.Join(root, "cpu"+strconv.Itoa(n))
+
+		freqDir := filepath.Join(dir, "cpufreq")
+		if cur, ok := readSysfsInt(freqDir, "scaling_cur_freq"); ok {
+			max, _ := readSysfsInt(freqDir, "cpuinfo_max_freq")
+			sample.curMHz = append(sample.curMHz, uint32(cur/1000))
+			sample.maxMHz = append(sample.maxMHz, uint32(max/1000))
+		}
+
+		throttleDir := filepath.Join(dir, "thermal_throttle")
+		if v, ok := readSysfsInt(throttleDir, "core_throttle_count"); ok {
+			sample.coreThrottle[n] = uint64(v)
+		}
+		if v, ok := readSysfsInt(throttleDir, "package_throttle_count"); ok {
+			sample.pkgThrottle[n] = uint64(v)
+		}
+	}
+
+	return sample
+}
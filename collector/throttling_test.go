@@ -0,0 +1,188 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// writeCPUFreq creates or updates a fake cpuN sysfs directory.
+func writeCPUFreq(t *testing.T, root string, cpu int, curMHz, maxMHz int, coreThrottle, pkgThrottle int) {
+	t.Helper()
+
+	dir := filepath.Join(root, "cpu"+strconv.Itoa(cpu))
+	files := map[string]int{
+		"cpufreq/scaling_cur_freq":                curMHz * 1000,
+		"cpufreq/cpuinfo_max_freq":                maxMHz * 1000,
+		"thermal_throttle/core_throttle_count":    coreThrottle,
+		"thermal_throttle/package_throttle_count": pkgThrottle,
+	}
+	for file, value := range files {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strconv.Itoa(value)+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func newTestThrottleDetector(root string) *ThrottleDetector {
+	return NewThrottleDetector(&config.ThrottlingConfig{
+		Root:           root,
+		FrequencyRatio: 0.7,
+		LoadPercent:    50,
+		CPUTempLimit:   90,
+		GPUTempLimit:   83,
+	})
+}
+
+func TestThrottleDetectorLiveFrequency(t *testing.T) {
+	root := t.TempDir()
+	writeCPUFreq(t, root, 0, 4000, 5000, 0, 0)
+	writeCPUFreq(t, root, 1, 3000, 5000, 0, 0)
+	writeCPUFreq(t, root, 10, 2000, 4000, 0, 0)
+
+	d := newTestThrottleDetector(root)
+	cpu := models.CPUMetrics{UsagePercent: 10, FrequencyMHz: 3600}
+	state := d.Detect(&cpu, models.GPUMetrics{}, time.Now())
+
+	if state.Throttled {
+		t.Errorf("Expected idle CPU not to be throttled, got %+v", state)
+	}
+	if cpu.FrequencyMHz != 3000 {
+		t.Errorf("Expected live average frequency 3000, got %d", cpu.FrequencyMHz)
+	}
+	want := []uint32{4000, 3000, 2000}
+	for i, f := range want {
+		if cpu.PerCoreFrequencyMHz[i] != f {
+			t.Errorf("Core %d: expected %d MHz, got %d", i, f, cpu.PerCoreFrequencyMHz[i])
+		}
+	}
+	if cpu.MaxFrequencyMHz != 5000 {
+		t.Errorf("Expected max frequency 5000, got %d", cpu.MaxFrequencyMHz)
+	}
+	// Without per-core usage, the fastest core
+	if state.FrequencyRatio != 0.8 {
+		t.Errorf("Expected frequency ratio 0.8, got %.3f", state.FrequencyRatio)
+	}
+}
+
+func TestThrottleDetectorBusyCores(t *testing.T) {
+	root := t.TempDir()
+	// Two busy cores at full clocks, two idle cores parked
+	writeCPUFreq(t, root, 0, 5000, 5000, 0, 0)
+	writeCPUFreq(t, root, 1, 4800, 5000, 0, 0)
+	writeCPUFreq(t, root, 2, 800, 5000, 0, 0)
+	writeCPUFreq(t, root, 3, 800, 5000, 0, 0)
+
+	tests := []struct {
+		name      string
+		perCore   []float64
+		ratio     float64
+		throttled bool
+	}{
+		{"busy cores at full clocks", []float64{100, 100, 2, 2}, 0.98, false},
+		{"busy cores held back", []float64{2, 2, 100, 100}, 0.16, true},
+		{"no per-core usage", nil, 1, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestThrottleDetector(root)
+			cpu := models.CPUMetrics{UsagePercent: 51, Temperature: 60, PerCorePercent: tt.perCore}
+			state := d.Detect(&cpu, models.GPUMetrics{}, time.Now())
+
+			if state.FrequencyRatio < tt.ratio-0.001 || state.FrequencyRatio > tt.ratio+0.001 {
+				t.Errorf("Expected frequency ratio %.2f, got %.3f", tt.ratio, state.FrequencyRatio)
+			}
+			if state.Throttled != tt.throttled {
+				t.Errorf("Expected throttled=%v, got %+v", tt.throttled, state)
+			}
+		})
+	}
+}
+
+func TestThrottleDetectorReasons(t *testing.T) {
+	tests := []struct {
+		name    string
+		usage   float64
+		temp    float64
+		gpuTemp uint32
+		reasons []string
+	}{
+		{"idle at low clocks", 10, 50, 60, nil},
+		{"power limited", 90, 70, 60, []string{models.ThrottlePower}},
+		{"hot", 90, 95, 60, []string{models.ThrottleThermal}},
+		{"hot gpu", 10, 50, 85, []string{models.ThrottleGPUThermal}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			writeCPUFreq(t, root, 0, 2000, 5000, 0, 0)
+
+			d := newTestThrottleDetector(root)
+			cpu := models.CPUMetrics{UsagePercent: tt.usage, Temperature: tt.temp}
+			gpu := models.GPUMetrics{Available: true, TemperatureC: tt.gpuTemp}
+			state := d.Detect(&cpu, gpu, time.Now())
+
+			if len(state.Reasons) != len(tt.reasons) {
+				t.Fatalf("Expected reasons %v, got %v", tt.reasons, state.Reasons)
+			}
+			for i := range tt.reasons {
+				if state.Reasons[i] != tt.reasons[i] {
+					t.Errorf("Expected reasons %v, got %v", tt.reasons, state.Reasons)
+				}
+			}
+			if state.Throttled != (len(tt.reasons) > 0) {
+				t.Errorf("Unexpected throttled=%v", state.Throttled)
+			}
+		})
+	}
+}
+
+func TestThrottleDetectorCounters(t *testing.T) {
+	root := t.TempDir()
+	writeCPUFreq(t, root, 0, 5000, 5000, 10, 100)
+	writeCPUFreq(t, root, 1, 5000, 5000, 20, 100)
+
+	d := newTestThrottleDetector(root)
+	start := time.Now()
+	cpu := models.CPUMetrics{}
+	if state := d.Detect(&cpu, models.GPUMetrics{}, start); state.Throttled {
+		t.Fatalf("Expected first sample to only record counters, got %+v", state)
+	}
+
+	// Both cores throttled; the package counter is shared by both
+	writeCPUFreq(t, root, 0, 5000, 5000, 12, 103)
+	writeCPUFreq(t, root, 1, 5000, 5000, 21, 103)
+	state := d.Detect(&cpu, models.GPUMetrics{}, start.Add(time.Second))
+
+	if state.CoreThrottleEvents != 3 || state.PackageThrottleEvents != 3 {
+		t.Errorf("Expected 3 core and 3 package events, got %d and %d",
+			state.CoreThrottleEvents, state.PackageThrottleEvents)
+	}
+	if !state.Throttled || state.Reasons[0] != models.ThrottleThermal {
+		t.Errorf("Expected thermal throttling, got %+v", state)
+	}
+
+	// Still throttling: episode duration grows
+	writeCPUFreq(t, root, 0, 5000, 5000, 13, 104)
+	state = d.Detect(&cpu, models.GPUMetrics{}, start.Add(3*time.Second))
+	if !state.Since.Equal(start.Add(time.Second)) || state.DurationSec != 2 {
+		t.Errorf("Expected episode since +1s lasting 2s, got %v / %.1fs", state.Since, state.DurationSec)
+	}
+
+	// Counters unchanged: throttling is over
+	state = d.Detect(&cpu, models.GPUMetrics{}, start.Add(4*time.Second))
+	if state.Throttled {
+		t.Errorf("Expected throttling to end, got %+v", state)
+	}
+}
@@ -0,0 +1,142 @@
+package collector
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/shirou/gopsutil/v3/process"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// watchEntry is a compiled watchlist entry.
+type watchEntry struct {
+	id      string
+	name    string // normalized with groupKey
+	pattern *regexp.Regexp
+	cmdline *regexp.Regexp
+}
+
+// matches reports whether the process matches all matchers set on the entry.
+// cmdline is resolved lazily, since reading it is expensive.
+func (e *watchEntry) matches(name string, cmdline func() string) bool {
+	if e.name != "" && groupKey(name) != e.name {
+		return false
+	}
+	if e.pattern != nil && !e.pattern.MatchString(name) {
+		return false
+	}
+	if e.cmdline != nil && !e.cmdline.MatchString(cmdline()) {
+		return false
+	}
+	return true
+}
+
+// SetWatchlist sets the processes that are always sampled.
+// Entries with invalid regular expressions are skipped and reported in the error.
+func (c *ProcessCollector) SetWatchlist(entries []config.WatchedProcessConfig) error {
+	compiled := make([]watchEntry, 0, len(entries))
+	var firstErr error
+
+	for _, e := range entries {
+		entry := watchEntry{id: e.ID, name: groupKey(e.Name)}
+
+		if e.Pattern != "" {
+			re, err := regexp.Compile(e.Pattern)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("watchlist %q: invalid pattern: %w", e.ID, err)
+				}
+				continue
+			}
+			entry.pattern = re
+		}
+
+		if e.Cmdline != "" {
+			re, err := regexp.Compile(e.Cmdline)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("watchlist %q: invalid cmdline: %w", e.ID, err)
+				}
+				continue
+			}
+			entry.cmdline = re
+		}
+
+		compiled = append(compiled, entry)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watchlist = compiled
+	c.watched = nil
+
+	return firstErr
+}
+
+// GetWatched returns the watchlist state computed by the last Collect call.
+// Returns nil if the watchlist is empty.
+func (c *ProcessCollector) GetWatched() []models.WatchedProcessInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.watched == nil {
+		return nil
+	}
+
+	watched := make([]models.WatchedProcessInfo, len(c.watched))
+	copy(watched, c.watched)
+	return watched
+}
+
+// newWatchedState returns an empty state for every watchlist entry.
+func newWatchedState(watchlist []watchEntry) []models.WatchedProcessInfo {
+	if len(watchlist) == 0 {
+		return nil
+	}
+
+	watched := make([]models.WatchedProcessInfo, len(watchlist))
+	for i, entry := range watchlist {
+		watched[i].ID = entry.id
+	}
+	return watched
+}
+
+// matchWatchlist adds the process to every watchlist entry it matches and
+// returns the IDs of the matched entries.
+func (c *ProcessCollector) matchWatchlist(p *process.Process, info *models.ProcessInfo, watchlist []watchEntry, watched []models.WatchedProcessInfo) []string {
+	cmdline := func() string {
+		d := c.getDetails(p, info.Name, true)
+		applyDetails(info, d)
+		return d.cmdline
+	}
+
+	var matched []string
+	for i := range watchlist {
+		if !watchlist[i].matches(info.Name, cmdline) {
+			continue
+		}
+
+		// Thread count is skipped for regular processes, fetch it for watched ones
+		if matched == nil && info.Threads == 0 {
+			if threads, err := p.NumThreads(); err == nil {
+				info.Threads = threads
+			}
+		}
+		matched = append(matched, watchlist[i].id)
+
+		w := &watched[i]
+		if !w.Running {
+			w.Running = true
+			w.Name = info.Name
+		}
+		w.ProcessCount++
+		w.PIDs = append(w.PIDs, info.PID)
+		w.CPUPercent += info.CPUPercent
+		w.MemoryMB += info.MemoryMB
+		w.Threads += info.Threads
+	}
+
+	return matched
+}
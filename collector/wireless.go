@@ -0,0 +1,348 @@
+package collector
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+const (
+	// defaultProcWireless is the wireless extensions statistics file.
+	defaultProcWireless = "/proc/net/wireless"
+	// defaultSysClassNet is the sysfs directory listing network interfaces.
+	defaultSysClassNet = "/sys/class/net"
+	// maxWextQuality is the link quality maximum reported by most drivers.
+	maxWextQuality = 70
+)
+
+// wextStats is a wireless extensions statistics entry.
+type wextStats struct {
+	quality float64
+	level   float64
+	noise   float64
+	retries uint64
+}
+
+// wirelessLink is the association state of a wireless interface.
+type wirelessLink struct {
+	connected      bool
+	ssid           string
+	bssid          string
+	signalDBm      float64
+	qualityPercent float64
+	txMbps         float64
+	rxMbps         float64
+	frequencyMHz   int
+}
+
+// WirelessCollector collects Wi-Fi link quality. Signal statistics are read
+// on every sample; the association details (SSID, BSSID, bitrate) are
+// refreshed in the background every link interval.
+type WirelessCollector struct {
+	procPath     string
+	sysNet       string
+	linkInterval time.Duration
+	queryLinks   func(ifaces []string) map[string]wirelessLink
+
+	mu          sync.Mutex
+	links       map[string]wirelessLink
+	linksAt     time.Time
+	refreshing  bool
+	lastRetries map[string]uint64
+	lastAt      time.Time
+}
+
+// NewWirelessCollector creates a new Wi-Fi collector.
+func NewWirelessCollector(cfg *config.WirelessConfig) *WirelessCollector {
+	return newWirelessCollector(defaultProcWireless, defaultSysClassNet, cfg.LinkInterval, queryWirelessLinks)
+}
+
+// newWirelessCollector creates a Wi-Fi collector reading the given paths.
+func newWirelessCollector(procPath, sysNet string, linkInterval time.Duration, queryLinks func([]string) map[string]wirelessLink) *WirelessCollector {
+	if linkInterval <= 0 {
+		linkInterval = 5 * time.Second
+	}
+	return &WirelessCollector{
+		procPath:     procPath,
+		sysNet:       sysNet,
+		linkInterval: linkInterval,
+		queryLinks:   queryLinks,
+		links:        make(map[string]wirelessLink),
+		lastRetries:  make(map[string]uint64),
+	}
+}
+
+// Collect returns the link state of all wireless interfaces by name.
+func (c *WirelessCollector) Collect() map[string]models.WirelessInfo {
+	return c.collectAt(time.Now())
+}
+
+// Reset discards the previous retry counters so that the next rates are
+// not computed across a collection gap.
+func (c *WirelessCollector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lastRetries = make(map[string]uint64)
+	c.lastAt = time.Time{}
+}
+
+// collectAt reads the statistics and merges them with the cached
+// association details.
+func (c *WirelessCollector) collectAt(now time.Time) map[string]models.WirelessInfo {
+	stats := readProcWireless(c.procPath)
+	for _, name := range sysfsWirelessInterfaces(c.sysNet) {
+		if _, ok := stats[name]; !ok {
+			stats[name] = readSysfsWireless(filepath.Join(c.sysNet, name, "wireless"))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := make(map[string]bool, len(stats)+len(c.links))
+	for name := range stats {
+		names[name] = true
+	}
+	for name := range c.links {
+		names[name] = true
+	}
+
+	elapsed := now.Sub(c.lastAt).Seconds()
+	result := make(map[string]models.WirelessInfo, len(names))
+	for name := range names {
+		link := c.links[name]
+		info := models.WirelessInfo{
+			Connected:     link.connected,
+			SSID:          link.ssid,
+			BSSID:         link.bssid,
+			SignalDBm:     link.signalDBm,
+			LinkQuality:   link.qualityPercent,
+			TxBitrateMbps: link.txMbps,
+			RxBitrateMbps: link.rxMbps,
+			FrequencyMHz:  link.frequencyMHz,
+		}
+
+		if st, ok := stats[name]; ok {
+			if st.level != 0 {
+				info.Connected = true
+				info.SignalDBm = st.level
+				info.LinkQuality = st.quality / maxWextQuality * 100
+				if info.LinkQuality > 100 {
+					info.LinkQuality = 100
+				}
+			}
+			info.NoiseDBm = st.noise
+
+			if prev, ok := c.lastRetries[name]; ok && !c.lastAt.IsZero() && elapsed > 0 && st.retries >= prev {
+				info.RetriesPerSec = float64(st.retries-prev) / elapsed
+			}
+			c.lastRetries[name] = st.retries
+		}
+
+		result[name] = info
+	}
+	c.lastAt = now
+
+	// Refresh association details in the background
+	if c.queryLinks != nil && !c.refreshing && now.Sub(c.linksAt) >= c.linkInterval {
+		c.refreshing = true
+		ifaces := make([]string, 0, len(stats))
+		for name := range stats {
+			ifaces = append(ifaces, name)
+		}
+		sort.Strings(ifaces)
+		go c.refreshLinks(ifaces, now)
+	}
+
+	return result
+}
+
+// refreshLinks queries the association details and caches them.
+func (c *WirelessCollector) refreshLinks(ifaces []string, at time.Time) {
+	defer recoverPanic("Wireless")
+
+	links := c.queryLinks(ifaces)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if links == nil {
+		links = make(map[string]wirelessLink)
+	}
+	c.links = links
+	c.linksAt = at
+	c.refreshing = false
+}
+
+// readProcWireless parses /proc/net/wireless. Lines after the two header
+// lines look like:
+//
+//	wlp2s0: 0000   54.  -56.  -256        0      0      0      0     21        0
+func readProcWireless(path string) map[string]wextStats {
+	stats := make(map[string]wextStats)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return stats
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		// status, link, level, noise, nwid, crypt, frag, retry, misc, beacon
+		if len(fields) < 8 {
+			continue
+		}
+		stats[strings.TrimSpace(name)] = wextStats{
+			quality: parseWextValue(fields[1]),
+			level:   wextDBm(parseWextValue(fields[2])),
+			noise:   wextDBm(parseWextValue(fields[3])),
+			retries: uint64(parseWextValue(fields[7])),
+		}
+	}
+	return stats
+}
+
+// sysfsWirelessInterfaces lists interfaces with a wireless PHY in sysfs.
+func sysfsWirelessInterfaces(sysNet string) []string {
+	entries, err := os.ReadDir(sysNet)
+	if err != nil {
+		return nil
+	}
+
+	var names []string
+	for _, entry := range entries {
+		dir := filepath.Join(sysNet, entry.Name())
+		for _, marker := range []string{"wireless", "phy80211"} {
+			if _, err := os.Stat(filepath.Join(dir, marker)); err == nil {
+				names = append(names, entry.Name())
+				break
+			}
+		}
+	}
+	return names
+}
+
+// readSysfsWireless reads the legacy sysfs wireless statistics
+// (CONFIG_WIRELESS_EXT_SYSFS). Missing files yield zero values.
+func readSysfsWireless(dir string) wextStats {
+	read := func(name string) float64 {
+		return parseWextValue(readSysfsString(dir, name))
+	}
+	return wextStats{
+		quality: read("link"),
+		level:   wextDBm(read("level")),
+		noise:   wextDBm(read("noise")),
+		retries: uint64(read("retries")),
+	}
+}
+
+// parseWextValue parses a wireless extensions value; drivers append a "."
+// to values that were updated since the last read.
+func parseWextValue(s string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(s, "."), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// wextDBm converts a wireless extensions level to dBm. Some drivers report
+// the 8-bit unsigned value (e.g. 200 for -56 dBm); -256 means "not
+// available".
+func wextDBm(v float64) float64 {
+	switch {
+	case v <= -256:
+		return 0
+	case v > 63:
+		return v - 256
+	}
+	return v
+}
+
+// parseIWLink parses the output of "iw dev <iface> link".
+func parseIWLink(output string) wirelessLink {
+	var link wirelessLink
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if rest, ok := strings.CutPrefix(line, "Connected to "); ok {
+			link.connected = true
+			if fields := strings.Fields(rest); len(fields) > 0 {
+				link.bssid = strings.ToLower(fields[0])
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		fields := strings.Fields(value)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch key {
+		case "SSID":
+			link.ssid = value
+		case "freq":
+			if f, err := strconv.ParseFloat(fields[0], 64); err == nil {
+				link.frequencyMHz = int(f)
+			}
+		case "signal":
+			link.signalDBm, _ = strconv.ParseFloat(fields[0], 64)
+		case "rx bitrate":
+			link.rxMbps, _ = strconv.ParseFloat(fields[0], 64)
+		case "tx bitrate":
+			link.txMbps, _ = strconv.ParseFloat(fields[0], 64)
+		}
+	}
+
+	return link
+}
+
+// mergeWireless attaches the Wi-Fi state to the matching interfaces.
+// Wireless interfaces without traffic are added so that their link quality
+// is still reported.
+func mergeWireless(metrics *models.NetworkMetrics, wireless map[string]models.WirelessInfo) {
+	seen := make(map[string]bool, len(wireless))
+	for i := range metrics.Interfaces {
+		iface := &metrics.Interfaces[i]
+		if info, ok := wireless[iface.Name]; ok {
+			info := info
+			iface.Wireless = &info
+			seen[iface.Name] = true
+		}
+	}
+
+	names := make([]string, 0, len(wireless))
+	for name := range wireless {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		info := wireless[name]
+		metrics.Interfaces = append(metrics.Interfaces, models.InterfaceInfo{
+			Name:     name,
+			IsUp:     info.Connected,
+			Wireless: &info,
+		})
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package collector
+
+import (
+	"context"
+	"os/exec"
+	"time"
+)
+
+// iwTimeout bounds a single "iw" invocation.
+const iwTimeout = 2 * time.Second
+
+// queryWirelessLinks reads the association details of each interface with
+// "iw". Without iw only the /proc and sysfs statistics are available.
+func queryWirelessLinks(ifaces []string) map[string]wirelessLink {
+	iw, err := exec.LookPath("iw")
+	if err != nil {
+		return nil
+	}
+
+	links := make(map[string]wirelessLink, len(ifaces))
+	for _, iface := range ifaces {
+		ctx, cancel := context.WithTimeout(context.Background(), iwTimeout)
+		output, err := exec.CommandContext(ctx, iw, "dev", iface, "link").Output()
+		cancel()
+		if err != nil {
+			continue
+		}
+		links[iface] = parseIWLink(string(output))
+	}
+	return links
+}
@@ -0,0 +1,189 @@
+package collector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+const procWirelessFixture = `Inter-| sta-|   Quality        |   Discarded packets               | Missed | WE
+ face | tus | link level noise |  nwid  crypt   frag  retry   misc | beacon | 22
+wlp2s0: 0000   54.  -56.  -256        0      0      0     12      0        0
+  wlan1: 0000   35   200   161        0      0      0      4      0        0
+`
+
+func TestReadProcWireless(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wireless")
+	if err := os.WriteFile(path, []byte(procWirelessFixture), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := readProcWireless(path)
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 interfaces, got %d: %+v", len(stats), stats)
+	}
+
+	wlp := stats["wlp2s0"]
+	if wlp.quality != 54 || wlp.level != -56 || wlp.noise != 0 || wlp.retries != 12 {
+		t.Errorf("Unexpected wlp2s0 stats: %+v", wlp)
+	}
+
+	// Unsigned 8-bit levels are converted to dBm
+	wlan := stats["wlan1"]
+	if wlan.level != -56 || wlan.noise != -95 {
+		t.Errorf("Unexpected wlan1 stats: %+v", wlan)
+	}
+
+	if missing := readProcWireless(filepath.Join(t.TempDir(), "missing")); len(missing) != 0 {
+		t.Errorf("Expected no stats for a missing file, got %+v", missing)
+	}
+}
+
+func TestSysfsWireless(t *testing.T) {
+	dir := t.TempDir()
+	writeSysfsFiles(t, dir, map[string]string{
+		"wlan0/wireless/link":    "60",
+		"wlan0/wireless/level":   "-48",
+		"wlan0/wireless/noise":   "-90",
+		"wlan0/wireless/retries": "7",
+		"wlan1/phy80211/name":    "phy1",
+		"eth0/operstate":         "up",
+	})
+
+	names := sysfsWirelessInterfaces(dir)
+	if len(names) != 2 || names[0] != "wlan0" || names[1] != "wlan1" {
+		t.Fatalf("Expected wlan0 and wlan1, got %v", names)
+	}
+
+	stats := readSysfsWireless(filepath.Join(dir, "wlan0", "wireless"))
+	if stats.quality != 60 || stats.level != -48 || stats.noise != -90 || stats.retries != 7 {
+		t.Errorf("Unexpected sysfs stats: %+v", stats)
+	}
+}
+
+func TestParseIWLink(t *testing.T) {
+	output := `Connected to AA:BB:CC:DD:EE:FF (on wlp2s0)
+	SSID: Home Network
+	freq: 5180.0
+	RX: 123456 bytes (789 packets)
+	TX: 65432 bytes (321 packets)
+	signal: -61 dBm
+	rx bitrate: 866.7 MBit/s VHT-MCS 9 80MHz short GI VHT-NSS 2
+	tx bitrate: 780.0 MBit/s VHT-MCS 8 80MHz short GI VHT-NSS 2
+`
+	link := parseIWLink(output)
+	if !link.connected || link.bssid != "aa:bb:cc:dd:ee:ff" || link.ssid != "Home Network" {
+		t.Errorf("Unexpected association: %+v", link)
+	}
+	if link.frequencyMHz != 5180 || link.signalDBm != -61 || link.rxMbps != 866.7 || link.txMbps != 780 {
+		t.Errorf("Unexpected link values: %+v", link)
+	}
+
+	if link := parseIWLink("Not connected.\n"); link.connected {
+		t.Errorf("Expected a disconnected link, got %+v", link)
+	}
+}
+
+func TestWirelessCollectAt(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wireless")
+	write := func(retries string) {
+		line := "wlp2s0: 0000   35.  -60.  -256   0   0   0   " + retries + "   0   0\n"
+		if err := os.WriteFile(path, []byte("header\nheader\n"+line), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queried := make(chan []string, 1)
+	query := func(ifaces []string) map[string]wirelessLink {
+		queried <- ifaces
+		return map[string]wirelessLink{
+			"wlp2s0": {connected: true, ssid: "Home", bssid: "aa:bb:cc:dd:ee:ff", signalDBm: -58, txMbps: 400, rxMbps: 300, frequencyMHz: 2437},
+		}
+	}
+	c := newWirelessCollector(path, filepath.Join(dir, "net"), time.Minute, query)
+
+	start := time.Now()
+	write("100")
+	info := c.collectAt(start)["wlp2s0"]
+	if !info.Connected || info.SignalDBm != -60 || info.LinkQuality != 50 || info.RetriesPerSec != 0 {
+		t.Errorf("Unexpected first sample: %+v", info)
+	}
+
+	// The association details are refreshed in the background
+	select {
+	case ifaces := <-queried:
+		if len(ifaces) != 1 || ifaces[0] != "wlp2s0" {
+			t.Errorf("Expected a query for wlp2s0, got %v", ifaces)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the association details to be queried")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		c.mu.Lock()
+		refreshing := c.refreshing
+		c.mu.Unlock()
+		if !refreshing {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Association refresh did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	write("120")
+	info = c.collectAt(start.Add(2 * time.Second))["wlp2s0"]
+	if info.SSID != "Home" || info.BSSID != "aa:bb:cc:dd:ee:ff" || info.TxBitrateMbps != 400 || info.FrequencyMHz != 2437 {
+		t.Errorf("Expected cached association details, got %+v", info)
+	}
+	// The statistics file takes precedence over the cached signal
+	if info.SignalDBm != -60 {
+		t.Errorf("Expected signal from /proc/net/wireless, got %.0f", info.SignalDBm)
+	}
+	if info.RetriesPerSec != 10 {
+		t.Errorf("Expected 10 retries/s, got %.1f", info.RetriesPerSec)
+	}
+
+	// No refresh before the link interval elapses
+	select {
+	case <-queried:
+		t.Error("Unexpected association query within the link interval")
+	default:
+	}
+
+	// After a reset the first rate is not computed across the gap
+	c.Reset()
+	write("500")
+	if info := c.collectAt(start.Add(time.Hour))["wlp2s0"]; info.RetriesPerSec != 0 {
+		t.Errorf("Expected no retry rate after reset, got %.1f", info.RetriesPerSec)
+	}
+	<-queried
+}
+
+func TestMergeWireless(t *testing.T) {
+	metrics := models.NetworkMetrics{
+		Interfaces: []models.InterfaceInfo{{Name: "eth0", IsUp: true}, {Name: "wlan0", IsUp: true}},
+	}
+	mergeWireless(&metrics, map[string]models.WirelessInfo{
+		"wlan0": {Connected: true, SignalDBm: -50},
+		"wlan1": {Connected: false},
+	})
+
+	if len(metrics.Interfaces) != 3 {
+		t.Fatalf("Expected the idle wireless interface to be added, got %d interfaces", len(metrics.Interfaces))
+	}
+	if metrics.Interfaces[0].Wireless != nil {
+		t.Error("Expected no wireless info on eth0")
+	}
+	if w := metrics.Interfaces[1].Wireless; w == nil || w.SignalDBm != -50 {
+		t.Errorf("Expected wireless info on wlan0, got %+v", w)
+	}
+	if iface := metrics.Interfaces[2]; iface.Name != "wlan1" || iface.IsUp || iface.Wireless == nil {
+		t.Errorf("Unexpected added interface: %+v", iface)
+	}
+}
@@ -0,0 +1,171 @@
+//go:build windows
+
+package collector
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+var (
+	wlanapiDLL             = syscall.NewLazyDLL("wlanapi.dll")
+	procWlanOpenHandle     = wlanapiDLL.NewProc("WlanOpenHandle")
+	procWlanCloseHandle    = wlanapiDLL.NewProc("WlanCloseHandle")
+	procWlanEnumInterfaces = wlanapiDLL.NewProc("WlanEnumInterfaces")
+	procWlanQueryInterface = wlanapiDLL.NewProc("WlanQueryInterface")
+	procWlanFreeMemory     = wlanapiDLL.NewProc("WlanFreeMemory")
+)
+
+const (
+	wlanClientVersion               = 2
+	wlanIntfOpcodeCurrentConnection = 7
+	wlanIntfOpcodeRSSI              = 0x10000102
+	wlanInterfaceStateConnected     = 1
+
+	// WLAN_INTERFACE_INFO: GUID, WCHAR[256] description, state
+	wlanInterfaceInfoSize      = 532
+	wlanInterfaceListHeader    = 8
+	wlanInterfaceStateOffset   = 16 + 512
+	wlanInterfaceDescrOffset   = 16
+	wlanConnectionAttrsMinSize = 588
+
+	// Offsets in WLAN_CONNECTION_ATTRIBUTES (WLAN_ASSOCIATION_ATTRIBUTES
+	// starts at 520)
+	wlanSSIDLengthOffset = 520
+	wlanSSIDOffset       = 524
+	wlanBSSIDOffset      = 560
+	wlanQualityOffset    = 576
+	wlanRxRateOffset     = 580
+	wlanTxRateOffset     = 584
+
+	// gaaFlagIncludeAllInterfaces (GAA_FLAG_INCLUDE_ALL_INTERFACES) also
+	// lists adapters that are down
+	gaaFlagIncludeAllInterfaces = 0x0100
+)
+
+// queryWirelessLinks reads the association details of all WLAN interfaces
+// from the native Wi-Fi API, keyed by adapter friendly name.
+func queryWirelessLinks(ifaces []string) map[string]wirelessLink {
+	if err := wlanapiDLL.Load(); err != nil {
+		return nil
+	}
+
+	var negotiated uint32
+	var handle uintptr
+	ret, _, _ := procWlanOpenHandle.Call(wlanClientVersion, 0,
+		uintptr(unsafe.Pointer(&negotiated)), uintptr(unsafe.Pointer(&handle)))
+	if ret != 0 {
+		return nil
+	}
+	defer procWlanCloseHandle.Call(handle, 0)
+
+	var list unsafe.Pointer
+	ret, _, _ = procWlanEnumInterfaces.Call(handle, 0, uintptr(unsafe.Pointer(&list)))
+	if ret != 0 || list == nil {
+		return nil
+	}
+	defer procWlanFreeMemory.Call(uintptr(list))
+
+	names := adapterFriendlyNames()
+	count := *(*uint32)(list)
+	links := make(map[string]wirelessLink, count)
+
+	for i := 0; i < int(count); i++ {
+		item := unsafe.Add(list, wlanInterfaceListHeader+i*wlanInterfaceInfoSize)
+		guid := *(*windows.GUID)(item)
+		state := *(*uint32)(unsafe.Add(item, wlanInterfaceStateOffset))
+
+		name := names[strings.ToUpper(guid.String())]
+		if name == "" {
+			name = windows.UTF16PtrToString((*uint16)(unsafe.Add(item, wlanInterfaceDescrOffset)))
+		}
+
+		var link wirelessLink
+		if state == wlanInterfaceStateConnected {
+			link = queryWlanConnection(handle, &guid)
+		}
+		links[name] = link
+	}
+
+	return links
+}
+
+// queryWlanConnection reads the current connection of a WLAN interface.
+func queryWlanConnection(handle uintptr, guid *windows.GUID) wirelessLink {
+	var link wirelessLink
+
+	attrs, ok := queryWlanInterface(handle, guid, wlanIntfOpcodeCurrentConnection)
+	if !ok || len(attrs) < wlanConnectionAttrsMinSize {
+		return link
+	}
+
+	link.connected = true
+	ssidLen := binary.LittleEndian.Uint32(attrs[wlanSSIDLengthOffset:])
+	if ssidLen > 32 {
+		ssidLen = 32
+	}
+	link.ssid = string(attrs[wlanSSIDOffset : wlanSSIDOffset+ssidLen])
+
+	bssid := attrs[wlanBSSIDOffset : wlanBSSIDOffset+6]
+	link.bssid = fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x",
+		bssid[0], bssid[1], bssid[2], bssid[3], bssid[4], bssid[5])
+
+	link.qualityPercent = float64(binary.LittleEndian.Uint32(attrs[wlanQualityOffset:]))
+	link.rxMbps = float64(binary.LittleEndian.Uint32(attrs[wlanRxRateOffset:])) / 1000
+	link.txMbps = float64(binary.LittleEndian.Uint32(attrs[wlanTxRateOffset:])) / 1000
+
+	if rssi, ok := queryWlanInterface(handle, guid, wlanIntfOpcodeRSSI); ok && len(rssi) >= 4 {
+		link.signalDBm = float64(int32(binary.LittleEndian.Uint32(rssi)))
+	} else {
+		// Documented mapping of signal quality (0-100) to -100..-50 dBm
+		link.signalDBm = link.qualityPercent/2 - 100
+	}
+
+	return link
+}
+
+// queryWlanInterface runs WlanQueryInterface and copies the result.
+func queryWlanInterface(handle uintptr, guid *windows.GUID, opcode uint32) ([]byte, bool) {
+	var size uint32
+	var data unsafe.Pointer
+	ret, _, _ := procWlanQueryInterface.Call(handle, uintptr(unsafe.Pointer(guid)), uintptr(opcode), 0,
+		uintptr(unsafe.Pointer(&size)), uintptr(unsafe.Pointer(&data)), 0)
+	if ret != 0 || data == nil {
+		return nil, false
+	}
+	defer procWlanFreeMemory.Call(uintptr(data))
+
+	buf := make([]byte, size)
+	copy(buf, unsafe.Slice((*byte)(data), size))
+	return buf, true
+}
+
+// adapterFriendlyNames maps adapter GUIDs ("{...}") to friendly names, the
+// interface names used by the network counters.
+func adapterFriendlyNames() map[string]string {
+	names := make(map[string]string)
+
+	size := uint32(15000)
+	for attempt := 0; attempt < 3; attempt++ {
+		buf := make([]byte, size)
+		addrs := (*windows.IpAdapterAddresses)(unsafe.Pointer(&buf[0]))
+		err := windows.GetAdaptersAddresses(syscall.AF_UNSPEC, gaaFlagIncludeAllInterfaces, 0, addrs, &size)
+		if err == windows.ERROR_BUFFER_OVERFLOW {
+			continue
+		}
+		if err != nil {
+			return names
+		}
+		for a := addrs; a != nil; a = a.Next {
+			guid := strings.ToUpper(windows.BytePtrToString(a.AdapterName))
+			names[guid] = windows.UTF16PtrToString(a.FriendlyName)
+		}
+		return names
+	}
+	return names
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,6 +24,9 @@ type Config struct {
 	UI         UIConfig         `mapstructure:"ui"`
 	Overlay    OverlayConfig    `mapstructure:"overlay"`
 	Logging    LoggingConfig    `mapstructure:"logging"`
+	Benchmark  BenchmarkConfig  `mapstructure:"benchmark"`
+	// FlightRecorder configures dumps of the history around alerts.
+	FlightRecorder FlightRecorderConfig `mapstructure:"flight_recorder"`
 }
 
 // MonitoringConfig holds monitoring-related settings.
@@ -30,12 +35,264 @@ type MonitoringConfig struct {
 	UpdateInterval time.Duration `mapstructure:"update_interval"`
 	// HistoryDuration is how long to keep metrics history.
 	HistoryDuration time.Duration `mapstructure:"history_duration"`
+	// Adaptive configures activity-based adjustment of the collection interval.
+	Adaptive AdaptiveSamplingConfig `mapstructure:"adaptive"`
 	// EnableGPU enables GPU monitoring (requires NVIDIA GPU with NVML).
 	EnableGPU bool `mapstructure:"enable_gpu"`
 	// EnableProcesses enables top processes monitoring.
 	EnableProcesses bool `mapstructure:"enable_processes"`
 	// TopProcessCount is how many top processes to track.
 	TopProcessCount int `mapstructure:"top_process_count"`
+	// ProcessGrouping configures aggregation of processes into groups.
+	ProcessGrouping ProcessGroupingConfig `mapstructure:"process_grouping"`
+	// ProcessGPU configures per-process GPU usage and VRAM monitoring.
+	ProcessGPU ProcessGPUConfig `mapstructure:"process_gpu"`
+	// Watchlist lists processes that are always sampled, regardless of their rank.
+	Watchlist []WatchedProcessConfig `mapstructure:"watchlist"`
+	// Ping configures network latency probes.
+	Ping PingConfig `mapstructure:"ping"`
+	// GameServer configures latency probes to the game's own server.
+	GameServer GameServerConfig `mapstructure:"game_server"`
+	// Power configures battery and power supply monitoring.
+	Power PowerConfig `mapstructure:"power"`
+	// Sensors configures hardware sensor (hwmon) monitoring.
+	Sensors SensorsConfig `mapstructure:"sensors"`
+	// RAPL configures CPU package and DRAM power measurement.
+	RAPL RAPLConfig `mapstructure:"rapl"`
+	// Throttling configures CPU/GPU throttling detection.
+	Throttling ThrottlingConfig `mapstructure:"throttling"`
+	// Wireless configures Wi-Fi link quality monitoring.
+	Wireless WirelessConfig `mapstructure:"wireless"`
+	// Cgroups configures per-cgroup (systemd unit, container) resource monitoring.
+	Cgroups CgroupsConfig `mapstructure:"cgroups"`
+	// Bottleneck configures classification of what limits performance.
+	Bottleneck BottleneckConfig `mapstructure:"bottleneck"`
+	// LeakDetection configures detection of processes with growing memory.
+	LeakDetection LeakDetectionConfig `mapstructure:"leak_detection"`
+}
+
+// AdaptiveSamplingConfig holds adaptive collection interval settings. When
+// enabled, the interval moves between MinInterval and MaxInterval depending
+// on load, active alerts and overlay visibility.
+type AdaptiveSamplingConfig struct {
+	// Enabled enables adaptive sampling.
+	Enabled bool `mapstructure:"enabled"`
+	// MinInterval is the interval used under load or while an alert is active.
+	MinInterval time.Duration `mapstructure:"min_interval"`
+	// MaxInterval is the interval used while idle with the overlay hidden.
+	MaxInterval time.Duration `mapstructure:"max_interval"`
+	// IdlePercent is the CPU/GPU usage at or below which the system is idle.
+	IdlePercent float64 `mapstructure:"idle_percent"`
+	// BusyPercent is the CPU/GPU usage at or above which the system is busy.
+	BusyPercent float64 `mapstructure:"busy_percent"`
+	// IdleSamples is how many consecutive idle samples are needed before slowing down.
+	IdleSamples int `mapstructure:"idle_samples"`
+}
+
+// WirelessConfig holds Wi-Fi link quality monitoring settings.
+type WirelessConfig struct {
+	// Enabled enables Wi-Fi monitoring.
+	Enabled bool `mapstructure:"enabled"`
+	// LinkInterval is how often SSID, BSSID and bitrate are refreshed.
+	LinkInterval time.Duration `mapstructure:"link_interval"`
+}
+
+// CgroupsConfig holds cgroup v2 resource monitoring settings.
+type CgroupsConfig struct {
+	// Enabled enables cgroup monitoring.
+	Enabled bool `mapstructure:"enabled"`
+	// Root is the cgroup v2 mount point (default /sys/fs/cgroup).
+	Root string `mapstructure:"root"`
+	// Paths lists cgroups relative to Root (e.g. "system.slice/docker.service").
+	Paths []string `mapstructure:"paths"`
+	// Slices lists cgroups whose direct children are all monitored (e.g. "system.slice").
+	Slices []string `mapstructure:"slices"`
+}
+
+// ThrottlingConfig holds throttling detection settings.
+type ThrottlingConfig struct {
+	// Enabled enables throttling detection.
+	Enabled bool `mapstructure:"enabled"`
+	// Root is the CPU sysfs directory (default /sys/devices/system/cpu).
+	Root string `mapstructure:"root"`
+	// FrequencyRatio is the live/max frequency ratio below which a loaded CPU is throttled.
+	FrequencyRatio float64 `mapstructure:"frequency_ratio"`
+	// LoadPercent is the CPU usage above which low frequency counts as throttling.
+	LoadPercent float64 `mapstructure:"load_percent"`
+	// CPUTempLimit is the CPU temperature treated as thermal throttling in Celsius.
+	CPUTempLimit float64 `mapstructure:"cpu_temp_limit"`
+	// GPUTempLimit is the GPU temperature treated as thermal throttling in Celsius.
+	GPUTempLimit float64 `mapstructure:"gpu_temp_limit"`
+}
+
+// BottleneckConfig holds bottleneck classifier settings.
+type BottleneckConfig struct {
+	// Enabled enables the classifier (and per-core CPU usage collection).
+	Enabled bool `mapstructure:"enabled"`
+	// Window is how much recent history the classification is based on
+	// (stretched to the last 3 samples at long collection intervals).
+	Window time.Duration `mapstructure:"window"`
+}
+
+// LeakDetectionConfig holds memory leak detection settings. Process memory
+// is downsampled to one point per SampleInterval and kept for Window.
+type LeakDetectionConfig struct {
+	// Enabled enables leak detection.
+	Enabled bool `mapstructure:"enabled"`
+	// SampleInterval is the downsampling interval of the memory history.
+	SampleInterval time.Duration `mapstructure:"sample_interval"`
+	// Window is how long the memory history is kept and analyzed.
+	Window time.Duration `mapstructure:"window"`
+	// MinDuration is how long a process must grow before it is reported.
+	MinDuration time.Duration `mapstructure:"min_duration"`
+	// MinGrowthMBPerHour is the smallest growth rate reported as a leak.
+	MinGrowthMBPerHour float64 `mapstructure:"min_growth_mb_per_hour"`
+}
+
+// RAPLConfig holds CPU power (RAPL powercap) measurement settings.
+type RAPLConfig struct {
+	// Enabled enables CPU package and DRAM power measurement.
+	Enabled bool `mapstructure:"enabled"`
+	// Root is the powercap sysfs directory (default /sys/class/powercap).
+	Root string `mapstructure:"root"`
+}
+
+// ProcessGPUConfig holds per-process GPU usage monitoring settings.
+type ProcessGPUConfig struct {
+	// Enabled enables per-process GPU engine utilisation and VRAM usage.
+	Enabled bool `mapstructure:"enabled"`
+	// Root is the procfs directory with DRM fdinfo on Linux (default /proc).
+	Root string `mapstructure:"root"`
+}
+
+// SensorsConfig holds hardware sensor monitoring settings.
+type SensorsConfig struct {
+	// Enabled enables hardware sensor monitoring.
+	Enabled bool `mapstructure:"enabled"`
+	// Root is the hwmon sysfs directory (default /sys/class/hwmon).
+	Root string `mapstructure:"root"`
+	// Sensors customizes individual sensors.
+	Sensors []SensorConfig `mapstructure:"sensors"`
+}
+
+// SensorConfig customizes a single sensor.
+type SensorConfig struct {
+	// ID is the sensor identifier ("chip/sensor", e.g. "nct6775/fan2").
+	ID string `mapstructure:"id"`
+	// Name is the friendly display name.
+	Name string `mapstructure:"name"`
+	// Max alerts when the value reaches this threshold (0 = disabled).
+	Max float64 `mapstructure:"max"`
+	// Min alerts when the value drops to this threshold (0 = disabled).
+	Min float64 `mapstructure:"min"`
+	// Hidden removes the sensor from the readings.
+	Hidden bool `mapstructure:"hidden"`
+}
+
+// PowerConfig holds battery and power supply monitoring settings.
+type PowerConfig struct {
+	// Enabled enables battery monitoring.
+	Enabled bool `mapstructure:"enabled"`
+	// Root is the power supply sysfs directory (default /sys/class/power_supply).
+	Root string `mapstructure:"root"`
+	// BatteryInterval is the collection interval while on battery (0 = unchanged).
+	BatteryInterval time.Duration `mapstructure:"battery_interval"`
+}
+
+// GameServerConfig holds settings for detecting and probing the server a
+// game process is connected to.
+type GameServerConfig struct {
+	// Enabled enables game server detection.
+	Enabled bool `mapstructure:"enabled"`
+	// Processes lists game executable names. When empty, the foreground
+	// window's process is used.
+	Processes []string `mapstructure:"processes"`
+	// Interval is how often connections are scanned and the server probed.
+	Interval time.Duration `mapstructure:"interval"`
+	// Timeout is the probe timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// IgnorePorts lists remote ports that are never treated as the game
+	// server (e.g. launcher and telemetry HTTPS connections).
+	IgnorePorts []int `mapstructure:"ignore_ports"`
+}
+
+// PingConfig holds network latency probe settings.
+type PingConfig struct {
+	// Enabled enables latency probes.
+	Enabled bool `mapstructure:"enabled"`
+	// Interval is how often all targets are probed.
+	Interval time.Duration `mapstructure:"interval"`
+	// Timeout is the default probe timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Window is the number of recent probes per target used for statistics.
+	Window int `mapstructure:"window"`
+	// Targets lists the probe targets (built-in defaults are used when empty).
+	Targets []PingTargetConfig `mapstructure:"targets"`
+}
+
+// PingTargetConfig describes a single latency probe target.
+type PingTargetConfig struct {
+	// Name is the display name.
+	Name string `mapstructure:"name"`
+	// Host is the host name or IP address.
+	Host string `mapstructure:"host"`
+	// Port is the port for tcp, udp and http probes.
+	Port int `mapstructure:"port"`
+	// Protocol is the probe type ("tcp", "udp", "icmp", "http", "dns").
+	Protocol string `mapstructure:"protocol"`
+	// URL is the URL for http probes (overrides host and port).
+	URL string `mapstructure:"url"`
+	// Server is the DNS server for dns probes ("1.1.1.1" or "1.1.1.1:53", empty = system resolver).
+	Server string `mapstructure:"server"`
+	// ExpectStatus lists the accepted status codes for http probes (empty = any).
+	ExpectStatus []int `mapstructure:"expect_status"`
+	// BodyContains is a substring the http response body must contain.
+	BodyContains string `mapstructure:"body_contains"`
+	// Timeout overrides the default probe timeout.
+	Timeout time.Duration `mapstructure:"timeout"`
+	// Disabled skips this target.
+	Disabled bool `mapstructure:"disabled"`
+}
+
+// WatchedProcessConfig describes a watchlist entry. A process matches if it
+// satisfies every matcher that is set (Name, Pattern, Cmdline).
+type WatchedProcessConfig struct {
+	// ID is the stable identifier used in history, exports and alerts.
+	ID string `mapstructure:"id"`
+	// Name matches the executable name exactly (case-insensitive, ".exe" optional).
+	Name string `mapstructure:"name"`
+	// Pattern is a regular expression matched against the executable name.
+	Pattern string `mapstructure:"pattern"`
+	// Cmdline is a regular expression matched against the full command line.
+	Cmdline string `mapstructure:"cmdline"`
+	// CPUThreshold is the summed CPU usage percentage that raises an alert (0 = disabled).
+	CPUThreshold float64 `mapstructure:"cpu_threshold"`
+	// MemoryThresholdMB is the summed memory usage in MB that raises an alert (0 = disabled).
+	MemoryThresholdMB uint64 `mapstructure:"memory_threshold_mb"`
+	// AlertOnExit raises an alert when a matching process exits.
+	AlertOnExit bool `mapstructure:"alert_on_exit"`
+}
+
+// ProcessGroupingConfig holds process grouping settings.
+type ProcessGroupingConfig struct {
+	// Enabled enables process grouping.
+	Enabled bool `mapstructure:"enabled"`
+	// Mode is the grouping mode ("executable" or "tree").
+	Mode string `mapstructure:"mode"`
+	// TreeBoundaries lists parent processes that are never used as a tree root
+	// (e.g. explorer.exe), so their children form separate groups.
+	TreeBoundaries []string `mapstructure:"tree_boundaries"`
+	// Rules maps executable names to custom group names.
+	Rules []ProcessGroupRule `mapstructure:"rules"`
+}
+
+// ProcessGroupRule assigns processes matching any of the patterns to a named group.
+type ProcessGroupRule struct {
+	// Name is the group name.
+	Name string `mapstructure:"name"`
+	// Match is a list of executable name patterns (case-insensitive, ".exe" optional,
+	// "*" and "?" wildcards).
+	Match []string `mapstructure:"match"`
 }
 
 // AlertsConfig holds alert threshold settings.
@@ -52,10 +309,40 @@ type AlertsConfig struct {
 	GPUTempThreshold float64 `mapstructure:"gpu_temp_threshold"`
 	// DiskThreshold is the disk usage percentage threshold for alerts.
 	DiskThreshold float64 `mapstructure:"disk_threshold"`
+	// PingThresholdMs is the median latency threshold per ping target in ms (0 = disabled).
+	PingThresholdMs float64 `mapstructure:"ping_threshold_ms"`
+	// JitterThresholdMs is the jitter threshold per ping target in ms (0 = disabled).
+	JitterThresholdMs float64 `mapstructure:"jitter_threshold_ms"`
+	// PacketLossThreshold is the packet loss percentage threshold per ping target (0 = disabled).
+	PacketLossThreshold float64 `mapstructure:"packet_loss_threshold"`
+	// ThrottlingMinDuration is how long throttling must last before alerting (0 = immediately).
+	ThrottlingMinDuration time.Duration `mapstructure:"throttling_min_duration"`
+	// BatteryLowPercent is the battery level alert threshold while on battery (0 = disabled).
+	BatteryLowPercent float64 `mapstructure:"battery_low_percent"`
+	// WifiSignalDBm alerts when the Wi-Fi signal drops to this level in dBm (0 = disabled).
+	WifiSignalDBm float64 `mapstructure:"wifi_signal_dbm"`
+	// CgroupMemoryPercent alerts when a cgroup uses this share of its memory.max (0 = disabled).
+	CgroupMemoryPercent float64 `mapstructure:"cgroup_memory_percent"`
+	// CertExpiryDays alerts when an https target certificate expires within this many days (0 = disabled).
+	CertExpiryDays int `mapstructure:"cert_expiry_days"`
 	// Cooldown is the minimum time between repeated alerts of the same type.
 	Cooldown time.Duration `mapstructure:"cooldown"`
 	// SoundEnabled enables sound notifications.
 	SoundEnabled bool `mapstructure:"sound_enabled"`
+	// Attribution configures naming the processes behind CPU, RAM and GPU alerts.
+	Attribution AttributionConfig `mapstructure:"attribution"`
+}
+
+// AttributionConfig holds settings for attributing resource spikes to processes.
+type AttributionConfig struct {
+	// Enabled attaches the top contributing processes to alerts.
+	Enabled bool `mapstructure:"enabled"`
+	// Window is the recent period treated as the spike.
+	Window time.Duration `mapstructure:"window"`
+	// Baseline is the period before the spike its usage is compared to.
+	Baseline time.Duration `mapstructure:"baseline"`
+	// Count is the maximum number of contributors per alert.
+	Count int `mapstructure:"count"`
 }
 
 // UIConfig holds UI-related settings.
@@ -136,6 +423,37 @@ type LoggingConfig struct {
 	MaxBackups int `mapstructure:"max_backups"`
 }
 
+// BenchmarkConfig holds settings for benchmark capture sessions.
+type BenchmarkConfig struct {
+	// Dir is where session summaries are written (relative to config dir if not absolute).
+	Dir string `mapstructure:"dir"`
+	// Hotkey is the hotkey to start and stop a session.
+	Hotkey string `mapstructure:"hotkey"`
+	// MaxSamples caps the snapshots kept per session (0 = unlimited).
+	MaxSamples int `mapstructure:"max_samples"`
+	// SaveSamples also writes every snapshot of the session as CSV.
+	SaveSamples bool `mapstructure:"save_samples"`
+}
+
+// FlightRecorderConfig holds settings for dumping the metrics around an
+// alert (or an on-demand trigger) to a file.
+type FlightRecorderConfig struct {
+	// Enabled dumps the history when an alert fires.
+	Enabled bool `mapstructure:"enabled"`
+	// Dir is where dumps are written (relative to config dir if not absolute).
+	Dir string `mapstructure:"dir"`
+	// Before is how much history before the trigger is dumped (at most history_duration).
+	Before time.Duration `mapstructure:"before"`
+	// After is how long to keep recording after the trigger.
+	After time.Duration `mapstructure:"after"`
+	// AlertTypes limits the alert types that trigger a dump (empty = all).
+	AlertTypes []string `mapstructure:"alert_types"`
+	// MaxFiles is the number of dumps to keep, oldest are deleted first (0 = unlimited).
+	MaxFiles int `mapstructure:"max_files"`
+	// Hotkey is the hotkey to dump on demand.
+	Hotkey string `mapstructure:"hotkey"`
+}
+
 // Manager handles configuration loading and saving.
 type Manager struct {
 	mu       sync.RWMutex
@@ -248,6 +566,8 @@ func (m *Manager) Update(modifier func(*Config)) error {
 	m.viper.Set("ui", m.config.UI)
 	m.viper.Set("overlay", m.config.Overlay)
 	m.viper.Set("logging", m.config.Logging)
+	m.viper.Set("benchmark", m.config.Benchmark)
+	m.viper.Set("flight_recorder", m.config.FlightRecorder)
 
 	return nil
 }
@@ -275,9 +595,50 @@ func (m *Manager) setDefaults() {
 	// Monitoring defaults
 	m.viper.SetDefault("monitoring.update_interval", "1s")
 	m.viper.SetDefault("monitoring.history_duration", "60s")
+	m.viper.SetDefault("monitoring.adaptive.enabled", false)
+	m.viper.SetDefault("monitoring.adaptive.min_interval", "500ms")
+	m.viper.SetDefault("monitoring.adaptive.max_interval", "5s")
+	m.viper.SetDefault("monitoring.adaptive.idle_percent", 15.0)
+	m.viper.SetDefault("monitoring.adaptive.busy_percent", 60.0)
+	m.viper.SetDefault("monitoring.adaptive.idle_samples", 5)
 	m.viper.SetDefault("monitoring.enable_gpu", true)
 	m.viper.SetDefault("monitoring.enable_processes", true)
 	m.viper.SetDefault("monitoring.top_process_count", 10)
+	m.viper.SetDefault("monitoring.process_gpu.enabled", true)
+	m.viper.SetDefault("monitoring.ping.enabled", true)
+	m.viper.SetDefault("monitoring.ping.interval", "3s")
+	m.viper.SetDefault("monitoring.ping.timeout", "2s")
+	m.viper.SetDefault("monitoring.ping.window", 20)
+	m.viper.SetDefault("monitoring.sensors.enabled", true)
+	m.viper.SetDefault("monitoring.rapl.enabled", true)
+	m.viper.SetDefault("monitoring.throttling.enabled", true)
+	m.viper.SetDefault("monitoring.throttling.frequency_ratio", 0.7)
+	m.viper.SetDefault("monitoring.throttling.load_percent", 50.0)
+	m.viper.SetDefault("monitoring.throttling.cpu_temp_limit", 90.0)
+	m.viper.SetDefault("monitoring.throttling.gpu_temp_limit", 83.0)
+	m.viper.SetDefault("monitoring.wireless.enabled", true)
+	m.viper.SetDefault("monitoring.wireless.link_interval", "5s")
+	m.viper.SetDefault("monitoring.cgroups.enabled", false)
+	m.viper.SetDefault("monitoring.cgroups.slices", []string{"system.slice"})
+	m.viper.SetDefault("monitoring.bottleneck.enabled", false)
+	m.viper.SetDefault("monitoring.bottleneck.window", "5s")
+	m.viper.SetDefault("monitoring.leak_detection.enabled", true)
+	m.viper.SetDefault("monitoring.leak_detection.sample_interval", "1m")
+	m.viper.SetDefault("monitoring.leak_detection.window", "3h")
+	m.viper.SetDefault("monitoring.leak_detection.min_duration", "30m")
+	m.viper.SetDefault("monitoring.leak_detection.min_growth_mb_per_hour", 50.0)
+	m.viper.SetDefault("monitoring.power.enabled", true)
+	m.viper.SetDefault("monitoring.power.battery_interval", "5s")
+	m.viper.SetDefault("monitoring.game_server.enabled", false)
+	m.viper.SetDefault("monitoring.game_server.interval", "5s")
+	m.viper.SetDefault("monitoring.game_server.timeout", "1s")
+	m.viper.SetDefault("monitoring.game_server.ignore_ports", []int{53, 80, 443})
+	m.viper.SetDefault("monitoring.process_grouping.enabled", false)
+	m.viper.SetDefault("monitoring.process_grouping.mode", "executable")
+	m.viper.SetDefault("monitoring.process_grouping.tree_boundaries", []string{
+		"explorer.exe", "services.exe", "svchost.exe", "wininit.exe", "winlogon.exe",
+		"systemd", "init", "launchd",
+	})
 
 	// Alerts defaults
 	m.viper.SetDefault("alerts.enabled", true)
@@ -286,8 +647,20 @@ func (m *Manager) setDefaults() {
 	m.viper.SetDefault("alerts.gpu_threshold", 85.0)
 	m.viper.SetDefault("alerts.gpu_temp_threshold", 85.0)
 	m.viper.SetDefault("alerts.disk_threshold", 90.0)
+	m.viper.SetDefault("alerts.ping_threshold_ms", 150.0)
+	m.viper.SetDefault("alerts.jitter_threshold_ms", 30.0)
+	m.viper.SetDefault("alerts.packet_loss_threshold", 5.0)
+	m.viper.SetDefault("alerts.cert_expiry_days", 14)
+	m.viper.SetDefault("alerts.cgroup_memory_percent", 90.0)
+	m.viper.SetDefault("alerts.wifi_signal_dbm", -75.0)
+	m.viper.SetDefault("alerts.battery_low_percent", 15.0)
+	m.viper.SetDefault("alerts.throttling_min_duration", "3s")
 	m.viper.SetDefault("alerts.cooldown", "30s")
 	m.viper.SetDefault("alerts.sound_enabled", true)
+	m.viper.SetDefault("alerts.attribution.enabled", true)
+	m.viper.SetDefault("alerts.attribution.window", "5s")
+	m.viper.SetDefault("alerts.attribution.baseline", "30s")
+	m.viper.SetDefault("alerts.attribution.count", 3)
 
 	// UI defaults
 	m.viper.SetDefault("ui.tray_enabled", true)
@@ -327,6 +700,20 @@ func (m *Manager) setDefaults() {
 	m.viper.SetDefault("logging.rotation", "daily")
 	m.viper.SetDefault("logging.max_age", 7)
 	m.viper.SetDefault("logging.max_backups", 5)
+
+	// Benchmark defaults
+	m.viper.SetDefault("benchmark.dir", "benchmarks")
+	m.viper.SetDefault("benchmark.hotkey", "Ctrl+Shift+B")
+	m.viper.SetDefault("benchmark.max_samples", 36000)
+	m.viper.SetDefault("benchmark.save_samples", true)
+
+	// Flight recorder defaults
+	m.viper.SetDefault("flight_recorder.enabled", true)
+	m.viper.SetDefault("flight_recorder.dir", "flight")
+	m.viper.SetDefault("flight_recorder.before", "30s")
+	m.viper.SetDefault("flight_recorder.after", "10s")
+	m.viper.SetDefault("flight_recorder.max_files", 50)
+	m.viper.SetDefault("flight_recorder.hotkey", "Ctrl+Shift+F")
 }
 
 // createDefaultConfig creates a default configuration file.
@@ -377,9 +764,151 @@ func (c *Config) Validate() []error {
 	if c.Monitoring.HistoryDuration < time.Second {
 		errs = append(errs, fmt.Errorf("history_duration must be at least 1s"))
 	}
+	if a := c.Monitoring.Adaptive; a.Enabled {
+		if a.MinInterval < 100*time.Millisecond {
+			errs = append(errs, fmt.Errorf("adaptive min_interval must be at least 100ms"))
+		}
+		if a.MaxInterval < a.MinInterval {
+			errs = append(errs, fmt.Errorf("adaptive max_interval must not be below min_interval"))
+		}
+		if a.IdlePercent < 0 || a.BusyPercent > 100 || a.IdlePercent >= a.BusyPercent {
+			errs = append(errs, fmt.Errorf("adaptive idle_percent must be below busy_percent (0-100)"))
+		}
+		if a.IdleSamples < 1 {
+			errs = append(errs, fmt.Errorf("adaptive idle_samples must be at least 1"))
+		}
+	}
 	if c.Monitoring.TopProcessCount < 1 || c.Monitoring.TopProcessCount > 50 {
 		errs = append(errs, fmt.Errorf("top_process_count must be between 1 and 50"))
 	}
+	if c.Monitoring.ProcessGrouping.Enabled {
+		if mode := c.Monitoring.ProcessGrouping.Mode; mode != "executable" && mode != "tree" {
+			errs = append(errs, fmt.Errorf("invalid process_grouping mode: %s", mode))
+		}
+		for i, rule := range c.Monitoring.ProcessGrouping.Rules {
+			if rule.Name == "" || len(rule.Match) == 0 {
+				errs = append(errs, fmt.Errorf("process_grouping rule %d must have a name and at least one match pattern", i))
+			}
+		}
+	}
+	if c.Monitoring.Ping.Enabled {
+		if c.Monitoring.Ping.Interval < 500*time.Millisecond {
+			errs = append(errs, fmt.Errorf("ping interval must be at least 500ms"))
+		}
+		if c.Monitoring.Ping.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("ping timeout must be positive"))
+		}
+		if c.Monitoring.Ping.Window < 2 || c.Monitoring.Ping.Window > 1000 {
+			errs = append(errs, fmt.Errorf("ping window must be between 2 and 1000"))
+		}
+		validProtocols := map[string]bool{"": true, "tcp": true, "udp": true, "icmp": true, "http": true, "dns": true}
+		targetNames := make(map[string]bool)
+		for i, t := range c.Monitoring.Ping.Targets {
+			if t.Name == "" {
+				errs = append(errs, fmt.Errorf("ping target %d must have a name", i))
+			} else if targetNames[t.Name] {
+				errs = append(errs, fmt.Errorf("duplicate ping target name: %s", t.Name))
+			}
+			targetNames[t.Name] = true
+			if t.Host == "" && t.URL == "" {
+				errs = append(errs, fmt.Errorf("ping target %q must have a host or url", t.Name))
+			}
+			if !validProtocols[strings.ToLower(t.Protocol)] {
+				errs = append(errs, fmt.Errorf("invalid protocol for ping target %q: %s", t.Name, t.Protocol))
+			}
+			for _, code := range t.ExpectStatus {
+				if code < 100 || code > 599 {
+					errs = append(errs, fmt.Errorf("invalid expected status for ping target %q: %d", t.Name, code))
+				}
+			}
+		}
+	}
+
+	if c.Monitoring.Power.BatteryInterval != 0 && c.Monitoring.Power.BatteryInterval < 100*time.Millisecond {
+		errs = append(errs, fmt.Errorf("power battery_interval must be at least 100ms"))
+	}
+
+	sensorIDs := make(map[string]bool)
+	for i, s := range c.Monitoring.Sensors.Sensors {
+		if s.ID == "" {
+			errs = append(errs, fmt.Errorf("sensor %d must have an id", i))
+		} else if sensorIDs[s.ID] {
+			errs = append(errs, fmt.Errorf("duplicate sensor id: %s", s.ID))
+		}
+		sensorIDs[s.ID] = true
+		if s.Min > 0 && s.Max > 0 && s.Min >= s.Max {
+			errs = append(errs, fmt.Errorf("sensor %q min must be below max", s.ID))
+		}
+	}
+
+	if c.Monitoring.Throttling.Enabled {
+		if r := c.Monitoring.Throttling.FrequencyRatio; r <= 0 || r >= 1 {
+			errs = append(errs, fmt.Errorf("throttling frequency_ratio must be between 0 and 1"))
+		}
+		if l := c.Monitoring.Throttling.LoadPercent; l < 0 || l > 100 {
+			errs = append(errs, fmt.Errorf("throttling load_percent must be between 0 and 100"))
+		}
+	}
+
+	if c.Monitoring.Bottleneck.Enabled {
+		if c.Monitoring.Bottleneck.Window <= 0 {
+			errs = append(errs, fmt.Errorf("bottleneck window must be positive"))
+		} else if c.Monitoring.Bottleneck.Window > c.Monitoring.HistoryDuration {
+			errs = append(errs, fmt.Errorf("bottleneck window must not exceed history_duration"))
+		}
+	}
+
+	if leaks := c.Monitoring.LeakDetection; leaks.Enabled {
+		if leaks.SampleInterval < time.Second {
+			errs = append(errs, fmt.Errorf("leak_detection sample_interval must be at least 1s"))
+		}
+		if leaks.MinDuration <= 0 || leaks.MinDuration > leaks.Window {
+			errs = append(errs, fmt.Errorf("leak_detection min_duration must be positive and not exceed window"))
+		}
+		if leaks.MinGrowthMBPerHour <= 0 {
+			errs = append(errs, fmt.Errorf("leak_detection min_growth_mb_per_hour must be positive"))
+		}
+	}
+
+	if c.Monitoring.Wireless.Enabled && c.Monitoring.Wireless.LinkInterval < time.Second {
+		errs = append(errs, fmt.Errorf("wireless link_interval must be at least 1s"))
+	}
+
+	for _, paths := range [][]string{c.Monitoring.Cgroups.Paths, c.Monitoring.Cgroups.Slices} {
+		for _, p := range paths {
+			if p == "" || strings.Contains(p, "..") {
+				errs = append(errs, fmt.Errorf("invalid cgroup path: %q", p))
+			}
+		}
+	}
+
+	if c.Monitoring.GameServer.Enabled {
+		if c.Monitoring.GameServer.Interval < time.Second {
+			errs = append(errs, fmt.Errorf("game_server interval must be at least 1s"))
+		}
+		if c.Monitoring.GameServer.Timeout <= 0 {
+			errs = append(errs, fmt.Errorf("game_server timeout must be positive"))
+		}
+	}
+
+	watchIDs := make(map[string]bool)
+	for i, w := range c.Monitoring.Watchlist {
+		if w.ID == "" {
+			errs = append(errs, fmt.Errorf("watchlist entry %d must have an id", i))
+		} else if watchIDs[w.ID] {
+			errs = append(errs, fmt.Errorf("duplicate watchlist id: %s", w.ID))
+		}
+		watchIDs[w.ID] = true
+		if w.Name == "" && w.Pattern == "" && w.Cmdline == "" {
+			errs = append(errs, fmt.Errorf("watchlist entry %q must set name, pattern or cmdline", w.ID))
+		}
+		if _, err := regexp.Compile(w.Pattern); err != nil {
+			errs = append(errs, fmt.Errorf("invalid watchlist pattern for %q: %w", w.ID, err))
+		}
+		if _, err := regexp.Compile(w.Cmdline); err != nil {
+			errs = append(errs, fmt.Errorf("invalid watchlist cmdline for %q: %w", w.ID, err))
+		}
+	}
 
 	// Validate alert thresholds
 	if c.Alerts.CPUThreshold < 0 || c.Alerts.CPUThreshold > 100 {
@@ -391,9 +920,38 @@ func (c *Config) Validate() []error {
 	if c.Alerts.GPUThreshold < 0 || c.Alerts.GPUThreshold > 100 {
 		errs = append(errs, fmt.Errorf("gpu_threshold must be between 0 and 100"))
 	}
+	if c.Alerts.PacketLossThreshold < 0 || c.Alerts.PacketLossThreshold > 100 {
+		errs = append(errs, fmt.Errorf("packet_loss_threshold must be between 0 and 100"))
+	}
+	if c.Alerts.BatteryLowPercent < 0 || c.Alerts.BatteryLowPercent > 100 {
+		errs = append(errs, fmt.Errorf("battery_low_percent must be between 0 and 100"))
+	}
+	if c.Alerts.WifiSignalDBm > 0 {
+		errs = append(errs, fmt.Errorf("wifi_signal_dbm must not be positive"))
+	}
+	if c.Alerts.CgroupMemoryPercent < 0 || c.Alerts.CgroupMemoryPercent > 100 {
+		errs = append(errs, fmt.Errorf("cgroup_memory_percent must be between 0 and 100"))
+	}
+	if c.Alerts.ThrottlingMinDuration < 0 {
+		errs = append(errs, fmt.Errorf("throttling_min_duration must not be negative"))
+	}
+	if c.Alerts.CertExpiryDays < 0 {
+		errs = append(errs, fmt.Errorf("cert_expiry_days must not be negative"))
+	}
 	if c.Alerts.Cooldown < time.Second {
 		errs = append(errs, fmt.Errorf("cooldown must be at least 1s"))
 	}
+	if a := c.Alerts.Attribution; a.Enabled {
+		if a.Window <= 0 || a.Baseline <= 0 {
+			errs = append(errs, fmt.Errorf("attribution window and baseline must be positive"))
+		}
+		if a.Window+a.Baseline > c.Monitoring.HistoryDuration {
+			errs = append(errs, fmt.Errorf("attribution window and baseline exceed history_duration"))
+		}
+		if a.Count < 1 || a.Count > 10 {
+			errs = append(errs, fmt.Errorf("attribution count must be between 1 and 10"))
+		}
+	}
 
 	// Validate overlay config
 	validPositions := map[string]bool{
@@ -418,5 +976,21 @@ func (c *Config) Validate() []error {
 		errs = append(errs, fmt.Errorf("invalid log level: %s", c.Logging.Level))
 	}
 
+	if c.Benchmark.MaxSamples < 0 {
+		errs = append(errs, fmt.Errorf("benchmark max_samples must not be negative"))
+	}
+
+	if f := c.FlightRecorder; f.Enabled {
+		if f.Before < 0 || f.After < 0 {
+			errs = append(errs, fmt.Errorf("flight_recorder before and after must not be negative"))
+		}
+		if f.Before > c.Monitoring.HistoryDuration {
+			errs = append(errs, fmt.Errorf("flight_recorder before exceeds history_duration, only %v is available", c.Monitoring.HistoryDuration))
+		}
+		if f.MaxFiles < 0 {
+			errs = append(errs, fmt.Errorf("flight_recorder max_files must not be negative"))
+		}
+	}
+
 	return errs
 }
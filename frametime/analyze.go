@@ -0,0 +1,173 @@
+package frametime
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+const (
+	// stutterWindow is the number of preceding frames a frame is compared to.
+	stutterWindow = 20
+	// stutterFactor is how much longer than the recent median a frame must
+	// take to count as a stutter.
+	stutterFactor = 2.0
+	// minStutterMs ignores spikes too short to be noticeable at high frame
+	// rates (e.g. 3 ms after 1.4 ms frames).
+	minStutterMs = 8.0
+)
+
+// Stats are frame-time statistics of a run of frames.
+type Stats struct {
+	// Frames is the number of frames.
+	Frames int `json:"frames"`
+	// DurationSec is the time covered by the frames in seconds: from the
+	// start of the first frame to the last one, or the summed frame time
+	// if that is longer (e.g. without timestamps).
+	DurationSec float64 `json:"duration_sec"`
+	// AvgFPS is the number of frames divided by the duration.
+	AvgFPS float64 `json:"avg_fps"`
+	// Low1FPS is the frame rate of the slowest 1% of frames (their mean frame time).
+	Low1FPS float64 `json:"low_1_fps"`
+	// Low01FPS is the frame rate of the slowest 0.1% of frames.
+	Low01FPS float64 `json:"low_0_1_fps"`
+	// AvgFrameTimeMs is the mean frame time.
+	AvgFrameTimeMs float64 `json:"avg_frame_time_ms"`
+	// MinFrameTimeMs is the shortest frame time.
+	MinFrameTimeMs float64 `json:"min_frame_time_ms"`
+	// MaxFrameTimeMs is the longest frame time.
+	MaxFrameTimeMs float64 `json:"max_frame_time_ms"`
+	// P50FrameTimeMs is the median frame time.
+	P50FrameTimeMs float64 `json:"p50_frame_time_ms"`
+	// P90FrameTimeMs is the 90th percentile frame time.
+	P90FrameTimeMs float64 `json:"p90_frame_time_ms"`
+	// P95FrameTimeMs is the 95th percentile frame time.
+	P95FrameTimeMs float64 `json:"p95_frame_time_ms"`
+	// P99FrameTimeMs is the 99th percentile frame time.
+	P99FrameTimeMs float64 `json:"p99_frame_time_ms"`
+	// P999FrameTimeMs is the 99.9th percentile frame time.
+	P999FrameTimeMs float64 `json:"p99_9_frame_time_ms"`
+	// Stutters is the number of frames taking at least twice (and 8 ms)
+	// longer than the median of the preceding frames.
+	Stutters int `json:"stutters"`
+	// StuttersPerMin is the stutter rate.
+	StuttersPerMin float64 `json:"stutters_per_min"`
+}
+
+// String returns a one-line summary.
+func (s Stats) String() string {
+	return fmt.Sprintf("%d frames, avg %.1f FPS, 1%% low %.1f FPS, 0.1%% low %.1f FPS, p99 %.2f ms, max %.2f ms, %d stutters",
+		s.Frames, s.AvgFPS, s.Low1FPS, s.Low01FPS, s.P99FrameTimeMs, s.MaxFrameTimeMs, s.Stutters)
+}
+
+// Analyze computes the statistics of the given frames.
+func Analyze(frames []Frame) Stats {
+	var stats Stats
+	if len(frames) == 0 {
+		return stats
+	}
+
+	times := make([]float64, len(frames))
+	var total float64
+	for i, f := range frames {
+		times[i] = f.FrameTimeMs
+		total += f.FrameTimeMs
+	}
+
+	stats.Frames = len(frames)
+	stats.DurationSec = coveredMs(frames, total) / 1000
+	stats.AvgFrameTimeMs = total / float64(len(frames))
+	stats.AvgFPS = fps(stats.AvgFrameTimeMs)
+	stats.Stutters = countStutters(times)
+	if stats.DurationSec > 0 {
+		stats.StuttersPerMin = float64(stats.Stutters) / stats.DurationSec * 60
+	}
+
+	sorted := append([]float64(nil), times...)
+	sort.Float64s(sorted)
+	stats.MinFrameTimeMs = sorted[0]
+	stats.MaxFrameTimeMs = sorted[len(sorted)-1]
+	stats.P50FrameTimeMs = percentile(sorted, 50)
+	stats.P90FrameTimeMs = percentile(sorted, 90)
+	stats.P95FrameTimeMs = percentile(sorted, 95)
+	stats.P99FrameTimeMs = percentile(sorted, 99)
+	stats.P999FrameTimeMs = percentile(sorted, 99.9)
+	stats.Low1FPS = fps(worstMean(sorted, 0.01))
+	stats.Low01FPS = fps(worstMean(sorted, 0.001))
+
+	return stats
+}
+
+// coveredMs returns the time covered by the frames in milliseconds. Sampled
+// logs (MangoHud with a log interval) list only some frames, so the sum of
+// their frame times is far shorter than the time between their offsets.
+func coveredMs(frames []Frame, totalMs float64) float64 {
+	first, last := frames[0], frames[len(frames)-1]
+	span := float64(last.Offset-first.Offset)/float64(time.Millisecond) + first.FrameTimeMs
+	return max(totalMs, span)
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	// The epsilon keeps e.g. 99.9% of 1000 at rank 999 despite rounding
+	rank := int(math.Ceil(p/100*float64(len(sorted)) - 1e-9))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
+
+// worstMean returns the mean of the largest fraction of sorted values (at
+// least one value).
+func worstMean(sorted []float64, fraction float64) float64 {
+	n := int(float64(len(sorted)) * fraction)
+	if n < 1 {
+		n = 1
+	}
+	var sum float64
+	for _, v := range sorted[len(sorted)-n:] {
+		sum += v
+	}
+	return sum / float64(n)
+}
+
+// fps converts a frame time in milliseconds to frames per second.
+func fps(frameTimeMs float64) float64 {
+	if frameTimeMs <= 0 {
+		return 0
+	}
+	return 1000 / frameTimeMs
+}
+
+// countStutters counts frames that take stutterFactor times longer than
+// the median of the preceding stutterWindow frames.
+func countStutters(times []float64) int {
+	count := 0
+	forEachStutter(times, func(int) { count++ })
+	return count
+}
+
+// forEachStutter calls fn with the index of every stutter frame.
+func forEachStutter(times []float64, fn func(i int)) {
+	window := make([]float64, 0, stutterWindow)
+	for i, t := range times {
+		// Too little history for a stable median
+		if i < stutterWindow/2 {
+			continue
+		}
+		start := i - stutterWindow
+		if start < 0 {
+			start = 0
+		}
+		window = append(window[:0], times[start:i]...)
+		sort.Float64s(window)
+		median := window[len(window)/2]
+		if t >= median*stutterFactor && t-median >= minStutterMs {
+			fn(i)
+		}
+	}
+}
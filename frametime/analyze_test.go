@@ -0,0 +1,60 @@
+package frametime
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// steadyFrames returns n frames of the given frame time.
+func steadyFrames(n int, frameTimeMs float64) []Frame {
+	frames := make([]Frame, n)
+	var offset time.Duration
+	for i := range frames {
+		offset += time.Duration(frameTimeMs * float64(time.Millisecond))
+		frames[i] = Frame{Offset: offset, FrameTimeMs: frameTimeMs}
+	}
+	return frames
+}
+
+func TestAnalyze(t *testing.T) {
+	// 990 frames at 10 ms, 9 at 50 ms and one at 100 ms
+	frames := steadyFrames(1000, 10)
+	for i := 100; i < 1000; i += 100 {
+		frames[i].FrameTimeMs = 50
+	}
+	frames[999].FrameTimeMs = 100
+
+	stats := Analyze(frames)
+	if stats.Frames != 1000 {
+		t.Fatalf("Expected 1000 frames, got %d", stats.Frames)
+	}
+	// 990*10 + 9*50 + 100 = 10450 ms
+	if math.Abs(stats.AvgFPS-1000/10.45) > 0.01 {
+		t.Errorf("Unexpected average FPS: %.2f", stats.AvgFPS)
+	}
+	// The slowest 1% (10 frames) average 55 ms, the slowest 0.1% is the 100 ms frame
+	if math.Abs(stats.Low1FPS-1000.0/55) > 0.01 || stats.Low01FPS != 10 {
+		t.Errorf("Unexpected lows: %.2f / %.2f", stats.Low1FPS, stats.Low01FPS)
+	}
+	if stats.P50FrameTimeMs != 10 || stats.P99FrameTimeMs != 10 || stats.P999FrameTimeMs != 50 || stats.MaxFrameTimeMs != 100 {
+		t.Errorf("Unexpected percentiles: %+v", stats)
+	}
+	if stats.Stutters != 10 {
+		t.Errorf("Expected 10 stutters, got %d", stats.Stutters)
+	}
+
+	if empty := Analyze(nil); empty.Frames != 0 || empty.AvgFPS != 0 {
+		t.Errorf("Expected empty stats, got %+v", empty)
+	}
+}
+
+func TestStutterThreshold(t *testing.T) {
+	// At 500 FPS a 4 ms frame doubles the frame time but is not noticeable
+	frames := steadyFrames(100, 2)
+	frames[50].FrameTimeMs = 4
+	frames[80].FrameTimeMs = 12
+	if stutters := Analyze(frames).Stutters; stutters != 1 {
+		t.Errorf("Expected 1 stutter, got %d", stutters)
+	}
+}
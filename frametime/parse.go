@@ -0,0 +1,264 @@
+// Package frametime imports and analyzes frame-time logs captured by
+// PresentMon and MangoHud, and aligns them with the collected metrics.
+package frametime
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format identifies the tool that wrote a frame-time log.
+type Format string
+
+const (
+	// FormatPresentMon is a PresentMon CSV capture.
+	FormatPresentMon Format = "presentmon"
+	// FormatMangoHud is a MangoHud CSV log.
+	FormatMangoHud Format = "mangohud"
+)
+
+// Frame is a single presented frame.
+type Frame struct {
+	// Offset is the time since the start of the capture.
+	Offset time.Duration `json:"offset"`
+	// FrameTimeMs is the time since the previous frame in milliseconds.
+	FrameTimeMs float64 `json:"frame_time_ms"`
+}
+
+// Log is an imported frame-time log.
+type Log struct {
+	// Format is the tool that wrote the log.
+	Format Format `json:"format"`
+	// Application is the captured application (if recorded).
+	Application string `json:"application,omitempty"`
+	// Start is the wall-clock time of the start of the capture (zero if unknown).
+	Start time.Time `json:"start"`
+	// Frames are the frames in capture order.
+	Frames []Frame `json:"-"`
+}
+
+// Duration returns the time covered by the log.
+func (l *Log) Duration() time.Duration {
+	if len(l.Frames) == 0 {
+		return 0
+	}
+	last := l.Frames[len(l.Frames)-1]
+	return last.Offset
+}
+
+// FrameTime returns the wall-clock time of a frame.
+func (l *Log) FrameTime(f Frame) time.Time {
+	return l.Start.Add(f.Offset)
+}
+
+// ErrUnknownFormat is returned for CSV files that are neither PresentMon nor
+// MangoHud logs.
+var ErrUnknownFormat = errors.New("not a PresentMon or MangoHud frame-time log")
+
+// mangoHudFileTime matches the timestamp MangoHud puts in log file names,
+// e.g. "cs2_2024-03-01_20-15-42.csv".
+var mangoHudFileTime = regexp.MustCompile(`(\d{4}-\d{2}-\d{2}_\d{2}-\d{2}-\d{2})`)
+
+// Load reads a frame-time log file. The capture start is taken from the
+// MangoHud file name when present, otherwise it is derived from the file
+// modification time (the end of the capture).
+func Load(path string) (*Log, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	log, err := Parse(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	if m := mangoHudFileTime.FindString(filepath.Base(path)); m != "" && log.Format == FormatMangoHud {
+		if start, err := time.ParseInLocation("2006-01-02_15-04-05", m, time.Local); err == nil {
+			log.Start = start
+			return log, nil
+		}
+	}
+	if info, err := file.Stat(); err == nil {
+		log.Start = info.ModTime().Add(-log.Duration())
+	}
+	return log, nil
+}
+
+// Parse reads a PresentMon or MangoHud CSV log. The capture start is left
+// zero; callers set Log.Start to align the frames with metrics.
+func Parse(r io.Reader) (*Log, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	// Both tools may write metadata lines before the column header
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil, ErrUnknownFormat
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		columns := columnIndex(record)
+		if _, ok := columns["application"]; ok {
+			if _, ok := firstColumn(columns, "msbetweenpresents", "frametime"); ok {
+				return parsePresentMon(reader, columns)
+			}
+		}
+		if _, ok := columns["frametime"]; ok {
+			if _, ok := columns["fps"]; ok {
+				return parseMangoHud(reader, columns)
+			}
+		}
+	}
+}
+
+// parsePresentMon reads PresentMon rows. A capture may contain several
+// processes; the one with the most frames is kept.
+func parsePresentMon(reader *csv.Reader, columns map[string]int) (*Log, error) {
+	app := columns["application"]
+	frameCol, _ := firstColumn(columns, "msbetweenpresents", "frametime")
+	// TimeInSeconds (1.x) is in seconds, CPUStartTime (2.x) in milliseconds
+	timeCol, timeScale := -1, 0.0
+	if col, ok := columns["timeinseconds"]; ok {
+		timeCol, timeScale = col, float64(time.Second)
+	} else if col, ok := columns["cpustarttime"]; ok {
+		timeCol, timeScale = col, float64(time.Millisecond)
+	}
+
+	byApp := make(map[string][]Frame)
+	var order []string
+	var elapsed time.Duration
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		frameTime, ok := floatField(record, frameCol)
+		if !ok || frameTime <= 0 {
+			continue
+		}
+		name := field(record, app)
+
+		offset := elapsed + time.Duration(frameTime*float64(time.Millisecond))
+		if t, ok := floatField(record, timeCol); ok {
+			offset = time.Duration(t * timeScale)
+		}
+		elapsed = offset
+
+		if _, seen := byApp[name]; !seen {
+			order = append(order, name)
+		}
+		byApp[name] = append(byApp[name], Frame{Offset: offset, FrameTimeMs: frameTime})
+	}
+
+	log := &Log{Format: FormatPresentMon}
+	for _, name := range order {
+		if len(byApp[name]) > len(log.Frames) {
+			log.Application = name
+			log.Frames = byApp[name]
+		}
+	}
+	if len(log.Frames) == 0 {
+		return nil, errors.New("no frames in PresentMon log")
+	}
+	return log, nil
+}
+
+// parseMangoHud reads MangoHud rows. Each row is one logged frame (every
+// frame with log_interval=0, otherwise one sample per interval).
+func parseMangoHud(reader *csv.Reader, columns map[string]int) (*Log, error) {
+	frameCol := columns["frametime"]
+	elapsedCol, hasElapsed := columns["elapsed"]
+
+	log := &Log{Format: FormatMangoHud}
+	var elapsed time.Duration
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		frameTime, ok := floatField(record, frameCol)
+		if !ok || frameTime <= 0 {
+			continue
+		}
+
+		// elapsed is in nanoseconds since the start of the log
+		offset := elapsed + time.Duration(frameTime*float64(time.Millisecond))
+		if hasElapsed {
+			if ns, ok := floatField(record, elapsedCol); ok {
+				offset = time.Duration(ns)
+			}
+		}
+		elapsed = offset
+
+		log.Frames = append(log.Frames, Frame{Offset: offset, FrameTimeMs: frameTime})
+	}
+
+	if len(log.Frames) == 0 {
+		return nil, errors.New("no frames in MangoHud log")
+	}
+	return log, nil
+}
+
+// columnIndex maps lower-cased column names to their index.
+func columnIndex(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	return columns
+}
+
+// firstColumn returns the index of the first of the given columns present.
+func firstColumn(columns map[string]int, names ...string) (int, bool) {
+	for _, name := range names {
+		if col, ok := columns[name]; ok {
+			return col, true
+		}
+	}
+	return -1, false
+}
+
+// field returns a trimmed field, or "" when the row is too short.
+func field(record []string, col int) string {
+	if col < 0 || col >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[col])
+}
+
+// floatField parses a numeric field.
+func floatField(record []string, col int) (float64, bool) {
+	s := field(record, col)
+	if s == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
@@ -0,0 +1,102 @@
+package frametime
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadPresentMon(t *testing.T) {
+	log, err := Load("testdata/presentmon.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The process with the most frames is kept
+	if log.Format != FormatPresentMon || log.Application != "cs2.exe" {
+		t.Fatalf("Unexpected log: %s %q", log.Format, log.Application)
+	}
+	if len(log.Frames) != 5 {
+		t.Fatalf("Expected 5 frames, got %d", len(log.Frames))
+	}
+	if f := log.Frames[3]; f.Offset != 70*time.Millisecond || f.FrameTimeMs != 40 {
+		t.Errorf("Unexpected frame: %+v", f)
+	}
+	if log.Duration() != 80*time.Millisecond {
+		t.Errorf("Expected 80 ms duration, got %v", log.Duration())
+	}
+	if log.Start.IsZero() {
+		t.Error("Expected the start to be derived from the file time")
+	}
+}
+
+func TestLoadMangoHud(t *testing.T) {
+	log, err := Load("testdata/cs2_2024-03-01_20-15-42.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if log.Format != FormatMangoHud || len(log.Frames) != 3 {
+		t.Fatalf("Unexpected log: %s with %d frames", log.Format, len(log.Frames))
+	}
+	want := time.Date(2024, 3, 1, 20, 15, 42, 0, time.Local)
+	if !log.Start.Equal(want) {
+		t.Errorf("Expected start from the file name %v, got %v", want, log.Start)
+	}
+	if f := log.Frames[2]; f.Offset != 40*time.Millisecond || f.FrameTimeMs != 20 {
+		t.Errorf("Unexpected frame: %+v", f)
+	}
+}
+
+func TestLoadMangoHudSampled(t *testing.T) {
+	// One row every 100 ms (log_interval=100), one of them a 40 ms stutter
+	log, err := Load("testdata/sampled_2024-03-01_21-00-00.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Frames) != 30 || log.Duration() != 3*time.Second {
+		t.Fatalf("Unexpected log: %d frames over %v", len(log.Frames), log.Duration())
+	}
+
+	stats := Analyze(log.Frames)
+	// From the start of the first sampled frame to the last
+	if want := 2.907; math.Abs(stats.DurationSec-want) > 1e-9 {
+		t.Errorf("Expected %.3f s, got %.3f s", want, stats.DurationSec)
+	}
+	if stats.Stutters != 1 || math.Abs(stats.StuttersPerMin-60/2.907) > 1e-6 {
+		t.Errorf("Expected 1 stutter in 2.9 s, got %d (%.1f/min)", stats.Stutters, stats.StuttersPerMin)
+	}
+}
+
+func TestParsePresentMon2(t *testing.T) {
+	input := `Application,ProcessID,SwapChainAddress,PresentRuntime,CPUStartTime,FrameTime,MsBetweenDisplayChange
+game.exe,100,0x1,DXGI,1000.0,8.0,8.0
+game.exe,100,0x1,DXGI,1008.0,8.5,8.5
+`
+	log, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Frames) != 2 || log.Frames[1].Offset != 1008*time.Millisecond || log.Frames[1].FrameTimeMs != 8.5 {
+		t.Errorf("Unexpected frames: %+v", log.Frames)
+	}
+}
+
+func TestParseMangoHudWithoutElapsed(t *testing.T) {
+	log, err := Parse(strings.NewReader("fps,frametime\n100,10\n50,20\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(log.Frames) != 2 || log.Frames[1].Offset != 30*time.Millisecond {
+		t.Errorf("Expected offsets from summed frame times, got %+v", log.Frames)
+	}
+}
+
+func TestParseUnknown(t *testing.T) {
+	_, err := Parse(strings.NewReader("Timestamp,CPU%\n2024-01-01 00:00:00,10\n"))
+	if !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("Expected ErrUnknownFormat, got %v", err)
+	}
+}
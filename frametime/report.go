@@ -0,0 +1,250 @@
+package frametime
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// Interval is the frame-time statistics of the frames presented during one
+// metrics sample, next to the system metrics of that sample.
+type Interval struct {
+	// Timestamp is the metrics sample time (the end of the interval).
+	Timestamp time.Time `json:"timestamp"`
+	// Frames is the number of frames presented in the interval.
+	Frames int `json:"frames"`
+	// AvgFPS is the average frame rate in the interval.
+	AvgFPS float64 `json:"avg_fps"`
+	// MaxFrameTimeMs is the longest frame time in the interval.
+	MaxFrameTimeMs float64 `json:"max_frame_time_ms"`
+	// Stutters is the number of stutters in the interval.
+	Stutters int `json:"stutters"`
+	// CPUPercent is the CPU usage.
+	CPUPercent float64 `json:"cpu_percent"`
+	// CPUTempC is the CPU temperature.
+	CPUTempC float64 `json:"cpu_temp_c"`
+	// GPUPercent is the GPU usage.
+	GPUPercent float64 `json:"gpu_percent"`
+	// GPUTempC is the GPU temperature.
+	GPUTempC uint32 `json:"gpu_temp_c"`
+	// VRAMUsedMB is the used video memory.
+	VRAMUsedMB uint64 `json:"vram_used_mb"`
+	// RAMPercent is the RAM usage.
+	RAMPercent float64 `json:"ram_percent"`
+}
+
+// SystemSummary summarizes the metrics samples within a capture.
+type SystemSummary struct {
+	// Samples is the number of metrics samples within the capture.
+	Samples int `json:"samples"`
+	// AvgCPUPercent is the average CPU usage.
+	AvgCPUPercent float64 `json:"avg_cpu_percent"`
+	// MaxCPUPercent is the peak CPU usage.
+	MaxCPUPercent float64 `json:"max_cpu_percent"`
+	// AvgGPUPercent is the average GPU usage.
+	AvgGPUPercent float64 `json:"avg_gpu_percent"`
+	// MaxGPUPercent is the peak GPU usage.
+	MaxGPUPercent float64 `json:"max_gpu_percent"`
+	// MaxCPUTempC is the peak CPU temperature.
+	MaxCPUTempC float64 `json:"max_cpu_temp_c"`
+	// MaxGPUTempC is the peak GPU temperature.
+	MaxGPUTempC uint32 `json:"max_gpu_temp_c"`
+	// AvgRAMPercent is the average RAM usage.
+	AvgRAMPercent float64 `json:"avg_ram_percent"`
+	// MaxVRAMUsedMB is the peak video memory usage.
+	MaxVRAMUsedMB uint64 `json:"max_vram_used_mb"`
+}
+
+// Report combines a frame-time log with the metrics of the same period.
+type Report struct {
+	// GeneratedAt is when the report was created.
+	GeneratedAt time.Time `json:"generated_at"`
+	// Format is the tool that wrote the frame-time log.
+	Format Format `json:"format"`
+	// Application is the captured application.
+	Application string `json:"application,omitempty"`
+	// Start is the start of the capture.
+	Start time.Time `json:"start"`
+	// End is the end of the capture.
+	End time.Time `json:"end"`
+	// Summary is the frame-time statistics of the whole capture.
+	Summary Stats `json:"summary"`
+	// System summarizes the metrics within the capture (nil without metrics).
+	System *SystemSummary `json:"system,omitempty"`
+	// Intervals are the per-sample statistics aligned by timestamp.
+	Intervals []Interval `json:"intervals,omitempty"`
+}
+
+// BuildReport analyzes the log and aligns its frames with the metrics
+// samples taken during the capture. Each sample covers the frames presented
+// since the previous sample. Metrics may come from the history ring buffer
+// or from a metrics CSV file.
+func BuildReport(log *Log, metrics []*models.Metrics) *Report {
+	report := &Report{
+		GeneratedAt: time.Now(),
+		Format:      log.Format,
+		Application: log.Application,
+		Start:       log.Start,
+		End:         log.Start.Add(log.Duration()),
+		Summary:     Analyze(log.Frames),
+	}
+
+	samples := samplesWithin(metrics, report.Start, report.End)
+	if len(samples) == 0 {
+		return report
+	}
+
+	report.System = summarizeSystem(samples)
+	stutters := stutterFlags(log.Frames)
+
+	next := 0
+	for i, m := range samples {
+		from := m.Timestamp.Add(-sampleInterval(m))
+		if i > 0 {
+			from = samples[i-1].Timestamp
+		}
+
+		interval := Interval{
+			Timestamp:  m.Timestamp,
+			CPUPercent: m.CPU.UsagePercent,
+			CPUTempC:   m.CPU.Temperature,
+			GPUPercent: m.GPU.UsagePercent,
+			GPUTempC:   m.GPU.TemperatureC,
+			VRAMUsedMB: m.GPU.VRAMUsedMB,
+			RAMPercent: m.Memory.UsedPercent,
+		}
+
+		// Frames are in time order; skip those before the interval
+		var total float64
+		for next < len(log.Frames) && !log.FrameTime(log.Frames[next]).After(from) {
+			next++
+		}
+		for next < len(log.Frames) && !log.FrameTime(log.Frames[next]).After(m.Timestamp) {
+			f := log.Frames[next]
+			interval.Frames++
+			total += f.FrameTimeMs
+			if f.FrameTimeMs > interval.MaxFrameTimeMs {
+				interval.MaxFrameTimeMs = f.FrameTimeMs
+			}
+			if stutters[next] {
+				interval.Stutters++
+			}
+			next++
+		}
+		if total > 0 {
+			interval.AvgFPS = float64(interval.Frames) * 1000 / total
+		}
+
+		report.Intervals = append(report.Intervals, interval)
+	}
+
+	return report
+}
+
+// samplesWithin returns the samples taken during [start, end], plus the
+// first sample after end, which covers the last frames. Samples are sorted
+// by timestamp.
+func samplesWithin(metrics []*models.Metrics, start, end time.Time) []*models.Metrics {
+	var samples []*models.Metrics
+	for _, m := range metrics {
+		if m != nil && m.Timestamp.After(start) {
+			samples = append(samples, m)
+		}
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].Timestamp.Before(samples[j].Timestamp)
+	})
+
+	for i, m := range samples {
+		if m.Timestamp.After(end) {
+			return samples[:i+1]
+		}
+	}
+	return samples
+}
+
+// sampleInterval returns the time covered by a sample without a predecessor.
+func sampleInterval(m *models.Metrics) time.Duration {
+	if m.IntervalMs > 0 {
+		return time.Duration(m.IntervalMs * float64(time.Millisecond))
+	}
+	return time.Second
+}
+
+// summarizeSystem computes averages and peaks of the samples.
+func summarizeSystem(samples []*models.Metrics) *SystemSummary {
+	s := &SystemSummary{Samples: len(samples)}
+	for _, m := range samples {
+		s.AvgCPUPercent += m.CPU.UsagePercent
+		s.AvgGPUPercent += m.GPU.UsagePercent
+		s.AvgRAMPercent += m.Memory.UsedPercent
+		s.MaxCPUPercent = max(s.MaxCPUPercent, m.CPU.UsagePercent)
+		s.MaxGPUPercent = max(s.MaxGPUPercent, m.GPU.UsagePercent)
+		s.MaxCPUTempC = max(s.MaxCPUTempC, m.CPU.Temperature)
+		s.MaxGPUTempC = max(s.MaxGPUTempC, m.GPU.TemperatureC)
+		s.MaxVRAMUsedMB = max(s.MaxVRAMUsedMB, m.GPU.VRAMUsedMB)
+	}
+	n := float64(len(samples))
+	s.AvgCPUPercent /= n
+	s.AvgGPUPercent /= n
+	s.AvgRAMPercent /= n
+	return s
+}
+
+// stutterFlags marks the frames counted as stutters by Analyze.
+func stutterFlags(frames []Frame) []bool {
+	times := make([]float64, len(frames))
+	for i, f := range frames {
+		times[i] = f.FrameTimeMs
+	}
+	flags := make([]bool, len(frames))
+	forEachStutter(times, func(i int) { flags[i] = true })
+	return flags
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// WriteCSV writes the aligned intervals as CSV, one row per metrics sample.
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{
+		"Timestamp", "Frames", "Avg_FPS", "Max_Frame_ms", "Stutters",
+		"CPU%", "CPU_Temp", "GPU%", "GPU_Temp", "GPU_VRAM_MB", "RAM%",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, in := range r.Intervals {
+		record := []string{
+			in.Timestamp.Format("2006-01-02 15:04:05.000"),
+			fmt.Sprintf("%d", in.Frames),
+			fmt.Sprintf("%.1f", in.AvgFPS),
+			fmt.Sprintf("%.2f", in.MaxFrameTimeMs),
+			fmt.Sprintf("%d", in.Stutters),
+			fmt.Sprintf("%.1f", in.CPUPercent),
+			fmt.Sprintf("%.1f", in.CPUTempC),
+			fmt.Sprintf("%.1f", in.GPUPercent),
+			fmt.Sprintf("%d", in.GPUTempC),
+			fmt.Sprintf("%d", in.VRAMUsedMB),
+			fmt.Sprintf("%.1f", in.RAMPercent),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
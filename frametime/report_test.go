@@ -0,0 +1,81 @@
+package frametime
+
+import (
+	"bytes"
+	"encoding/csv"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+func TestBuildReport(t *testing.T) {
+	start := time.Date(2024, 3, 1, 20, 0, 0, 0, time.Local)
+	// Two seconds at 100 FPS, then 0.9 s at 66 FPS with a stutter
+	frames := append(steadyFrames(200, 10), steadyFrames(60, 15)...)
+	for i := 200; i < len(frames); i++ {
+		frames[i].Offset += 2 * time.Second
+	}
+	frames[230].FrameTimeMs = 80
+	log := &Log{Format: FormatMangoHud, Start: start, Frames: frames}
+
+	var metrics []*models.Metrics
+	for i := -1; i <= 4; i++ {
+		m := &models.Metrics{Timestamp: start.Add(time.Duration(i) * time.Second), IntervalMs: 1000}
+		m.CPU.UsagePercent = float64(10 * (i + 2))
+		m.GPU.UsagePercent = 90
+		m.GPU.TemperatureC = uint32(70 + i)
+		metrics = append(metrics, m)
+	}
+	// Out-of-order input is sorted
+	metrics[2], metrics[3] = metrics[3], metrics[2]
+
+	report := BuildReport(log, metrics)
+	if report.Summary.Frames != 260 || report.Summary.Stutters != 1 {
+		t.Fatalf("Unexpected summary: %+v", report.Summary)
+	}
+	if !report.End.Equal(start.Add(2900 * time.Millisecond)) {
+		t.Errorf("Expected the capture to end after 2.9 s, got %v", report.End.Sub(start))
+	}
+
+	// Samples at 1, 2 and 3 s cover the capture, 0 s and 4 s are outside
+	if len(report.Intervals) != 3 {
+		t.Fatalf("Expected 3 intervals, got %d", len(report.Intervals))
+	}
+	first, last := report.Intervals[0], report.Intervals[2]
+	if first.Frames != 100 || math.Abs(first.AvgFPS-100) > 0.01 || first.CPUPercent != 30 {
+		t.Errorf("Unexpected first interval: %+v", first)
+	}
+	if last.Frames != 60 || last.Stutters != 1 || last.MaxFrameTimeMs != 80 {
+		t.Errorf("Unexpected last interval: %+v", last)
+	}
+
+	if sys := report.System; sys == nil || sys.Samples != 3 || sys.MaxCPUPercent != 50 || sys.MaxGPUTempC != 73 || sys.AvgGPUPercent != 90 {
+		t.Errorf("Unexpected system summary: %+v", report.System)
+	}
+
+	var buf bytes.Buffer
+	if err := report.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 || rows[1][1] != "100" || rows[3][4] != "1" {
+		t.Errorf("Unexpected CSV: %v", rows)
+	}
+}
+
+func TestBuildReportWithoutMetrics(t *testing.T) {
+	log := &Log{Format: FormatPresentMon, Start: time.Now(), Frames: steadyFrames(10, 16)}
+
+	report := BuildReport(log, nil)
+	if report.System != nil || report.Intervals != nil {
+		t.Errorf("Expected no system data, got %+v", report)
+	}
+	if report.Summary.Frames != 10 {
+		t.Errorf("Expected the frame summary, got %+v", report.Summary)
+	}
+}
@@ -18,6 +18,8 @@ const (
 	HotkeyShowWindow HotkeyID = iota + 1
 	HotkeyToggleOverlay
 	HotkeyMoveOverlay
+	HotkeyBenchmark
+	HotkeyFlightRecord
 )
 
 // HotkeyHandler is a function that handles a hotkey press.
@@ -3,10 +3,13 @@ package logger
 
 import (
 	"encoding/csv"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -153,6 +156,12 @@ func (l *Logger) initCSV(path string) error {
 			"Disk_Write_MBps",
 			"Net_Download_KBps",
 			"Net_Upload_KBps",
+			// Added later; kept last so files started by older versions
+			// keep their columns in place
+			"CPU_Package_W",
+			"DRAM_W",
+			"GPU_Power_W",
+			"Est_Power_W",
 		}
 		if err := l.csvWriter.Write(header); err != nil {
 			return err
@@ -163,6 +172,10 @@ func (l *Logger) initCSV(path string) error {
 	return nil
 }
 
+// csvTimestampFormat keeps milliseconds so that sub-second and irregular
+// (adaptive) samples keep their actual timestamps.
+const csvTimestampFormat = "2006-01-02 15:04:05.000"
+
 // LogMetrics writes metrics to the CSV file.
 func (l *Logger) LogMetrics(m *models.Metrics) {
 	if l.csvWriter == nil || l.csvFile == nil {
@@ -173,7 +186,7 @@ func (l *Logger) LogMetrics(m *models.Metrics) {
 	defer l.csvMu.Unlock()
 
 	record := []string{
-		m.Timestamp.Format("2006-01-02 15:04:05"),
+		m.Timestamp.Format(csvTimestampFormat),
 		fmt.Sprintf("%.1f", m.CPU.UsagePercent),
 		fmt.Sprintf("%.1f", m.CPU.Temperature),
 		fmt.Sprintf("%d", m.Memory.UsedMB),
@@ -188,6 +201,10 @@ func (l *Logger) LogMetrics(m *models.Metrics) {
 		fmt.Sprintf("%.2f", m.Disk.WriteMBps),
 		fmt.Sprintf("%.2f", m.Network.DownloadKBps),
 		fmt.Sprintf("%.2f", m.Network.UploadKBps),
+		fmt.Sprintf("%.1f", m.CPU.PackagePowerWatts),
+		fmt.Sprintf("%.1f", m.CPU.DRAMPowerWatts),
+		fmt.Sprintf("%.1f", m.GPU.PowerWatts),
+		fmt.Sprintf("%.1f", m.EstimatedPowerWatts()),
 	}
 
 	if err := l.csvWriter.Write(record); err != nil {
@@ -246,7 +263,31 @@ func (l *Logger) ExportMetricsCSV(path string, metrics []*models.Metrics) error
 		"Disk_Write_MBps",
 		"Net_Download_KBps",
 		"Net_Upload_KBps",
+		"Game_Server_ms",
+		"CPU_Package_W",
+		"DRAM_W",
+		"GPU_Power_W",
+		"Est_Power_W",
+		"Disk_Queue",
+		"Hard_Faults_per_s",
+		"Bottleneck",
+		"Bottleneck_Confidence",
+		"Interval_ms",
+		"Event",
+	}
+
+	// Watched processes get a CPU and RAM column each
+	watchIDs := watchedProcessIDs(metrics)
+	for _, id := range watchIDs {
+		header = append(header, "Watch_"+id+"_CPU%", "Watch_"+id+"_RAM_MB")
 	}
+
+	// Ping targets get latency, jitter and loss columns each
+	pingTargets := pingTargetNames(metrics)
+	for _, name := range pingTargets {
+		header = append(header, "Ping_"+name+"_ms", "Ping_"+name+"_Jitter_ms", "Ping_"+name+"_Loss%")
+	}
+
 	if err := writer.Write(header); err != nil {
 		return err
 	}
@@ -254,7 +295,7 @@ func (l *Logger) ExportMetricsCSV(path string, metrics []*models.Metrics) error
 	// Write records
 	for _, m := range metrics {
 		record := []string{
-			m.Timestamp.Format("2006-01-02 15:04:05"),
+			m.Timestamp.Format(csvTimestampFormat),
 			fmt.Sprintf("%.1f", m.CPU.UsagePercent),
 			fmt.Sprintf("%.1f", m.CPU.Temperature),
 			fmt.Sprintf("%d", m.Memory.UsedMB),
@@ -269,6 +310,219 @@ func (l *Logger) ExportMetricsCSV(path string, metrics []*models.Metrics) error
 			fmt.Sprintf("%.2f", m.Disk.WriteMBps),
 			fmt.Sprintf("%.2f", m.Network.DownloadKBps),
 			fmt.Sprintf("%.2f", m.Network.UploadKBps),
+			fmt.Sprintf("%.1f", m.Network.GameServerMs),
+			fmt.Sprintf("%.1f", m.CPU.PackagePowerWatts),
+			fmt.Sprintf("%.1f", m.CPU.DRAMPowerWatts),
+			fmt.Sprintf("%.1f", m.GPU.PowerWatts),
+			fmt.Sprintf("%.1f", m.EstimatedPowerWatts()),
+			fmt.Sprintf("%.2f", m.Disk.QueueLength),
+			fmt.Sprintf("%.0f", m.Memory.HardFaultsPerSec),
+			string(m.Bottleneck.Class),
+			fmt.Sprintf("%.2f", m.Bottleneck.Confidence),
+			fmt.Sprintf("%.0f", m.IntervalMs),
+			collectorEventName(m.Event),
+		}
+		for _, id := range watchIDs {
+			cpu, ram := "", ""
+			for _, w := range m.WatchedProcesses {
+				if w.ID == id && w.Running {
+					cpu = fmt.Sprintf("%.1f", w.CPUPercent)
+					ram = fmt.Sprintf("%d", w.MemoryMB)
+					break
+				}
+			}
+			record = append(record, cpu, ram)
+		}
+		for _, name := range pingTargets {
+			latency, jitter, loss := "", "", ""
+			for _, p := range m.Network.PingTargets {
+				if p.Name == name {
+					latency = fmt.Sprintf("%.1f", p.P50Ms)
+					jitter = fmt.Sprintf("%.1f", p.JitterMs)
+					loss = fmt.Sprintf("%.1f", p.LossPercent)
+					break
+				}
+			}
+			record = append(record, latency, jitter, loss)
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadMetricsCSV reads metrics written by LogMetrics or ExportMetricsCSV.
+// Only the system-wide columns are restored; unknown columns are ignored.
+func ReadMetricsCSV(path string) ([]*models.Metrics, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	if _, ok := columns["Timestamp"]; !ok {
+		return nil, fmt.Errorf("%s: missing Timestamp column", filepath.Base(path))
+	}
+
+	value := func(record []string, name string) float64 {
+		col, ok := columns[name]
+		if !ok || col >= len(record) {
+			return 0
+		}
+		v, _ := strconv.ParseFloat(record[col], 64)
+		return v
+	}
+
+	var metrics []*models.Metrics
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Older files have no milliseconds; the layout accepts both
+		ts, err := time.ParseInLocation("2006-01-02 15:04:05", record[columns["Timestamp"]], time.Local)
+		if err != nil {
+			continue
+		}
+
+		m := &models.Metrics{Timestamp: ts}
+		m.CPU.UsagePercent = value(record, "CPU%")
+		m.CPU.Temperature = value(record, "CPU_Temp")
+		m.CPU.PackagePowerWatts = value(record, "CPU_Package_W")
+		m.CPU.DRAMPowerWatts = value(record, "DRAM_W")
+		m.Memory.UsedMB = uint64(value(record, "RAM_MB"))
+		m.Memory.TotalMB = uint64(value(record, "RAM_Total_MB"))
+		m.Memory.UsedPercent = value(record, "RAM%")
+		m.Memory.SwapUsedMB = uint64(value(record, "Swap_MB"))
+		m.GPU.UsagePercent = value(record, "GPU%")
+		m.GPU.TemperatureC = uint32(value(record, "GPU_Temp"))
+		m.GPU.VRAMUsedMB = uint64(value(record, "GPU_VRAM_MB"))
+		m.GPU.VRAMTotalMB = uint64(value(record, "GPU_VRAM_Total_MB"))
+		m.GPU.PowerWatts = value(record, "GPU_Power_W")
+		m.Disk.ReadMBps = value(record, "Disk_Read_MBps")
+		m.Disk.WriteMBps = value(record, "Disk_Write_MBps")
+		m.Network.DownloadKBps = value(record, "Net_Download_KBps")
+		m.Network.UploadKBps = value(record, "Net_Upload_KBps")
+		m.Network.GameServerMs = value(record, "Game_Server_ms")
+		m.Disk.QueueLength = value(record, "Disk_Queue")
+		m.Memory.HardFaultsPerSec = value(record, "Hard_Faults_per_s")
+		if col, ok := columns["Bottleneck"]; ok && col < len(record) {
+			m.Bottleneck.Class = models.BottleneckClass(record[col])
+			m.Bottleneck.Confidence = value(record, "Bottleneck_Confidence")
+		}
+		m.IntervalMs = value(record, "Interval_ms")
+		metrics = append(metrics, m)
+	}
+
+	return metrics, nil
+}
+
+// JSONExport is the document written by ExportJSON.
+type JSONExport struct {
+	// ExportedAt is when the export was created.
+	ExportedAt time.Time `json:"exported_at"`
+	// SystemInfo is the system inventory at export time.
+	SystemInfo *models.SystemInfo `json:"system_info,omitempty"`
+	// Metrics is the metrics history.
+	Metrics []*models.Metrics `json:"metrics"`
+	// ProcessEvents contains recent process start/exit events.
+	ProcessEvents []*models.ProcessEvent `json:"process_events,omitempty"`
+	// Events contains collector events such as resume after sleep.
+	Events []*models.CollectorEvent `json:"events,omitempty"`
+	// ProcessTree is the process hierarchy at export time.
+	ProcessTree []*models.ProcessTreeNode `json:"process_tree,omitempty"`
+}
+
+// collectorEventName returns the CSV representation of a collector event.
+func collectorEventName(event *models.CollectorEvent) string {
+	if event == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s after %.0fs", event.Type, event.GapSec)
+}
+
+// ExportJSON exports metrics and related data to a new JSON file.
+func (l *Logger) ExportJSON(path string, export *JSONExport) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(export)
+}
+
+// ExportProcessEventsCSV exports process start/exit events to a new CSV file.
+func (l *Logger) ExportProcessEventsCSV(path string, events []*models.ProcessEvent) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	defer writer.Flush()
+
+	header := []string{
+		"Timestamp",
+		"Event",
+		"Name",
+		"PID",
+		"Parent_PID",
+		"Start_Time",
+		"Runtime_s",
+		"Peak_RAM_MB",
+		"Last_RAM_MB",
+		"Exit_Code",
+		"Crashed",
+		"Watch_IDs",
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, e := range events {
+		startTime := ""
+		if !e.StartTime.IsZero() {
+			startTime = e.StartTime.Format("2006-01-02 15:04:05")
+		}
+		exitCode, crashed := "", ""
+		if e.ExitCode != nil {
+			exitCode = fmt.Sprintf("%d", *e.ExitCode)
+			crashed = fmt.Sprintf("%t", e.Crashed)
+		}
+		record := []string{
+			e.Timestamp.Format("2006-01-02 15:04:05"),
+			string(e.Type),
+			e.Name,
+			fmt.Sprintf("%d", e.PID),
+			fmt.Sprintf("%d", e.ParentPID),
+			startTime,
+			fmt.Sprintf("%.0f", e.Runtime.Seconds()),
+			fmt.Sprintf("%d", e.PeakMemoryMB),
+			fmt.Sprintf("%d", e.LastMemoryMB),
+			exitCode,
+			crashed,
+			strings.Join(e.WatchIDs, ";"),
 		}
 		if err := writer.Write(record); err != nil {
 			return err
@@ -278,6 +532,36 @@ func (l *Logger) ExportMetricsCSV(path string, metrics []*models.Metrics) error
 	return nil
 }
 
+// watchedProcessIDs returns the watchlist IDs present in metrics, in order of first appearance.
+func watchedProcessIDs(metrics []*models.Metrics) []string {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, m := range metrics {
+		for _, w := range m.WatchedProcesses {
+			if !seen[w.ID] {
+				seen[w.ID] = true
+				ids = append(ids, w.ID)
+			}
+		}
+	}
+	return ids
+}
+
+// pingTargetNames returns the ping target names present in metrics, in order of first appearance.
+func pingTargetNames(metrics []*models.Metrics) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range metrics {
+		for _, p := range m.Network.PingTargets {
+			if !seen[p.Name] {
+				seen[p.Name] = true
+				names = append(names, p.Name)
+			}
+		}
+	}
+	return names
+}
+
 // Close closes the logger and associated resources.
 func (l *Logger) Close() {
 	l.csvMu.Lock()
@@ -11,6 +11,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -20,9 +21,11 @@ import (
 	"github.com/NaveLIL/erez-monitor/autostart"
 	"github.com/NaveLIL/erez-monitor/collector"
 	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/frametime"
 	"github.com/NaveLIL/erez-monitor/hotkeys"
 	"github.com/NaveLIL/erez-monitor/logger"
 	"github.com/NaveLIL/erez-monitor/models"
+	"github.com/NaveLIL/erez-monitor/recorder"
 	"github.com/NaveLIL/erez-monitor/ui"
 )
 
@@ -55,6 +58,8 @@ type Application struct {
 	overlay   *ui.Overlay
 	hotkeys   *hotkeys.Manager
 	autostart *autostart.Manager
+	sessions  *recorder.SessionRecorder
+	flight    *recorder.FlightRecorder
 	mutex     uintptr // Single instance mutex handle
 
 	ctx          context.Context
@@ -87,6 +92,9 @@ func main() {
 	debug := flag.Bool("debug", false, "Enable debug logging")
 	trayOnly := flag.Bool("tray-only", false, "Start minimized to system tray")
 	version := flag.Bool("version", false, "Print version and exit")
+	frameTimes := flag.String("frametimes", "", "Analyze a PresentMon or MangoHud frame-time log and exit")
+	metricsCSV := flag.String("metrics", "", "Metrics CSV log to align with -frametimes")
+	reportPath := flag.String("report", "", "Report file for -frametimes (default: <log>-report.json)")
 	flag.Parse()
 
 	if *version {
@@ -94,6 +102,14 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *frameTimes != "" {
+		if err := analyzeFrameTimes(*frameTimes, *metricsCSV, *reportPath); err != nil {
+			fmt.Fprintf(os.Stderr, "Frame-time analysis failed: %v\n", err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+
 	// Check for single instance
 	mutex, isFirst := checkSingleInstance()
 	if !isFirst {
@@ -116,6 +132,59 @@ func main() {
 	app.run(*trayOnly)
 }
 
+// analyzeFrameTimes analyzes a frame-time log, aligns it with the metrics
+// CSV log (if given) and writes the report as JSON plus a per-sample CSV.
+func analyzeFrameTimes(logPath, metricsPath, reportPath string) error {
+	frameLog, err := frametime.Load(logPath)
+	if err != nil {
+		return err
+	}
+
+	var metrics []*models.Metrics
+	if metricsPath != "" {
+		if metrics, err = logger.ReadMetricsCSV(metricsPath); err != nil {
+			return fmt.Errorf("failed to read metrics: %w", err)
+		}
+	}
+
+	report := frametime.BuildReport(frameLog, metrics)
+
+	if reportPath == "" {
+		reportPath = strings.TrimSuffix(logPath, filepath.Ext(logPath)) + "-report.json"
+	}
+	jsonFile, err := os.Create(reportPath)
+	if err != nil {
+		return err
+	}
+	defer jsonFile.Close()
+	if err := report.WriteJSON(jsonFile); err != nil {
+		return err
+	}
+
+	if len(report.Intervals) > 0 {
+		csvPath := strings.TrimSuffix(reportPath, filepath.Ext(reportPath)) + ".csv"
+		csvFile, err := os.Create(csvPath)
+		if err != nil {
+			return err
+		}
+		defer csvFile.Close()
+		if err := report.WriteCSV(csvFile); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("%s (%s): %s\n", report.Application, report.Format, report.Summary)
+	if report.System != nil {
+		fmt.Printf("System: CPU avg %.1f%% / max %.1f%%, GPU avg %.1f%% / max %.1f%% over %d samples\n",
+			report.System.AvgCPUPercent, report.System.MaxCPUPercent,
+			report.System.AvgGPUPercent, report.System.MaxGPUPercent, report.System.Samples)
+	} else if metricsPath != "" {
+		fmt.Println("No metrics samples overlap the capture")
+	}
+	fmt.Printf("Report written to %s\n", reportPath)
+	return nil
+}
+
 // init initializes all application components.
 func (app *Application) init(configPath string, debug bool) error {
 	var err error
@@ -169,6 +238,23 @@ func (app *Application) init(configPath string, debug bool) error {
 
 	// Initialize alerter
 	app.alerter = alerter.New(&app.config.Alerts)
+	app.alerter.SetWatchlist(app.config.Monitoring.Watchlist)
+	app.alerter.SetSensors(app.config.Monitoring.Sensors.Sensors)
+	app.alerter.SetMetricsHistory(app.collector.GetHistory())
+
+	// Initialize benchmark session recorder
+	benchmarkDir := app.config.Benchmark.Dir
+	if !filepath.IsAbs(benchmarkDir) {
+		benchmarkDir = filepath.Join(configDir, benchmarkDir)
+	}
+	app.sessions = recorder.NewSessionRecorder(&app.config.Benchmark, &app.config.Alerts, benchmarkDir, app.collector)
+
+	// Initialize flight recorder
+	flightDir := app.config.FlightRecorder.Dir
+	if !filepath.IsAbs(flightDir) {
+		flightDir = filepath.Join(configDir, flightDir)
+	}
+	app.flight = recorder.NewFlightRecorder(&app.config.FlightRecorder, flightDir, app.collector)
 
 	// Initialize autostart manager
 	app.autostart = autostart.New()
@@ -220,16 +306,40 @@ func (app *Application) run(trayOnly bool) {
 			// Check alerts
 			app.alerter.Check(metrics)
 
+			// Feed activity back into adaptive sampling
+			app.collector.SetActivity(app.overlay.IsVisible(), app.alerter.HasActiveAlerts())
+
 			// Log metrics to CSV
 			app.log.LogMetrics(metrics)
 		}
 	}()
 
+	// Connect alerter to process lifecycle events
+	processEventsCh := make(chan *models.ProcessEvent, 64)
+	app.collector.SubscribeProcessEvents(processEventsCh)
+
+	go func() {
+		for event := range processEventsCh {
+			if len(event.WatchIDs) > 0 {
+				app.log.Infof("Watched process %s: %s (PID %d)", event.Type, event.Name, event.PID)
+			}
+			app.alerter.CheckProcessEvent(event)
+		}
+	}()
+
 	// Set up alert handler for tray notifications
 	app.alerter.AddHandler(func(alert *models.Alert) {
 		app.tray.ShowNotification("EREZMonitor Alert", alert.Message)
 	})
 
+	// Dump the history around alerts
+	app.alerter.AddHandler(app.flight.HandleAlert)
+	app.flight.SetOnSaved(func(dump *recorder.FlightDump) {
+		if dump.Reason == recorder.ReasonManual {
+			app.tray.ShowNotification("Flight recording saved", dump.Path)
+		}
+	})
+
 	// Set up tray callbacks
 	app.tray.SetCallbacks(
 		app.onShowDetails,
@@ -237,8 +347,10 @@ func (app *Application) run(trayOnly bool) {
 		app.onMoveOverlay,
 		app.onSettings,
 		app.onExportLogs,
+		app.onFlightRecord,
 		app.onQuit,
 		app.onAutostart,
+		app.onToggleBenchmark,
 	)
 
 	// Start hotkey manager
@@ -258,6 +370,14 @@ func (app *Application) run(trayOnly bool) {
 			app.onToggleOverlay,
 			app.onMoveOverlay,
 		)
+		if app.config.Benchmark.Hotkey != "" {
+			app.hotkeys.Register(hotkeys.HotkeyBenchmark, app.config.Benchmark.Hotkey, func() {
+				app.tray.SetBenchmarkActive(app.onToggleBenchmark())
+			})
+		}
+		if app.config.FlightRecorder.Hotkey != "" {
+			app.hotkeys.Register(hotkeys.HotkeyFlightRecord, app.config.FlightRecorder.Hotkey, app.onFlightRecord)
+		}
 	}
 
 	// Start overlay
@@ -297,6 +417,15 @@ func (app *Application) shutdown() {
 	app.shutdownOnce.Do(func() {
 		app.log.Info("Shutting down...")
 
+		// Save a running benchmark session and flight recording before the
+		// collector stops
+		if app.sessions != nil && app.sessions.Active() {
+			app.onToggleBenchmark()
+		}
+		if app.flight != nil {
+			app.flight.Flush()
+		}
+
 		// Cancel context to stop all goroutines
 		app.cancel()
 
@@ -362,6 +491,24 @@ func (app *Application) onShowDetails() {
 	// Get latest metrics and print summary
 	if latest := app.collector.GetHistory().GetLatest(); latest != nil {
 		fmt.Printf("\n=== EREZMonitor Details ===\n")
+		if info := app.collector.GetSystemInfo(); info != nil {
+			uptime := time.Duration(info.UptimeSec) * time.Second
+			fmt.Printf("Host: %s | %s | Kernel: %s | Uptime: %v\n", info.Hostname, info.OS, info.KernelVersion, uptime)
+			if info.Board.Product != "" {
+				fmt.Printf("Board: %s %s | BIOS: %s (%s)\n",
+					info.Board.Manufacturer, info.Board.Product, info.BIOS.Version, info.BIOS.Date)
+			}
+			for _, module := range info.MemoryModules {
+				fmt.Printf("  DIMM %s: %d MB %s-%d %s\n",
+					module.Locator, module.SizeMB, module.Type, module.SpeedMTs, module.PartNumber)
+			}
+			for _, disk := range info.DiskDevices {
+				fmt.Printf("  Drive %s: %s %d GB (S/N %s)\n", disk.Name, disk.Model, disk.SizeGB, disk.Serial)
+			}
+			for _, nic := range info.NetworkInterfaces {
+				fmt.Printf("  NIC %s: %s\n", nic.Name, nic.MAC)
+			}
+		}
 		fmt.Printf("CPU: %.1f%% (Cores: %d)\n", latest.CPU.UsagePercent, len(latest.CPU.PerCorePercent))
 		fmt.Printf("RAM: %d/%d MB (%.1f%%)\n", latest.Memory.UsedMB, latest.Memory.TotalMB, latest.Memory.UsedPercent)
 		if latest.GPU.Available {
@@ -449,6 +596,40 @@ func (app *Application) onSettings() {
 	}()
 }
 
+// onToggleBenchmark starts or stops a benchmark session and returns whether
+// a session is recording.
+func (app *Application) onToggleBenchmark() bool {
+	summary, err := app.sessions.Toggle()
+	if summary == nil {
+		if err != nil {
+			app.log.Errorf("Failed to start benchmark session: %v", err)
+			return app.sessions.Active()
+		}
+		app.tray.ShowNotification("Benchmark started", "Recording every snapshot until the session is stopped")
+		return true
+	}
+
+	if err != nil {
+		app.log.Errorf("Failed to save benchmark session: %v", err)
+		app.tray.ShowNotification("Benchmark stopped", fmt.Sprintf("Failed to save the summary: %v", err))
+		return false
+	}
+	app.log.Infof("Benchmark summary saved to: %s", summary.MarkdownPath)
+	app.tray.ShowNotification("Benchmark stopped",
+		fmt.Sprintf("%d samples over %.0f s, summary saved to %s", summary.Samples, summary.DurationSec, summary.MarkdownPath))
+	return false
+}
+
+// onFlightRecord saves the recent history and the next seconds on demand.
+func (app *Application) onFlightRecord() {
+	if !app.flight.Trigger(recorder.ReasonManual, nil) {
+		app.log.Info("Flight recorder is already recording, the request is merged")
+		return
+	}
+	app.tray.ShowNotification("Flight recorder",
+		fmt.Sprintf("Recording the next %v, the file will be saved shortly", app.config.FlightRecorder.After))
+}
+
 // onExportLogs is called when "Export Logs" is clicked.
 func (app *Application) onExportLogs() {
 	app.log.Debug("Export Logs clicked")
@@ -475,6 +656,26 @@ func (app *Application) onExportLogs() {
 		return
 	}
 
+	// Export full snapshots (including process details and tree) as JSON
+	jsonPath := filepath.Join(homeDir, "Documents", fmt.Sprintf("erez-monitor-export-%s.json", timestamp))
+	export := &logger.JSONExport{
+		ExportedAt:    time.Now(),
+		SystemInfo:    app.collector.GetSystemInfo(),
+		Metrics:       history,
+		ProcessEvents: app.collector.GetProcessEvents(),
+		Events:        app.collector.GetEvents(),
+		ProcessTree:   app.collector.GetProcessTree(),
+	}
+	if err := app.log.ExportJSON(jsonPath, export); err != nil {
+		app.log.Errorf("Failed to export JSON: %v", err)
+	}
+
+	// Export process lifecycle events alongside the metrics
+	eventsPath := filepath.Join(homeDir, "Documents", fmt.Sprintf("erez-monitor-events-%s.csv", timestamp))
+	if err := app.log.ExportProcessEventsCSV(eventsPath, app.collector.GetProcessEvents()); err != nil {
+		app.log.Errorf("Failed to export process events: %v", err)
+	}
+
 	app.log.Infof("Metrics exported to: %s", exportPath)
 	app.tray.ShowNotification("Export Complete", fmt.Sprintf("Metrics exported to %s", exportPath))
 }
@@ -510,14 +711,31 @@ func (app *Application) logSystemInfo() {
 	}
 
 	app.log.Info("=== System Hardware Detected ===")
+	if info.Hostname != "" {
+		app.log.Infof("Host: %s (%s, kernel %s, %s)", info.Hostname, info.OS, info.KernelVersion, info.Arch)
+	}
+	if info.Board.Product != "" {
+		app.log.Infof("Board: %s %s | BIOS: %s %s (%s)",
+			info.Board.Manufacturer, info.Board.Product, info.BIOS.Vendor, info.BIOS.Version, info.BIOS.Date)
+	}
 	if info.CPUModel != "" {
 		app.log.Infof("CPU: %s (%d cores, %d threads)", info.CPUModel, info.CPUCores, info.CPUThreads)
 	}
 	if info.TotalRAM > 0 {
 		app.log.Infof("RAM: %d MB (%.1f GB)", info.TotalRAM, float64(info.TotalRAM)/1024)
 	}
+	for _, module := range info.MemoryModules {
+		app.log.Infof("  %s: %d MB %s-%d %s %s",
+			module.Locator, module.SizeMB, module.Type, module.SpeedMTs, module.Manufacturer, module.PartNumber)
+	}
 	if info.GPUName != "" {
 		app.log.Infof("GPU: %s", info.GPUName)
 	}
+	for _, disk := range info.DiskDevices {
+		app.log.Infof("Disk: %s %s (%d GB)", disk.Name, disk.Model, disk.SizeGB)
+	}
+	for _, nic := range info.NetworkInterfaces {
+		app.log.Infof("NIC: %s %s", nic.Name, nic.MAC)
+	}
 	app.log.Info("================================")
 }
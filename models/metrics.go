@@ -11,7 +11,75 @@ type Metrics struct {
 	GPU          GPUMetrics     `json:"gpu"`
 	Disk         DiskMetrics    `json:"disk"`
 	Network      NetworkMetrics `json:"network"`
+	Power        PowerMetrics   `json:"power"`
 	TopProcesses []ProcessInfo  `json:"top_processes"`
+	// TopGPUProcesses contains the processes using the GPU, busiest first
+	// (when per-process GPU monitoring is available).
+	TopGPUProcesses []ProcessInfo `json:"top_gpu_processes,omitempty"`
+	// TopMemoryProcesses contains the processes using the most memory, largest first.
+	TopMemoryProcesses []ProcessInfo `json:"top_memory_processes,omitempty"`
+	// TopIOProcesses contains the processes with the highest disk I/O rate,
+	// busiest first (when per-process I/O is measured).
+	TopIOProcesses []ProcessInfo `json:"top_io_processes,omitempty"`
+	// MemoryLeaks contains the processes whose memory grows steadily over the
+	// long-term history, fastest first.
+	MemoryLeaks []MemoryLeak `json:"memory_leaks,omitempty"`
+	// ProcessGroups contains aggregated process groups (when grouping is enabled).
+	ProcessGroups []ProcessGroupInfo `json:"process_groups,omitempty"`
+	// WatchedProcesses contains one entry per configured watchlist item, in config order.
+	WatchedProcesses []WatchedProcessInfo `json:"watched_processes,omitempty"`
+	// Sensors contains hardware sensor readings (temperatures, fans, voltages, power).
+	Sensors []SensorReading `json:"sensors,omitempty"`
+	// Throttling is the CPU/GPU throttling state.
+	Throttling ThrottlingState `json:"throttling"`
+	// Bottleneck is what limits performance over the recent samples.
+	Bottleneck BottleneckState `json:"bottleneck"`
+	// Cgroups contains resource usage of monitored cgroups (systemd units, containers).
+	Cgroups []CgroupStats `json:"cgroups,omitempty"`
+	// IntervalMs is the actual time since the previous sample in milliseconds
+	// (0 for the first sample and after a gap). The interval varies with
+	// adaptive sampling.
+	IntervalMs float64 `json:"interval_ms"`
+	// Event is set on the first sample after a collection gap (e.g., resume
+	// from sleep). History consumers must not connect it to the previous sample.
+	Event *CollectorEvent `json:"event,omitempty"`
+	// Session is the name of the benchmark session the snapshot was recorded
+	// in (set on recorded copies only).
+	Session string `json:"session,omitempty"`
+}
+
+// CgroupStats contains resource usage of a cgroup v2 group.
+type CgroupStats struct {
+	// Path is the cgroup path relative to the cgroup root.
+	Path string `json:"path"`
+	// Name is the last path element (e.g., "docker.service").
+	Name string `json:"name"`
+	// CPUPercent is the CPU usage since the previous sample (100 = one full core).
+	CPUPercent float64 `json:"cpu_percent"`
+	// NrThrottled is the number of periods the group was CPU throttled.
+	NrThrottled uint64 `json:"nr_throttled"`
+	// ThrottledMs is the total time the group was CPU throttled in milliseconds.
+	ThrottledMs uint64 `json:"throttled_ms"`
+	// MemoryCurrentMB is the current memory usage in MB.
+	MemoryCurrentMB uint64 `json:"memory_current_mb"`
+	// MemoryMaxMB is the memory limit in MB (0 = unlimited).
+	MemoryMaxMB uint64 `json:"memory_max_mb"`
+	// MemoryPercent is the memory usage relative to the limit (0 when unlimited).
+	MemoryPercent float64 `json:"memory_percent"`
+	// IOReadKBps is the read rate across all devices in KB/s.
+	IOReadKBps float64 `json:"io_read_kbps"`
+	// IOWriteKBps is the write rate across all devices in KB/s.
+	IOWriteKBps float64 `json:"io_write_kbps"`
+	// Pids is the number of processes in the group.
+	Pids uint64 `json:"pids"`
+	// PidsMax is the process limit (0 = unlimited).
+	PidsMax uint64 `json:"pids_max"`
+}
+
+// EstimatedPowerWatts returns the combined power draw of the measured
+// components (CPU package, DRAM and GPU), a lower bound of system power.
+func (m *Metrics) EstimatedPowerWatts() float64 {
+	return m.CPU.PackagePowerWatts + m.CPU.DRAMPowerWatts + m.GPU.PowerWatts
 }
 
 // CPUMetrics contains CPU-related metrics.
@@ -24,6 +92,111 @@ type CPUMetrics struct {
 	Temperature float64 `json:"temperature"`
 	// FrequencyMHz is the current CPU frequency in MHz.
 	FrequencyMHz uint32 `json:"frequency_mhz"`
+	// PackagePowerWatts is the CPU package power draw in watts (0 if unavailable).
+	PackagePowerWatts float64 `json:"package_power_watts"`
+	// DRAMPowerWatts is the DRAM power draw in watts (0 if unavailable).
+	DRAMPowerWatts float64 `json:"dram_power_watts"`
+	// PerCoreFrequencyMHz is the live frequency of each core in MHz (if available).
+	PerCoreFrequencyMHz []uint32 `json:"per_core_frequency_mhz,omitempty"`
+	// MaxFrequencyMHz is the highest maximum core frequency in MHz (if available).
+	MaxFrequencyMHz uint32 `json:"max_frequency_mhz,omitempty"`
+}
+
+// Throttling reasons.
+const (
+	// ThrottleThermal means the CPU is throttled because of its temperature.
+	ThrottleThermal = "thermal"
+	// ThrottlePower means the CPU runs below its maximum frequency under load
+	// while not hot, e.g. because of power or current limits.
+	ThrottlePower = "power"
+	// ThrottleGPUThermal means the GPU is at its thermal throttling temperature.
+	ThrottleGPUThermal = "gpu_thermal"
+)
+
+// ThrottlingState describes whether the CPU or GPU is being throttled.
+type ThrottlingState struct {
+	// Throttled indicates that throttling was detected.
+	Throttled bool `json:"throttled"`
+	// Reasons lists the detected throttling reasons (thermal, power, gpu_thermal).
+	Reasons []string `json:"reasons,omitempty"`
+	// FrequencyRatio is the ratio of live to maximum frequency of the busy
+	// cores, or of the fastest core without per-core usage (0 if unknown).
+	FrequencyRatio float64 `json:"frequency_ratio"`
+	// CoreThrottleEvents is the number of new core thermal throttle events.
+	CoreThrottleEvents uint64 `json:"core_throttle_events"`
+	// PackageThrottleEvents is the number of new package thermal throttle events.
+	PackageThrottleEvents uint64 `json:"package_throttle_events"`
+	// Since is when the current throttling episode started.
+	Since time.Time `json:"since,omitempty"`
+	// DurationSec is how long the current throttling episode has lasted.
+	DurationSec float64 `json:"duration_sec"`
+}
+
+// MemoryLeak is a process whose memory grows steadily over time.
+type MemoryLeak struct {
+	// Name is the process name.
+	Name string `json:"name"`
+	// PID is the process ID.
+	PID int32 `json:"pid"`
+	// MemoryMB is the latest downsampled memory usage in megabytes.
+	MemoryMB uint64 `json:"memory_mb"`
+	// GrowthMBPerHour is the fitted growth rate.
+	GrowthMBPerHour float64 `json:"growth_mb_per_hour"`
+	// TStat is the t-statistic of the growth rate; higher is more significant.
+	TStat float64 `json:"t_stat"`
+	// Since is the first sample of the analyzed period.
+	Since time.Time `json:"since"`
+	// ExhaustInSec is the projected time until RAM runs out at this rate
+	// (0 if unknown).
+	ExhaustInSec float64 `json:"exhaust_in_sec"`
+}
+
+// BottleneckClass is the component limiting performance.
+type BottleneckClass string
+
+const (
+	// BottleneckGPU means the GPU is saturated.
+	BottleneckGPU BottleneckClass = "gpu_bound"
+	// BottleneckCPUThread means one core is saturated while the others and
+	// the GPU have headroom (a single-threaded limit, typical for games).
+	BottleneckCPUThread BottleneckClass = "cpu_single_thread"
+	// BottleneckCPU means all cores are saturated.
+	BottleneckCPU BottleneckClass = "cpu_bound"
+	// BottleneckVRAM means video memory is full and spills to system memory.
+	BottleneckVRAM BottleneckClass = "vram_limited"
+	// BottleneckIO means the processors wait on paging or a deep disk queue.
+	BottleneckIO BottleneckClass = "io_stalled"
+	// BottleneckBalanced means no component is limiting.
+	BottleneckBalanced BottleneckClass = "balanced"
+)
+
+// BottleneckState is the result of the bottleneck classifier.
+type BottleneckState struct {
+	// Class is the limiting component (empty until enough samples are seen).
+	Class BottleneckClass `json:"class,omitempty"`
+	// Confidence is how certain the classification is (0-1).
+	Confidence float64 `json:"confidence"`
+	// Reason lists the readings behind the classification.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Label returns a short human-readable name of the class.
+func (c BottleneckClass) Label() string {
+	switch c {
+	case BottleneckGPU:
+		return "GPU-bound"
+	case BottleneckCPUThread:
+		return "CPU-bound (1 thread)"
+	case BottleneckCPU:
+		return "CPU-bound"
+	case BottleneckVRAM:
+		return "VRAM-limited"
+	case BottleneckIO:
+		return "IO-stalled"
+	case BottleneckBalanced:
+		return "Balanced"
+	}
+	return ""
 }
 
 // MemoryMetrics contains RAM-related metrics.
@@ -38,6 +211,9 @@ type MemoryMetrics struct {
 	SwapUsedMB uint64 `json:"swap_used_mb"`
 	// SwapTotalMB is the total swap space in megabytes.
 	SwapTotalMB uint64 `json:"swap_total_mb"`
+	// HardFaultsPerSec is the rate of page faults resolved from disk
+	// (major faults on Linux, page reads on Windows).
+	HardFaultsPerSec float64 `json:"hard_faults_per_sec"`
 }
 
 // GPUMetrics contains GPU-related metrics (NVIDIA GPUs via NVML).
@@ -74,6 +250,9 @@ type DiskMetrics struct {
 	ReadIOPS uint64 `json:"read_iops"`
 	// WriteIOPS is the number of write operations per second.
 	WriteIOPS uint64 `json:"write_iops"`
+	// QueueLength is the average number of outstanding I/O requests on the
+	// busiest disk.
+	QueueLength float64 `json:"queue_length"`
 	// Disks contains information about each disk partition.
 	Disks []DiskInfo `json:"disks"`
 }
@@ -94,6 +273,96 @@ type DiskInfo struct {
 	UsedPercent float64 `json:"used_percent"`
 }
 
+// SensorType is the kind of a hardware sensor.
+type SensorType string
+
+const (
+	SensorTemperature SensorType = "temperature"
+	SensorFan         SensorType = "fan"
+	SensorVoltage     SensorType = "voltage"
+	SensorPower       SensorType = "power"
+)
+
+// SensorReading is a single hardware sensor value.
+type SensorReading struct {
+	// ID is the stable sensor identifier ("chip/sensor", e.g. "coretemp/temp1").
+	ID string `json:"id"`
+	// Chip is the sensor chip driver name (e.g. "coretemp", "nct6775").
+	Chip string `json:"chip"`
+	// Label is the label reported by the driver (e.g. "Package id 0").
+	Label string `json:"label"`
+	// Name is the display name (user-defined, or the label).
+	Name string `json:"name"`
+	// Type is the sensor type.
+	Type SensorType `json:"type"`
+	// Value is the current value in Unit.
+	Value float64 `json:"value"`
+	// Unit is the value unit (°C, RPM, V, W).
+	Unit string `json:"unit"`
+	// Max is the hardware maximum reported by the driver (0 if unknown).
+	Max float64 `json:"max,omitempty"`
+	// Crit is the hardware critical value reported by the driver (0 if unknown).
+	Crit float64 `json:"crit,omitempty"`
+}
+
+// Battery statuses.
+const (
+	BatteryCharging    = "charging"
+	BatteryDischarging = "discharging"
+	BatteryFull        = "full"
+	BatteryNotCharging = "not charging"
+	BatteryUnknown     = "unknown"
+)
+
+// PowerMetrics contains battery and AC adapter state.
+type PowerMetrics struct {
+	// Available indicates if a system battery was found.
+	Available bool `json:"available"`
+	// OnAC indicates the system runs on external power.
+	OnAC bool `json:"on_ac"`
+	// BatteryPercent is the combined charge of all batteries (0-100).
+	BatteryPercent float64 `json:"battery_percent"`
+	// Status is the combined battery status (charging, discharging, full, ...).
+	Status string `json:"status,omitempty"`
+	// EnergyNowWh is the remaining energy in watt-hours.
+	EnergyNowWh float64 `json:"energy_now_wh"`
+	// EnergyFullWh is the energy when fully charged in watt-hours.
+	EnergyFullWh float64 `json:"energy_full_wh"`
+	// PowerDrawW is the current charge or discharge rate in watts.
+	PowerDrawW float64 `json:"power_draw_w"`
+	// TimeToEmptyMin is the estimated runtime left in minutes (0 if unknown).
+	TimeToEmptyMin float64 `json:"time_to_empty_min"`
+	// TimeToFullMin is the estimated time until fully charged in minutes (0 if unknown).
+	TimeToFullMin float64 `json:"time_to_full_min"`
+	// Batteries contains per-battery details.
+	Batteries []BatteryInfo `json:"batteries,omitempty"`
+}
+
+// OnBattery returns true if the system is running from its battery.
+func (p PowerMetrics) OnBattery() bool {
+	return p.Available && !p.OnAC
+}
+
+// BatteryInfo contains the state of a single battery.
+type BatteryInfo struct {
+	// Name is the battery name (e.g., "BAT0").
+	Name string `json:"name"`
+	// Status is the battery status (charging, discharging, full, ...).
+	Status string `json:"status"`
+	// CapacityPercent is the charge level (0-100).
+	CapacityPercent float64 `json:"capacity_percent"`
+	// EnergyNowWh is the remaining energy in watt-hours.
+	EnergyNowWh float64 `json:"energy_now_wh"`
+	// EnergyFullWh is the energy when fully charged in watt-hours.
+	EnergyFullWh float64 `json:"energy_full_wh"`
+	// PowerW is the current charge or discharge rate in watts.
+	PowerW float64 `json:"power_w"`
+	// TimeToEmptyMin is the estimated runtime left in minutes.
+	TimeToEmptyMin float64 `json:"time_to_empty_min"`
+	// TimeToFullMin is the estimated time until fully charged in minutes.
+	TimeToFullMin float64 `json:"time_to_full_min"`
+}
+
 // NetworkMetrics contains network I/O metrics.
 type NetworkMetrics struct {
 	// DownloadKBps is the download speed in KB/s.
@@ -108,14 +377,114 @@ type NetworkMetrics struct {
 	PacketsRecv uint64 `json:"packets_recv"`
 	// PacketsSent is the number of packets sent per second.
 	PacketsSent uint64 `json:"packets_sent"`
-	// PingMs is the best network latency in milliseconds.
+	// PingMs is the best round-trip latency of the TCP, UDP and ICMP
+	// targets in milliseconds.
 	PingMs float64 `json:"ping_ms"`
 	// PingTarget is the name of the ping target with best latency.
 	PingTarget string `json:"ping_target"`
+	// PingTargets contains latency statistics for each ping target.
+	PingTargets []PingTargetStats `json:"ping_targets,omitempty"`
+	// GameServerMs is the latency to the game server in milliseconds (0 if unknown).
+	GameServerMs float64 `json:"game_server_ms"`
+	// GameServer describes the detected game server, if any.
+	GameServer *GameServerInfo `json:"game_server,omitempty"`
 	// Interfaces contains per-interface metrics.
 	Interfaces []InterfaceInfo `json:"interfaces"`
 }
 
+// PingTargetStats contains latency statistics for a ping target over the
+// collector's sliding window of recent probes.
+type PingTargetStats struct {
+	// Name is the target display name.
+	Name string `json:"name"`
+	// Host is the target host.
+	Host string `json:"host"`
+	// Protocol is the probe protocol (tcp, udp, icmp, http, dns).
+	Protocol string `json:"protocol"`
+	// Server is the DNS server queried by dns probes (empty = system resolver).
+	Server string `json:"server,omitempty"`
+	// Available indicates if the last probe succeeded.
+	Available bool `json:"available"`
+	// Status is the last probe status (ok, timeout, nxdomain, error).
+	Status string `json:"status"`
+	// Samples is the number of probes in the window.
+	Samples int `json:"samples"`
+	// LastMs is the latency of the last probe in milliseconds.
+	LastMs float64 `json:"last_ms"`
+	// AvgMs is the average latency of successful probes.
+	AvgMs float64 `json:"avg_ms"`
+	// MinMs is the minimum latency.
+	MinMs float64 `json:"min_ms"`
+	// P50Ms is the median latency.
+	P50Ms float64 `json:"p50_ms"`
+	// P95Ms is the 95th percentile latency.
+	P95Ms float64 `json:"p95_ms"`
+	// MaxMs is the maximum latency.
+	MaxMs float64 `json:"max_ms"`
+	// JitterMs is the mean difference between consecutive latencies.
+	JitterMs float64 `json:"jitter_ms"`
+	// LossPercent is the percentage of failed probes (0-100). NXDOMAIN
+	// answers are not failures.
+	LossPercent float64 `json:"loss_percent"`
+	// TimeoutCount is the number of timed out probes in the window.
+	TimeoutCount int `json:"timeout_count"`
+	// NXDomainCount is the number of NXDOMAIN answers in the window (dns probes).
+	NXDomainCount int `json:"nxdomain_count"`
+	// Error is the error of the last probe, if it failed.
+	Error string `json:"error,omitempty"`
+	// HTTP contains the last health check details of http probes.
+	HTTP *HTTPCheckInfo `json:"http,omitempty"`
+}
+
+// GameServerInfo describes the server a game process is connected to.
+type GameServerInfo struct {
+	// ProcessName is the name of the game process.
+	ProcessName string `json:"process_name"`
+	// PID is the game process ID.
+	PID int32 `json:"pid"`
+	// Address is the remote IP address of the server.
+	Address string `json:"address"`
+	// Port is the remote port of the server.
+	Port uint32 `json:"port"`
+	// Transport is the connection transport (udp or tcp).
+	Transport string `json:"transport"`
+	// Method is the probe used to measure latency (icmp or tcp).
+	Method string `json:"method,omitempty"`
+	// LatencyMs is the measured latency in milliseconds.
+	LatencyMs float64 `json:"latency_ms"`
+	// Available indicates if the last probe succeeded.
+	Available bool `json:"available"`
+	// Endpoints is the number of candidate remote endpoints found.
+	Endpoints int `json:"endpoints"`
+	// LastCheck is when the server was last probed.
+	LastCheck time.Time `json:"last_check"`
+}
+
+// HTTPCheckInfo contains the details of an HTTP(S) health check.
+type HTTPCheckInfo struct {
+	// StatusCode is the response status code (0 if no response).
+	StatusCode int `json:"status_code"`
+	// DNSMs is the DNS lookup time in milliseconds.
+	DNSMs float64 `json:"dns_ms"`
+	// ConnectMs is the TCP connect time in milliseconds.
+	ConnectMs float64 `json:"connect_ms"`
+	// TLSMs is the TLS handshake time in milliseconds.
+	TLSMs float64 `json:"tls_ms"`
+	// TTFBMs is the time to the first response byte in milliseconds.
+	TTFBMs float64 `json:"ttfb_ms"`
+	// BodyMatched indicates the expected body substring was found.
+	BodyMatched bool `json:"body_matched"`
+	// CertExpiry is the expiry time of the server certificate (https only).
+	CertExpiry time.Time `json:"cert_expiry,omitempty"`
+	// CertDaysLeft is the number of days until the certificate expires.
+	CertDaysLeft float64 `json:"cert_days_left"`
+}
+
+// HasCert returns true if the check saw a TLS certificate.
+func (h *HTTPCheckInfo) HasCert() bool {
+	return h != nil && !h.CertExpiry.IsZero()
+}
+
 // InterfaceInfo contains information about a single network interface.
 type InterfaceInfo struct {
 	// Name is the interface name.
@@ -126,6 +495,32 @@ type InterfaceInfo struct {
 	UploadKBps float64 `json:"upload_kbps"`
 	// IsUp indicates if the interface is active.
 	IsUp bool `json:"is_up"`
+	// Wireless contains the Wi-Fi link state (nil for wired interfaces).
+	Wireless *WirelessInfo `json:"wireless,omitempty"`
+}
+
+// WirelessInfo contains the link quality of a wireless interface.
+type WirelessInfo struct {
+	// Connected indicates the interface is associated with an access point.
+	Connected bool `json:"connected"`
+	// SSID is the network name.
+	SSID string `json:"ssid,omitempty"`
+	// BSSID is the access point MAC address.
+	BSSID string `json:"bssid,omitempty"`
+	// SignalDBm is the received signal level in dBm.
+	SignalDBm float64 `json:"signal_dbm"`
+	// NoiseDBm is the noise level in dBm (0 if not reported).
+	NoiseDBm float64 `json:"noise_dbm,omitempty"`
+	// LinkQuality is the link quality in percent.
+	LinkQuality float64 `json:"link_quality"`
+	// TxBitrateMbps is the transmit bitrate in Mbit/s.
+	TxBitrateMbps float64 `json:"tx_bitrate_mbps,omitempty"`
+	// RxBitrateMbps is the receive bitrate in Mbit/s.
+	RxBitrateMbps float64 `json:"rx_bitrate_mbps,omitempty"`
+	// FrequencyMHz is the channel frequency in MHz.
+	FrequencyMHz int `json:"frequency_mhz,omitempty"`
+	// RetriesPerSec is the rate of packets discarded after too many retries.
+	RetriesPerSec float64 `json:"retries_per_sec"`
 }
 
 // ProcessInfo contains information about a running process.
@@ -144,6 +539,162 @@ type ProcessInfo struct {
 	Threads int32 `json:"threads"`
 	// Status is the process status (running, sleeping, etc.).
 	Status string `json:"status"`
+	// DiskReadKBps is the disk read rate in KB/s (only when process grouping is enabled).
+	DiskReadKBps float64 `json:"disk_read_kbps,omitempty"`
+	// DiskWriteKBps is the disk write rate in KB/s (only when process grouping is enabled).
+	DiskWriteKBps float64 `json:"disk_write_kbps,omitempty"`
+	// ParentPID is the parent process ID.
+	ParentPID int32 `json:"parent_pid"`
+	// StartTime is when the process was created.
+	StartTime time.Time `json:"start_time"`
+	// Cmdline is the full command line (top and watched processes only).
+	Cmdline string `json:"cmdline,omitempty"`
+	// Username is the process owner (top and watched processes only).
+	Username string `json:"username,omitempty"`
+	// ExePath is the executable path (top and watched processes only).
+	ExePath string `json:"exe_path,omitempty"`
+	// GPUPercent is the utilisation of the busiest GPU engine used by the process.
+	GPUPercent float64 `json:"gpu_percent,omitempty"`
+	// GPUEngine is the busiest engine (e.g., "3D", "render", "video").
+	GPUEngine string `json:"gpu_engine,omitempty"`
+	// GPUMemoryMB is the dedicated video memory used by the process in megabytes.
+	GPUMemoryMB uint64 `json:"gpu_memory_mb,omitempty"`
+}
+
+// ProcessTreeNode is a process in the parent/child hierarchy, with resource
+// usage aggregated over its whole subtree (the process and all descendants).
+type ProcessTreeNode struct {
+	// Process is the process itself.
+	Process ProcessInfo `json:"process"`
+	// Children are the direct child processes.
+	Children []*ProcessTreeNode `json:"children,omitempty"`
+	// SubtreeProcessCount is the number of processes in the subtree.
+	SubtreeProcessCount int `json:"subtree_process_count"`
+	// SubtreeCPUPercent is the summed CPU usage of the subtree.
+	SubtreeCPUPercent float64 `json:"subtree_cpu_percent"`
+	// SubtreeMemoryMB is the summed resident memory of the subtree in megabytes.
+	SubtreeMemoryMB uint64 `json:"subtree_memory_mb"`
+	// SubtreeThreads is the summed thread count of the subtree.
+	SubtreeThreads int32 `json:"subtree_threads"`
+}
+
+// ProcessGroupInfo contains aggregated metrics for a group of processes
+// (e.g. all chrome.exe instances or all descendants of one process tree).
+type ProcessGroupInfo struct {
+	// Name is the group name (executable name, tree root name or rule name).
+	Name string `json:"name"`
+	// RootPID is the PID of the tree root (tree mode only).
+	RootPID int32 `json:"root_pid,omitempty"`
+	// ProcessCount is the number of processes in the group.
+	ProcessCount int `json:"process_count"`
+	// PIDs contains the process IDs belonging to the group.
+	PIDs []int32 `json:"pids"`
+	// CPUPercent is the summed CPU usage of the group.
+	CPUPercent float64 `json:"cpu_percent"`
+	// MemoryMB is the summed resident memory of the group in megabytes.
+	MemoryMB uint64 `json:"memory_mb"`
+	// Threads is the summed thread count of the group.
+	Threads int32 `json:"threads"`
+	// DiskReadKBps is the summed disk read rate in KB/s.
+	DiskReadKBps float64 `json:"disk_read_kbps"`
+	// DiskWriteKBps is the summed disk write rate in KB/s.
+	DiskWriteKBps float64 `json:"disk_write_kbps"`
+}
+
+// WatchedProcessInfo contains aggregated metrics for a watchlist entry.
+// All processes matching the entry are summed, so the entry keeps a stable
+// identity across restarts and PID changes.
+type WatchedProcessInfo struct {
+	// ID is the stable watchlist entry identifier.
+	ID string `json:"id"`
+	// Running indicates if at least one matching process is running.
+	Running bool `json:"running"`
+	// Name is the executable name of the first matching process.
+	Name string `json:"name"`
+	// ProcessCount is the number of matching processes.
+	ProcessCount int `json:"process_count"`
+	// PIDs contains the process IDs of the matching processes.
+	PIDs []int32 `json:"pids"`
+	// CPUPercent is the summed CPU usage of the matching processes.
+	CPUPercent float64 `json:"cpu_percent"`
+	// MemoryMB is the summed resident memory in megabytes.
+	MemoryMB uint64 `json:"memory_mb"`
+	// Threads is the summed thread count.
+	Threads int32 `json:"threads"`
+}
+
+// WatchedProcessPoint is a single historical sample of a watched process.
+type WatchedProcessPoint struct {
+	// Timestamp is when the sample was taken.
+	Timestamp time.Time `json:"timestamp"`
+	// Running indicates if the process was running.
+	Running bool `json:"running"`
+	// CPUPercent is the CPU usage percentage.
+	CPUPercent float64 `json:"cpu_percent"`
+	// MemoryMB is the memory usage in megabytes.
+	MemoryMB uint64 `json:"memory_mb"`
+}
+
+// CollectorEventType represents the type of a collector event.
+type CollectorEventType string
+
+const (
+	// CollectorEventResumed is emitted when collection resumes after a gap,
+	// such as system sleep.
+	CollectorEventResumed CollectorEventType = "resumed"
+)
+
+// CollectorEvent describes a gap in metrics collection.
+type CollectorEvent struct {
+	// Type is the event type.
+	Type CollectorEventType `json:"type"`
+	// Timestamp is when collection resumed.
+	Timestamp time.Time `json:"timestamp"`
+	// GapStart is the time of the last sample before the gap.
+	GapStart time.Time `json:"gap_start"`
+	// GapSec is the wall-clock length of the gap in seconds.
+	GapSec float64 `json:"gap_sec"`
+	// Suspended indicates the system was asleep (the monotonic clock stood
+	// still while the wall clock advanced).
+	Suspended bool `json:"suspended"`
+}
+
+// ProcessEventType represents the type of a process lifecycle event.
+type ProcessEventType string
+
+const (
+	ProcessEventStart ProcessEventType = "start"
+	ProcessEventExit  ProcessEventType = "exit"
+)
+
+// ProcessEvent represents a process start or exit detected between two scans.
+type ProcessEvent struct {
+	// Type is the event type (start, exit).
+	Type ProcessEventType `json:"type"`
+	// Timestamp is when the event was detected (scan time, not exact OS time).
+	Timestamp time.Time `json:"timestamp"`
+	// Name is the process name.
+	Name string `json:"name"`
+	// PID is the process ID.
+	PID int32 `json:"pid"`
+	// ParentPID is the parent process ID (0 if unknown).
+	ParentPID int32 `json:"parent_pid"`
+	// StartTime is when the process was created (zero if unknown).
+	StartTime time.Time `json:"start_time"`
+	// Runtime is how long the process ran (exit events only).
+	Runtime time.Duration `json:"runtime"`
+	// PeakMemoryMB is the highest resident memory seen during the process lifetime.
+	PeakMemoryMB uint64 `json:"peak_memory_mb"`
+	// LastMemoryMB is the resident memory at the last scan before exit.
+	LastMemoryMB uint64 `json:"last_memory_mb"`
+	// ExitCode is the exit status of the process (exit events only, nil if
+	// unknown; only available on Windows).
+	ExitCode *uint32 `json:"exit_code,omitempty"`
+	// Crashed indicates that the exit code is an exception status (e.g.,
+	// access violation) rather than a normal exit.
+	Crashed bool `json:"crashed,omitempty"`
+	// WatchIDs lists the watchlist entries the process matched.
+	WatchIDs []string `json:"watch_ids,omitempty"`
 }
 
 // AlertType represents the type of alert.
@@ -155,11 +706,22 @@ const (
 	AlertTypeGPU     AlertType = "gpu"
 	AlertTypeDisk    AlertType = "disk"
 	AlertTypeNetwork AlertType = "network"
+	AlertTypeProcess AlertType = "process"
+	AlertTypeBattery AlertType = "battery"
+	AlertTypeSensor  AlertType = "sensor"
+	// AlertTypeThrottling is raised when the CPU or GPU is throttled.
+	AlertTypeThrottling AlertType = "throttling"
+	// AlertTypeWireless is raised when the Wi-Fi signal is weak.
+	AlertTypeWireless AlertType = "wireless"
+	// AlertTypeCgroup is raised when a cgroup nears its memory limit.
+	AlertTypeCgroup AlertType = "cgroup"
+	// AlertTypeMemoryLeak is raised when a process's memory grows steadily.
+	AlertTypeMemoryLeak AlertType = "memory_leak"
 )
 
 // Alert represents a system alert when a threshold is exceeded.
 type Alert struct {
-	// Type is the alert type (cpu, ram, gpu, disk, network).
+	// Type is the alert type (cpu, ram, gpu, disk, network, process).
 	Type AlertType `json:"type"`
 	// Timestamp is when the alert was triggered.
 	Timestamp time.Time `json:"timestamp"`
@@ -169,6 +731,23 @@ type Alert struct {
 	Value float64 `json:"value"`
 	// Threshold is the threshold that was exceeded.
 	Threshold float64 `json:"threshold"`
+	// Contributors are the processes that contributed most to the spike
+	// (CPU, RAM and GPU alerts), largest increase first.
+	Contributors []ProcessContribution `json:"contributors,omitempty"`
+}
+
+// ProcessContribution is a process's share of a resource spike.
+type ProcessContribution struct {
+	// Name is the process name.
+	Name string `json:"name"`
+	// PID is the process ID.
+	PID int32 `json:"pid"`
+	// Value is the process usage during the spike (CPU %, MB or GPU %).
+	Value float64 `json:"value"`
+	// Delta is the increase over the usage before the spike.
+	Delta float64 `json:"delta"`
+	// New is set when the process started during the analyzed period.
+	New bool `json:"new"`
 }
 
 // SystemInfo contains static system information.
@@ -179,6 +758,14 @@ type SystemInfo struct {
 	OS string `json:"os"`
 	// Platform is the platform (windows, linux, darwin).
 	Platform string `json:"platform"`
+	// KernelVersion is the kernel version (Windows build number on Windows).
+	KernelVersion string `json:"kernel_version"`
+	// Arch is the kernel architecture (e.g., "x86_64").
+	Arch string `json:"arch"`
+	// BootTime is when the system was booted.
+	BootTime time.Time `json:"boot_time"`
+	// UptimeSec is the system uptime in seconds when the info was returned.
+	UptimeSec uint64 `json:"uptime_sec"`
 	// CPUModel is the CPU model name.
 	CPUModel string `json:"cpu_model"`
 	// CPUCores is the number of physical CPU cores.
@@ -189,6 +776,115 @@ type SystemInfo struct {
 	TotalRAM uint64 `json:"total_ram"`
 	// GPUName is the GPU model name (if available).
 	GPUName string `json:"gpu_name"`
+	// SystemVendor is the system manufacturer (from DMI/SMBIOS).
+	SystemVendor string `json:"system_vendor,omitempty"`
+	// SystemProduct is the system product name (from DMI/SMBIOS).
+	SystemProduct string `json:"system_product,omitempty"`
+	// Board is the motherboard.
+	Board BoardInfo `json:"board"`
+	// BIOS is the system firmware.
+	BIOS BIOSInfo `json:"bios"`
+	// MemoryModules lists the installed memory modules (if readable).
+	MemoryModules []MemoryModule `json:"memory_modules,omitempty"`
+	// DiskDevices lists the physical disks.
+	DiskDevices []DiskDevice `json:"disk_devices,omitempty"`
+	// NetworkInterfaces lists the network interfaces with a hardware address.
+	NetworkInterfaces []NetworkInterface `json:"network_interfaces,omitempty"`
+	// CollectedAt is when the inventory was collected.
+	CollectedAt time.Time `json:"collected_at"`
+}
+
+// BoardInfo describes the motherboard.
+type BoardInfo struct {
+	// Manufacturer is the board manufacturer.
+	Manufacturer string `json:"manufacturer"`
+	// Product is the board model.
+	Product string `json:"product"`
+	// Version is the board revision.
+	Version string `json:"version,omitempty"`
+}
+
+// BIOSInfo describes the system firmware.
+type BIOSInfo struct {
+	// Vendor is the firmware vendor.
+	Vendor string `json:"vendor"`
+	// Version is the firmware version.
+	Version string `json:"version"`
+	// Date is the firmware release date as reported (usually MM/DD/YYYY).
+	Date string `json:"date"`
+}
+
+// MemoryModule describes an installed memory module.
+type MemoryModule struct {
+	// Locator is the slot name (e.g., "DIMM_A1").
+	Locator string `json:"locator"`
+	// Bank is the bank name.
+	Bank string `json:"bank,omitempty"`
+	// SizeMB is the module size in MB.
+	SizeMB uint64 `json:"size_mb"`
+	// Type is the memory type (e.g., "DDR4").
+	Type string `json:"type"`
+	// SpeedMTs is the maximum rated speed in MT/s.
+	SpeedMTs uint32 `json:"speed_mts"`
+	// ConfiguredSpeedMTs is the configured speed in MT/s.
+	ConfiguredSpeedMTs uint32 `json:"configured_speed_mts,omitempty"`
+	// Manufacturer is the module manufacturer.
+	Manufacturer string `json:"manufacturer"`
+	// PartNumber is the module part number.
+	PartNumber string `json:"part_number"`
+	// Serial is the module serial number.
+	Serial string `json:"serial,omitempty"`
+}
+
+// DiskDevice describes a physical disk.
+type DiskDevice struct {
+	// Name is the device name (e.g., "nvme0n1" or "PhysicalDrive0").
+	Name string `json:"name"`
+	// Model is the disk model.
+	Model string `json:"model"`
+	// Serial is the disk serial number.
+	Serial string `json:"serial,omitempty"`
+	// SizeGB is the disk size in gigabytes.
+	SizeGB uint64 `json:"size_gb"`
+	// Rotational indicates a spinning disk.
+	Rotational bool `json:"rotational"`
+}
+
+// NetworkInterface describes a network interface.
+type NetworkInterface struct {
+	// Name is the interface name.
+	Name string `json:"name"`
+	// MAC is the hardware address.
+	MAC string `json:"mac"`
+	// MTU is the maximum transmission unit.
+	MTU int `json:"mtu"`
+	// Up indicates the interface is up.
+	Up bool `json:"up"`
+	// Addresses lists the interface addresses in CIDR notation.
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// Clone creates a deep copy of the SystemInfo.
+func (s *SystemInfo) Clone() *SystemInfo {
+	clone := *s
+	if s.MemoryModules != nil {
+		clone.MemoryModules = make([]MemoryModule, len(s.MemoryModules))
+		copy(clone.MemoryModules, s.MemoryModules)
+	}
+	if s.DiskDevices != nil {
+		clone.DiskDevices = make([]DiskDevice, len(s.DiskDevices))
+		copy(clone.DiskDevices, s.DiskDevices)
+	}
+	if s.NetworkInterfaces != nil {
+		clone.NetworkInterfaces = make([]NetworkInterface, len(s.NetworkInterfaces))
+		for i, nic := range s.NetworkInterfaces {
+			clone.NetworkInterfaces[i] = nic
+			if nic.Addresses != nil {
+				clone.NetworkInterfaces[i].Addresses = append([]string(nil), nic.Addresses...)
+			}
+		}
+	}
+	return &clone
 }
 
 // NewMetrics creates a new Metrics instance with the current timestamp.
@@ -202,12 +898,22 @@ func NewMetrics() *Metrics {
 // Clone creates a deep copy of the Metrics.
 func (m *Metrics) Clone() *Metrics {
 	clone := &Metrics{
-		Timestamp: m.Timestamp,
-		CPU:       m.CPU,
-		Memory:    m.Memory,
-		GPU:       m.GPU,
-		Disk:      m.Disk,
-		Network:   m.Network,
+		Timestamp:  m.Timestamp,
+		CPU:        m.CPU,
+		Memory:     m.Memory,
+		GPU:        m.GPU,
+		Disk:       m.Disk,
+		Network:    m.Network,
+		Power:      m.Power,
+		Throttling: m.Throttling,
+		Bottleneck: m.Bottleneck,
+		IntervalMs: m.IntervalMs,
+		Session:    m.Session,
+	}
+
+	if m.Event != nil {
+		event := *m.Event
+		clone.Event = &event
 	}
 
 	// Deep copy slices
@@ -224,6 +930,53 @@ func (m *Metrics) Clone() *Metrics {
 	if m.Network.Interfaces != nil {
 		clone.Network.Interfaces = make([]InterfaceInfo, len(m.Network.Interfaces))
 		copy(clone.Network.Interfaces, m.Network.Interfaces)
+		for i := range clone.Network.Interfaces {
+			if w := clone.Network.Interfaces[i].Wireless; w != nil {
+				copied := *w
+				clone.Network.Interfaces[i].Wireless = &copied
+			}
+		}
+	}
+
+	if m.Network.PingTargets != nil {
+		clone.Network.PingTargets = make([]PingTargetStats, len(m.Network.PingTargets))
+		copy(clone.Network.PingTargets, m.Network.PingTargets)
+		for i := range clone.Network.PingTargets {
+			if h := clone.Network.PingTargets[i].HTTP; h != nil {
+				copied := *h
+				clone.Network.PingTargets[i].HTTP = &copied
+			}
+		}
+	}
+
+	if m.CPU.PerCoreFrequencyMHz != nil {
+		clone.CPU.PerCoreFrequencyMHz = make([]uint32, len(m.CPU.PerCoreFrequencyMHz))
+		copy(clone.CPU.PerCoreFrequencyMHz, m.CPU.PerCoreFrequencyMHz)
+	}
+
+	if m.Throttling.Reasons != nil {
+		clone.Throttling.Reasons = make([]string, len(m.Throttling.Reasons))
+		copy(clone.Throttling.Reasons, m.Throttling.Reasons)
+	}
+
+	if m.Cgroups != nil {
+		clone.Cgroups = make([]CgroupStats, len(m.Cgroups))
+		copy(clone.Cgroups, m.Cgroups)
+	}
+
+	if m.Sensors != nil {
+		clone.Sensors = make([]SensorReading, len(m.Sensors))
+		copy(clone.Sensors, m.Sensors)
+	}
+
+	if m.Power.Batteries != nil {
+		clone.Power.Batteries = make([]BatteryInfo, len(m.Power.Batteries))
+		copy(clone.Power.Batteries, m.Power.Batteries)
+	}
+
+	if m.Network.GameServer != nil {
+		gameServer := *m.Network.GameServer
+		clone.Network.GameServer = &gameServer
 	}
 
 	if m.TopProcesses != nil {
@@ -231,5 +984,47 @@ func (m *Metrics) Clone() *Metrics {
 		copy(clone.TopProcesses, m.TopProcesses)
 	}
 
+	if m.TopGPUProcesses != nil {
+		clone.TopGPUProcesses = make([]ProcessInfo, len(m.TopGPUProcesses))
+		copy(clone.TopGPUProcesses, m.TopGPUProcesses)
+	}
+
+	if m.TopMemoryProcesses != nil {
+		clone.TopMemoryProcesses = make([]ProcessInfo, len(m.TopMemoryProcesses))
+		copy(clone.TopMemoryProcesses, m.TopMemoryProcesses)
+	}
+
+	if m.TopIOProcesses != nil {
+		clone.TopIOProcesses = make([]ProcessInfo, len(m.TopIOProcesses))
+		copy(clone.TopIOProcesses, m.TopIOProcesses)
+	}
+
+	if m.MemoryLeaks != nil {
+		clone.MemoryLeaks = make([]MemoryLeak, len(m.MemoryLeaks))
+		copy(clone.MemoryLeaks, m.MemoryLeaks)
+	}
+
+	if m.ProcessGroups != nil {
+		clone.ProcessGroups = make([]ProcessGroupInfo, len(m.ProcessGroups))
+		for i, g := range m.ProcessGroups {
+			clone.ProcessGroups[i] = g
+			if g.PIDs != nil {
+				clone.ProcessGroups[i].PIDs = make([]int32, len(g.PIDs))
+				copy(clone.ProcessGroups[i].PIDs, g.PIDs)
+			}
+		}
+	}
+
+	if m.WatchedProcesses != nil {
+		clone.WatchedProcesses = make([]WatchedProcessInfo, len(m.WatchedProcesses))
+		for i, w := range m.WatchedProcesses {
+			clone.WatchedProcesses[i] = w
+			if w.PIDs != nil {
+				clone.WatchedProcesses[i].PIDs = make([]int32, len(w.PIDs))
+				copy(clone.WatchedProcesses[i].PIDs, w.PIDs)
+			}
+		}
+	}
+
 	return clone
 }
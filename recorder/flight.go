@@ -0,0 +1,299 @@
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/logger"
+	"github.com/NaveLIL/erez-monitor/models"
+	"github.com/NaveLIL/erez-monitor/storage"
+)
+
+// flightFilePrefix starts the names of flight recorder dumps.
+const flightFilePrefix = "flight_"
+
+// ReasonManual is the reason of on-demand dumps.
+const ReasonManual = "manual"
+
+// HistorySource provides the snapshots and history to dump.
+type HistorySource interface {
+	Source
+	// GetHistory returns the metrics storage buffer.
+	GetHistory() *storage.RingBuffer
+	// GetProcessEvents returns recent process start/exit events.
+	GetProcessEvents() []*models.ProcessEvent
+}
+
+// FlightDump is the metrics around a trigger.
+type FlightDump struct {
+	// Reason is the alert type, or "manual" for on-demand dumps.
+	Reason string `json:"reason"`
+	// TriggeredAt is when the dump was triggered.
+	TriggeredAt time.Time `json:"triggered_at"`
+	// Before is the history requested before the trigger.
+	Before time.Duration `json:"before"`
+	// After is the recording time after the trigger.
+	After time.Duration `json:"after"`
+	// Alerts are the alerts fired while the dump was recording.
+	Alerts []*models.Alert `json:"alerts,omitempty"`
+	// SystemInfo describes the machine.
+	SystemInfo *models.SystemInfo `json:"system_info,omitempty"`
+	// Snapshots are the full snapshots around the trigger, oldest first.
+	Snapshots []*models.Metrics `json:"snapshots"`
+	// ProcessEvents are the process starts and exits during the dump.
+	ProcessEvents []*models.ProcessEvent `json:"process_events,omitempty"`
+
+	// Path is the file the dump was written to.
+	Path string `json:"-"`
+}
+
+// flightCapture is a dump recording its "after" part.
+type flightCapture struct {
+	dump      *FlightDump
+	ch        chan *models.Metrics
+	flush     chan struct{}
+	flushOnce sync.Once
+	done      chan struct{}
+}
+
+// FlightRecorder dumps the history before and the snapshots after a
+// trigger. Triggers while a dump is recording are merged into it, so a
+// burst of alerts produces a single file.
+type FlightRecorder struct {
+	cfg    *config.FlightRecorderConfig
+	dir    string
+	source HistorySource
+	log    *logger.Logger
+
+	mu      sync.Mutex
+	pending *flightCapture
+	onSaved func(dump *FlightDump)
+}
+
+// NewFlightRecorder creates a flight recorder writing dumps to dir.
+func NewFlightRecorder(cfg *config.FlightRecorderConfig, dir string, source HistorySource) *FlightRecorder {
+	return &FlightRecorder{
+		cfg:    cfg,
+		dir:    dir,
+		source: source,
+		log:    logger.Get(),
+	}
+}
+
+// SetOnSaved sets a function called after a dump is written.
+func (r *FlightRecorder) SetOnSaved(fn func(dump *FlightDump)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onSaved = fn
+}
+
+// HandleAlert starts a dump for an alert when enabled for its type. It can
+// be registered as an alert handler.
+func (r *FlightRecorder) HandleAlert(alert *models.Alert) {
+	if !r.cfg.Enabled || !r.triggersOn(alert.Type) {
+		return
+	}
+	r.Trigger(string(alert.Type), alert)
+}
+
+// triggersOn reports whether alerts of the type trigger a dump.
+func (r *FlightRecorder) triggersOn(alertType models.AlertType) bool {
+	if len(r.cfg.AlertTypes) == 0 {
+		return true
+	}
+	for _, t := range r.cfg.AlertTypes {
+		if strings.EqualFold(t, string(alertType)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Trigger starts a dump with the given reason; alert may be nil. The
+// history before the trigger is copied immediately and the file is written
+// once the "after" period has been recorded. It reports whether a new dump
+// was started (false when merged into a recording one).
+func (r *FlightRecorder) Trigger(reason string, alert *models.Alert) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c := r.pending; c != nil {
+		if alert != nil {
+			c.dump.Alerts = append(c.dump.Alerts, alert)
+		}
+		return false
+	}
+
+	dump := &FlightDump{
+		Reason:      reason,
+		TriggeredAt: time.Now(),
+		Before:      r.cfg.Before,
+		After:       r.cfg.After,
+	}
+	if alert != nil {
+		dump.Alerts = []*models.Alert{alert}
+		dump.TriggeredAt = alert.Timestamp
+	}
+
+	c := &flightCapture{
+		dump:  dump,
+		ch:    make(chan *models.Metrics, subscribeBuffer),
+		flush: make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	// Subscribe before copying the history so no snapshot falls in between;
+	// capture skips the ones already copied
+	r.source.Subscribe(c.ch)
+	if r.cfg.Before > 0 {
+		dump.Snapshots = r.source.GetHistory().GetWindow(r.cfg.Before)
+	}
+	r.pending = c
+	go r.capture(c)
+
+	r.log.Infof("Flight recorder triggered (%s), recording %v more", reason, r.cfg.After)
+	return true
+}
+
+// capture records snapshots until the "after" period ends or Flush is
+// called, then writes the dump.
+func (r *FlightRecorder) capture(c *flightCapture) {
+	defer close(c.done)
+
+	var last time.Time
+	if n := len(c.dump.Snapshots); n > 0 {
+		last = c.dump.Snapshots[n-1].Timestamp
+	}
+	add := func(m *models.Metrics) {
+		// The history may already contain the first published snapshots
+		if m.Timestamp.After(last) {
+			c.dump.Snapshots = append(c.dump.Snapshots, m.Clone())
+			last = m.Timestamp
+		}
+	}
+
+	timer := time.NewTimer(c.dump.After)
+	defer timer.Stop()
+
+loop:
+	for {
+		select {
+		case m := <-c.ch:
+			add(m)
+		case <-timer.C:
+			break loop
+		case <-c.flush:
+			break loop
+		}
+	}
+	r.source.Unsubscribe(c.ch)
+
+	// Keep the snapshots published before unsubscribing
+	for len(c.ch) > 0 {
+		add(<-c.ch)
+	}
+
+	// Later triggers start a new dump from here on
+	r.mu.Lock()
+	r.pending = nil
+	onSaved := r.onSaved
+	r.mu.Unlock()
+
+	dump := c.dump
+	dump.SystemInfo = r.source.GetSystemInfo()
+	dump.ProcessEvents = eventsWithin(r.source.GetProcessEvents(), dump.Snapshots)
+
+	if err := r.save(dump); err != nil {
+		r.log.Errorf("Failed to write flight recorder dump: %v", err)
+		return
+	}
+	r.log.Infof("Flight recorder dump saved to: %s (%d snapshots)", dump.Path, len(dump.Snapshots))
+	if onSaved != nil {
+		onSaved(dump)
+	}
+}
+
+// Flush ends a recording dump early and waits until it is written, e.g. on
+// shutdown.
+func (r *FlightRecorder) Flush() {
+	r.mu.Lock()
+	c := r.pending
+	r.mu.Unlock()
+
+	if c == nil {
+		return
+	}
+	c.flushOnce.Do(func() { close(c.flush) })
+	<-c.done
+}
+
+// eventsWithin returns the process events between the first and last snapshot.
+func eventsWithin(events []*models.ProcessEvent, snapshots []*models.Metrics) []*models.ProcessEvent {
+	if len(snapshots) == 0 {
+		return nil
+	}
+	from := snapshots[0].Timestamp.Add(-time.Duration(snapshots[0].IntervalMs * float64(time.Millisecond)))
+	to := snapshots[len(snapshots)-1].Timestamp
+
+	var result []*models.ProcessEvent
+	for _, e := range events {
+		if e.Timestamp.After(from) && !e.Timestamp.After(to) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// save writes the dump as JSON and deletes the oldest dumps beyond MaxFiles.
+func (r *FlightRecorder) save(dump *FlightDump) error {
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+
+	// Milliseconds and the reason tell dumps apart; a counter handles the rest
+	base := fmt.Sprintf("%s%s_%s", flightFilePrefix, dump.TriggeredAt.Format("2006-01-02_15-04-05.000"), fileName(dump.Reason))
+	dump.Path = filepath.Join(r.dir, base+".json")
+	for n := 2; ; n++ {
+		if _, err := os.Stat(dump.Path); os.IsNotExist(err) {
+			break
+		}
+		dump.Path = filepath.Join(r.dir, fmt.Sprintf("%s_%d.json", base, n))
+	}
+
+	err := writeFile(dump.Path, func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(dump)
+	})
+	if err != nil {
+		return err
+	}
+
+	if r.cfg.MaxFiles > 0 {
+		r.prune()
+	}
+	return nil
+}
+
+// prune deletes the oldest dumps beyond MaxFiles. Names start with the
+// trigger time, so they sort chronologically.
+func (r *FlightRecorder) prune() {
+	matches, err := filepath.Glob(filepath.Join(r.dir, flightFilePrefix+"*.json"))
+	if err != nil || len(matches) <= r.cfg.MaxFiles {
+		return
+	}
+
+	sort.Strings(matches)
+	for _, path := range matches[:len(matches)-r.cfg.MaxFiles] {
+		if err := os.Remove(path); err != nil {
+			r.log.Warnf("Failed to delete old flight recorder dump: %v", err)
+		}
+	}
+}
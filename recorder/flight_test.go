@@ -0,0 +1,186 @@
+package recorder
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+	"github.com/NaveLIL/erez-monitor/storage"
+)
+
+// fakeHistory is a fakeSource with history and process events.
+type fakeHistory struct {
+	fakeSource
+	history *storage.RingBuffer
+	events  []*models.ProcessEvent
+}
+
+func (f *fakeHistory) GetHistory() *storage.RingBuffer {
+	return f.history
+}
+
+func (f *fakeHistory) GetProcessEvents() []*models.ProcessEvent {
+	return f.events
+}
+
+// newFakeHistory returns a source with the first n of the snapshots in its history.
+func newFakeHistory(snapshots []*models.Metrics, n int) *fakeHistory {
+	f := &fakeHistory{history: storage.NewRingBuffer(100)}
+	for _, m := range snapshots[:n] {
+		f.history.Add(m)
+	}
+	return f
+}
+
+func TestFlightRecorder(t *testing.T) {
+	snapshots := sessionSnapshots(12)
+	source := newFakeHistory(snapshots, 10)
+	source.events = []*models.ProcessEvent{
+		{Type: models.ProcessEventStart, Name: "old.exe", Timestamp: snapshots[0].Timestamp},
+		{Type: models.ProcessEventStart, Name: "spike.exe", Timestamp: snapshots[10].Timestamp},
+	}
+
+	cfg := &config.FlightRecorderConfig{Enabled: true, Before: 5 * time.Second, After: time.Hour}
+	r := NewFlightRecorder(cfg, t.TempDir(), source)
+	saved := make(chan *FlightDump, 1)
+	r.SetOnSaved(func(dump *FlightDump) { saved <- dump })
+
+	cpu := &models.Alert{Type: models.AlertTypeCPU, Timestamp: snapshots[9].Timestamp}
+	if !r.Trigger("cpu", cpu) {
+		t.Fatal("Expected a new dump")
+	}
+	// A second alert is merged into the recording dump
+	r.HandleAlert(&models.Alert{Type: models.AlertTypeRAM, Timestamp: snapshots[9].Timestamp})
+
+	// The last history snapshot is published again and skipped
+	for _, m := range snapshots[9:] {
+		source.publish(m)
+	}
+	r.Flush()
+
+	var dump *FlightDump
+	select {
+	case dump = <-saved:
+	default:
+		t.Fatal("Expected the dump to be saved on flush")
+	}
+
+	// 5 s of history (5..9) and two snapshots after the trigger
+	if len(dump.Snapshots) != 7 || !dump.Snapshots[0].Timestamp.Equal(snapshots[5].Timestamp) {
+		t.Fatalf("Unexpected snapshots: %d from %v", len(dump.Snapshots), dump.Snapshots[0].Timestamp)
+	}
+	if len(dump.Alerts) != 2 || dump.Reason != "cpu" || !dump.TriggeredAt.Equal(cpu.Timestamp) {
+		t.Errorf("Unexpected dump: %+v", dump)
+	}
+	if len(dump.ProcessEvents) != 1 || dump.ProcessEvents[0].Name != "spike.exe" {
+		t.Errorf("Expected the events within the dump, got %+v", dump.ProcessEvents)
+	}
+	if len(source.subscribers) != 0 {
+		t.Error("Expected the recorder to unsubscribe")
+	}
+
+	data, err := os.ReadFile(dump.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var loaded FlightDump
+	if err := json.Unmarshal(data, &loaded); err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Snapshots) != 7 || filepath.Base(dump.Path) != "flight_2024-03-01_20-00-09.000_cpu.json" {
+		t.Errorf("Unexpected file %s with %d snapshots", dump.Path, len(loaded.Snapshots))
+	}
+
+	// A new trigger starts a new dump
+	if !r.Trigger(ReasonManual, nil) {
+		t.Error("Expected a new dump after the previous one was saved")
+	}
+	r.Flush()
+}
+
+func TestFlightRecorderAlertTypes(t *testing.T) {
+	source := newFakeHistory(sessionSnapshots(1), 1)
+	cfg := &config.FlightRecorderConfig{Enabled: true, After: time.Hour, AlertTypes: []string{"Throttling"}}
+	r := NewFlightRecorder(cfg, t.TempDir(), source)
+
+	r.HandleAlert(&models.Alert{Type: models.AlertTypeCPU})
+	if len(source.subscribers) != 0 {
+		t.Fatal("Expected CPU alerts to be ignored")
+	}
+	r.HandleAlert(&models.Alert{Type: models.AlertTypeThrottling})
+	if len(source.subscribers) != 1 {
+		t.Fatal("Expected throttling alerts to trigger a dump")
+	}
+	r.Flush()
+
+	cfg.Enabled = false
+	r.HandleAlert(&models.Alert{Type: models.AlertTypeThrottling})
+	if len(source.subscribers) != 0 {
+		t.Error("Expected no dump when disabled")
+	}
+}
+
+func TestFlightRecorderPrune(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"flight_2024-01-01_00-00-00_cpu.json", "flight_2024-01-02_00-00-00_ram.json", "notes.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	source := newFakeHistory(sessionSnapshots(3), 3)
+	r := NewFlightRecorder(&config.FlightRecorderConfig{Before: time.Minute, MaxFiles: 2}, dir, source)
+	saved := make(chan *FlightDump, 1)
+	r.SetOnSaved(func(dump *FlightDump) { saved <- dump })
+
+	// Without an "after" period the dump is written right away
+	r.Trigger(ReasonManual, nil)
+	select {
+	case dump := <-saved:
+		if len(dump.Snapshots) != 3 {
+			t.Errorf("Expected the whole history, got %d snapshots", len(dump.Snapshots))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timed out waiting for the dump")
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(matches) != 3 {
+		t.Fatalf("Expected two dumps and the unrelated file, got %v", matches)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "flight_2024-01-01_00-00-00_cpu.json")); !os.IsNotExist(err) {
+		t.Error("Expected the oldest dump to be deleted")
+	}
+}
+
+func TestFlightRecorderFileNames(t *testing.T) {
+	dir := t.TempDir()
+	r := NewFlightRecorder(&config.FlightRecorderConfig{}, dir, newFakeHistory(sessionSnapshots(1), 1))
+	at := time.Date(2024, 3, 1, 20, 0, 9, 250*int(time.Millisecond), time.Local)
+
+	// Two alerts in the same millisecond, a manual dump and a later alert
+	dumps := []*FlightDump{
+		{Reason: "cpu", TriggeredAt: at},
+		{Reason: "cpu", TriggeredAt: at},
+		{Reason: ReasonManual, TriggeredAt: at},
+		{Reason: "cpu", TriggeredAt: at.Add(time.Millisecond)},
+	}
+	want := []string{
+		"flight_2024-03-01_20-00-09.250_cpu.json",
+		"flight_2024-03-01_20-00-09.250_cpu_2.json",
+		"flight_2024-03-01_20-00-09.250_manual.json",
+		"flight_2024-03-01_20-00-09.251_cpu.json",
+	}
+	for i, dump := range dumps {
+		if err := r.save(dump); err != nil {
+			t.Fatal(err)
+		}
+		if name := filepath.Base(dump.Path); name != want[i] {
+			t.Errorf("Dump %d: expected %s, got %s", i, want[i], name)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package recorder records metrics snapshots at full resolution for
+// benchmark sessions and flight recorder dumps, independent of the history
+// buffer.
+package recorder
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/logger"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// subscribeBuffer is the capacity of the session's subscription channel.
+// The collector drops snapshots for full channels, so it must absorb a
+// slow disk or a busy system.
+const subscribeBuffer = 64
+
+var (
+	// ErrSessionActive is returned when starting a session while one is recording.
+	ErrSessionActive = errors.New("a benchmark session is already recording")
+	// ErrNoSession is returned when stopping without an active session.
+	ErrNoSession = errors.New("no benchmark session is recording")
+)
+
+// Source provides the snapshots to record.
+type Source interface {
+	// Subscribe adds a channel to receive metrics updates.
+	Subscribe(ch chan<- *models.Metrics)
+	// Unsubscribe removes a channel from receiving updates.
+	Unsubscribe(ch chan<- *models.Metrics)
+	// GetSystemInfo returns static system information.
+	GetSystemInfo() *models.SystemInfo
+}
+
+// session is an active recording.
+type session struct {
+	name      string
+	started   time.Time
+	ch        chan *models.Metrics
+	done      chan struct{}
+	snapshots []*models.Metrics
+	truncated bool
+}
+
+// SessionRecorder records benchmark sessions. Only one session records at
+// a time; it can be started and stopped from the tray, a hotkey or code.
+type SessionRecorder struct {
+	cfg    *config.BenchmarkConfig
+	alerts *config.AlertsConfig
+	dir    string
+	source Source
+	log    *logger.Logger
+
+	mu      sync.Mutex
+	current *session
+}
+
+// NewSessionRecorder creates a recorder writing summaries to dir.
+func NewSessionRecorder(cfg *config.BenchmarkConfig, alerts *config.AlertsConfig, dir string, source Source) *SessionRecorder {
+	return &SessionRecorder{
+		cfg:    cfg,
+		alerts: alerts,
+		dir:    dir,
+		source: source,
+		log:    logger.Get(),
+	}
+}
+
+// Start begins recording a session. An empty name defaults to "benchmark".
+func (r *SessionRecorder) Start(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.current != nil {
+		return ErrSessionActive
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "benchmark"
+	}
+
+	s := &session{
+		name:    name,
+		started: time.Now(),
+		ch:      make(chan *models.Metrics, subscribeBuffer),
+		done:    make(chan struct{}),
+	}
+	r.current = s
+	go r.record(s)
+	r.source.Subscribe(s.ch)
+
+	r.log.Infof("Benchmark session %q started", name)
+	return nil
+}
+
+// record stores the snapshots of a session until its channel is closed.
+func (r *SessionRecorder) record(s *session) {
+	defer close(s.done)
+
+	for m := range s.ch {
+		if r.cfg.MaxSamples > 0 && len(s.snapshots) >= r.cfg.MaxSamples {
+			if !s.truncated {
+				r.log.Warnf("Benchmark session %q reached %d samples, ignoring the rest", s.name, r.cfg.MaxSamples)
+				s.truncated = true
+			}
+			continue
+		}
+		snapshot := m.Clone()
+		snapshot.Session = s.name
+		s.snapshots = append(s.snapshots, snapshot)
+	}
+}
+
+// Stop ends the active session, writes its summary and returns it. The
+// summary is returned even when writing the files fails.
+func (r *SessionRecorder) Stop() (*Summary, error) {
+	r.mu.Lock()
+	s := r.current
+	r.current = nil
+	r.mu.Unlock()
+
+	if s == nil {
+		return nil, ErrNoSession
+	}
+
+	// No send is in progress once Unsubscribe returns
+	r.source.Unsubscribe(s.ch)
+	close(s.ch)
+	<-s.done
+
+	summary := Summarize(s.name, s.snapshots, r.alerts)
+	summary.Truncated = s.truncated
+	summary.System = r.source.GetSystemInfo()
+	r.log.Infof("Benchmark session %q stopped after %d samples", s.name, len(s.snapshots))
+
+	return summary, r.save(summary, s)
+}
+
+// Toggle starts a session with the default name or stops the active one.
+// The summary is nil when a session was started.
+func (r *SessionRecorder) Toggle() (*Summary, error) {
+	if r.Active() {
+		return r.Stop()
+	}
+	return nil, r.Start("")
+}
+
+// Active reports whether a session is recording.
+func (r *SessionRecorder) Active() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current != nil
+}
+
+// save writes the summary as JSON and Markdown, and the snapshots as CSV
+// when enabled. Files are named after the session and its start time so
+// repeated runs with the same name are kept side by side.
+func (r *SessionRecorder) save(summary *Summary, s *session) error {
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create benchmark directory: %w", err)
+	}
+
+	base := filepath.Join(r.dir, fmt.Sprintf("%s_%s", fileName(s.name), s.started.Format("2006-01-02_15-04-05")))
+	summary.JSONPath = base + ".json"
+	summary.MarkdownPath = base + ".md"
+
+	if err := writeFile(summary.JSONPath, summary.WriteJSON); err != nil {
+		return fmt.Errorf("failed to write benchmark summary: %w", err)
+	}
+	if err := writeFile(summary.MarkdownPath, summary.WriteMarkdown); err != nil {
+		return fmt.Errorf("failed to write benchmark report: %w", err)
+	}
+
+	if r.cfg.SaveSamples && len(s.snapshots) > 0 {
+		summary.SamplesPath = base + "_samples.csv"
+		if err := r.log.ExportMetricsCSV(summary.SamplesPath, s.snapshots); err != nil {
+			return fmt.Errorf("failed to write benchmark samples: %w", err)
+		}
+	}
+	return nil
+}
+
+// writeFile creates path and writes it with write.
+func writeFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// fileName replaces characters that are not safe in file names.
+func fileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, name)
+}
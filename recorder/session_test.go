@@ -0,0 +1,135 @@
+package recorder
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// fakeSource delivers snapshots to its subscribers synchronously.
+type fakeSource struct {
+	mu          sync.Mutex
+	subscribers []chan<- *models.Metrics
+}
+
+func (f *fakeSource) Subscribe(ch chan<- *models.Metrics) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.subscribers = append(f.subscribers, ch)
+}
+
+func (f *fakeSource) Unsubscribe(ch chan<- *models.Metrics) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, sub := range f.subscribers {
+		if sub == ch {
+			f.subscribers = append(f.subscribers[:i], f.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (f *fakeSource) GetSystemInfo() *models.SystemInfo {
+	return &models.SystemInfo{Hostname: "rig"}
+}
+
+func (f *fakeSource) publish(m *models.Metrics) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ch := range f.subscribers {
+		ch <- m
+	}
+}
+
+func TestSessionRecorder(t *testing.T) {
+	dir := t.TempDir()
+	source := &fakeSource{}
+	cfg := &config.BenchmarkConfig{SaveSamples: true}
+	r := NewSessionRecorder(cfg, &config.AlertsConfig{}, dir, source)
+
+	if _, err := r.Stop(); !errors.Is(err, ErrNoSession) {
+		t.Errorf("Expected ErrNoSession, got %v", err)
+	}
+
+	if err := r.Start("before/after: 551.86"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Start("again"); !errors.Is(err, ErrSessionActive) {
+		t.Errorf("Expected ErrSessionActive, got %v", err)
+	}
+	if !r.Active() {
+		t.Fatal("Expected an active session")
+	}
+
+	snapshots := sessionSnapshots(5)
+	for _, m := range snapshots {
+		source.publish(m)
+	}
+
+	summary, err := r.Stop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Active() || len(source.subscribers) != 0 {
+		t.Error("Expected the session to unsubscribe on stop")
+	}
+	if summary.Session != "before/after: 551.86" || summary.Samples != 5 || summary.System.Hostname != "rig" {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	// Published snapshots are not modified
+	if snapshots[0].Session != "" {
+		t.Error("Expected the recorder to tag copies only")
+	}
+
+	// The file name is sanitized
+	if base := filepath.Base(summary.JSONPath); !strings.HasPrefix(base, "before_after__551.86_") {
+		t.Errorf("Unexpected file name: %s", base)
+	}
+
+	data, err := os.ReadFile(summary.JSONPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved Summary
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Samples != 5 || len(saved.Metrics) == 0 {
+		t.Errorf("Unexpected saved summary: %+v", saved)
+	}
+	for _, path := range []string{summary.MarkdownPath, summary.SamplesPath} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be written: %v", path, err)
+		}
+	}
+}
+
+func TestSessionRecorderMaxSamples(t *testing.T) {
+	source := &fakeSource{}
+	r := NewSessionRecorder(&config.BenchmarkConfig{MaxSamples: 3}, nil, t.TempDir(), source)
+
+	if _, err := r.Toggle(); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range sessionSnapshots(5) {
+		source.publish(m)
+	}
+
+	summary, err := r.Toggle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Session != "benchmark" || summary.Samples != 3 || !summary.Truncated {
+		t.Errorf("Unexpected summary: %+v", summary)
+	}
+	if summary.SamplesPath != "" {
+		t.Error("Expected no samples file without save_samples")
+	}
+}
@@ -0,0 +1,372 @@
+package recorder
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+	"github.com/NaveLIL/erez-monitor/storage"
+)
+
+// topProcessLimit is the number of processes listed in a summary.
+const topProcessLimit = 10
+
+// MetricSummary is the distribution of one metric over a session.
+type MetricSummary struct {
+	// Name is the metric name.
+	Name string `json:"name"`
+	// Unit is the metric unit.
+	Unit string `json:"unit"`
+	// Samples is the number of snapshots with a value.
+	Samples int `json:"samples"`
+	// Avg is the time-weighted average.
+	Avg float64 `json:"avg"`
+	// Min is the lowest value.
+	Min float64 `json:"min"`
+	// Max is the highest value.
+	Max float64 `json:"max"`
+	// P95 is the 95th percentile.
+	P95 float64 `json:"p95"`
+	// P99 is the 99th percentile.
+	P99 float64 `json:"p99"`
+}
+
+// ThresholdSummary is the time a metric spent above its alert threshold.
+type ThresholdSummary struct {
+	// Metric is the metric name.
+	Metric string `json:"metric"`
+	// Threshold is the alert threshold (0 for boolean states such as throttling).
+	Threshold float64 `json:"threshold"`
+	// Seconds is the time above the threshold.
+	Seconds float64 `json:"seconds"`
+	// Percent is the share of the session above the threshold.
+	Percent float64 `json:"percent"`
+}
+
+// ProcessSummary aggregates a process over the snapshots it appeared in the
+// top lists.
+type ProcessSummary struct {
+	// Name is the process name (processes with the same name are combined).
+	Name string `json:"name"`
+	// Samples is the number of snapshots the process was listed in.
+	Samples int `json:"samples"`
+	// AvgCPUPercent is the CPU usage averaged over the whole session.
+	AvgCPUPercent float64 `json:"avg_cpu_percent"`
+	// MaxCPUPercent is the peak CPU usage.
+	MaxCPUPercent float64 `json:"max_cpu_percent"`
+	// MaxMemoryMB is the peak memory usage.
+	MaxMemoryMB uint64 `json:"max_memory_mb"`
+	// MaxGPUPercent is the peak GPU usage (when per-process GPU data is available).
+	MaxGPUPercent float64 `json:"max_gpu_percent,omitempty"`
+}
+
+// Summary is the result of a benchmark session.
+type Summary struct {
+	// Session is the session name.
+	Session string `json:"session"`
+	// Start is the timestamp of the first snapshot.
+	Start time.Time `json:"start"`
+	// End is the timestamp of the last snapshot.
+	End time.Time `json:"end"`
+	// DurationSec is the time covered by the snapshots.
+	DurationSec float64 `json:"duration_sec"`
+	// Samples is the number of recorded snapshots.
+	Samples int `json:"samples"`
+	// Truncated is set when the session hit the sample limit.
+	Truncated bool `json:"truncated,omitempty"`
+	// System describes the machine the session was recorded on.
+	System *models.SystemInfo `json:"system,omitempty"`
+	// GPUName is the GPU the metrics were read from.
+	GPUName string `json:"gpu_name,omitempty"`
+	// Metrics are the per-metric distributions.
+	Metrics []MetricSummary `json:"metrics"`
+	// Thresholds are the times spent above the alert thresholds.
+	Thresholds []ThresholdSummary `json:"thresholds,omitempty"`
+	// TopProcesses are the busiest processes, highest average CPU first.
+	TopProcesses []ProcessSummary `json:"top_processes,omitempty"`
+
+	// JSONPath, MarkdownPath and SamplesPath are the files written on stop.
+	JSONPath     string `json:"-"`
+	MarkdownPath string `json:"-"`
+	SamplesPath  string `json:"-"`
+}
+
+// metricDef extracts one summarized metric from a snapshot. ok is false
+// when the snapshot has no value (e.g. no temperature sensor).
+type metricDef struct {
+	name  string
+	unit  string
+	value func(m *models.Metrics) (v float64, ok bool)
+}
+
+// positive reports values that are only meaningful when non-zero.
+func positive(v float64) (float64, bool) {
+	return v, v > 0
+}
+
+var metricDefs = []metricDef{
+	{"CPU usage", "%", func(m *models.Metrics) (float64, bool) { return m.CPU.UsagePercent, true }},
+	{"CPU temperature", "°C", func(m *models.Metrics) (float64, bool) { return positive(m.CPU.Temperature) }},
+	{"CPU frequency", "MHz", func(m *models.Metrics) (float64, bool) { return positive(float64(m.CPU.FrequencyMHz)) }},
+	{"CPU package power", "W", func(m *models.Metrics) (float64, bool) { return positive(m.CPU.PackagePowerWatts) }},
+	{"RAM usage", "%", func(m *models.Metrics) (float64, bool) { return m.Memory.UsedPercent, true }},
+	{"RAM used", "MB", func(m *models.Metrics) (float64, bool) { return float64(m.Memory.UsedMB), true }},
+	{"GPU usage", "%", func(m *models.Metrics) (float64, bool) { return m.GPU.UsagePercent, m.GPU.Available }},
+	{"GPU temperature", "°C", func(m *models.Metrics) (float64, bool) {
+		return float64(m.GPU.TemperatureC), m.GPU.Available && m.GPU.TemperatureC > 0
+	}},
+	{"GPU clock", "MHz", func(m *models.Metrics) (float64, bool) {
+		return float64(m.GPU.ClockMHz), m.GPU.Available && m.GPU.ClockMHz > 0
+	}},
+	{"VRAM used", "MB", func(m *models.Metrics) (float64, bool) { return float64(m.GPU.VRAMUsedMB), m.GPU.Available }},
+	{"GPU power", "W", func(m *models.Metrics) (float64, bool) {
+		return m.GPU.PowerWatts, m.GPU.Available && m.GPU.PowerWatts > 0
+	}},
+	{"Disk read", "MB/s", func(m *models.Metrics) (float64, bool) { return m.Disk.ReadMBps, true }},
+	{"Disk write", "MB/s", func(m *models.Metrics) (float64, bool) { return m.Disk.WriteMBps, true }},
+	{"Network download", "KB/s", func(m *models.Metrics) (float64, bool) { return m.Network.DownloadKBps, true }},
+	{"Network upload", "KB/s", func(m *models.Metrics) (float64, bool) { return m.Network.UploadKBps, true }},
+	{"Ping", "ms", func(m *models.Metrics) (float64, bool) { return positive(m.Network.PingMs) }},
+}
+
+// thresholdDef checks one alert threshold against a snapshot.
+type thresholdDef struct {
+	metric    string
+	threshold func(a *config.AlertsConfig) float64
+	above     func(m *models.Metrics, threshold float64) bool
+}
+
+var thresholdDefs = []thresholdDef{
+	{"CPU usage", func(a *config.AlertsConfig) float64 { return a.CPUThreshold },
+		func(m *models.Metrics, t float64) bool { return m.CPU.UsagePercent > t }},
+	{"RAM usage", func(a *config.AlertsConfig) float64 { return a.RAMThreshold },
+		func(m *models.Metrics, t float64) bool { return m.Memory.UsedPercent > t }},
+	{"GPU usage", func(a *config.AlertsConfig) float64 { return a.GPUThreshold },
+		func(m *models.Metrics, t float64) bool { return m.GPU.Available && m.GPU.UsagePercent > t }},
+	{"GPU temperature", func(a *config.AlertsConfig) float64 { return a.GPUTempThreshold },
+		func(m *models.Metrics, t float64) bool { return m.GPU.Available && float64(m.GPU.TemperatureC) > t }},
+	{"Ping", func(a *config.AlertsConfig) float64 { return a.PingThresholdMs },
+		func(m *models.Metrics, t float64) bool { return m.Network.PingMs > t }},
+}
+
+// Summarize computes the summary of the recorded snapshots. Averages and
+// times above thresholds are weighted by the interval each snapshot covers,
+// so they stay correct with adaptive sampling. alerts may be nil.
+func Summarize(session string, snapshots []*models.Metrics, alerts *config.AlertsConfig) *Summary {
+	summary := &Summary{Session: session, Samples: len(snapshots)}
+	if len(snapshots) == 0 {
+		return summary
+	}
+
+	summary.Start = snapshots[0].Timestamp
+	summary.End = snapshots[len(snapshots)-1].Timestamp
+	for _, m := range snapshots {
+		if m.GPU.Name != "" {
+			summary.GPUName = m.GPU.Name
+			break
+		}
+	}
+
+	weights := storage.SampleWeights(snapshots)
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	summary.DurationSec = total / 1000
+
+	for _, def := range metricDefs {
+		if ms, ok := summarizeMetric(def, snapshots, weights); ok {
+			summary.Metrics = append(summary.Metrics, ms)
+		}
+	}
+
+	// Throttling is a state rather than a value, listed with the thresholds
+	throttled := thresholdDef{"Throttling", nil, func(m *models.Metrics, _ float64) bool { return m.Throttling.Throttled }}
+	summary.Thresholds = append(summary.Thresholds, timeAbove(throttled, 0, snapshots, weights, total))
+	if alerts != nil {
+		for _, def := range thresholdDefs {
+			if t := def.threshold(alerts); t > 0 {
+				summary.Thresholds = append(summary.Thresholds, timeAbove(def, t, snapshots, weights, total))
+			}
+		}
+	}
+
+	summary.TopProcesses = summarizeProcesses(snapshots, weights, total)
+	return summary
+}
+
+// summarizeMetric computes the distribution of one metric. ok is false when
+// no snapshot has a value.
+func summarizeMetric(def metricDef, snapshots []*models.Metrics, weights []float64) (MetricSummary, bool) {
+	ms := MetricSummary{Name: def.name, Unit: def.unit}
+	var values []float64
+	var sum, weight float64
+	for i, m := range snapshots {
+		v, ok := def.value(m)
+		if !ok {
+			continue
+		}
+		values = append(values, v)
+		sum += v * weights[i]
+		weight += weights[i]
+	}
+	if len(values) == 0 {
+		return ms, false
+	}
+
+	sort.Float64s(values)
+	ms.Samples = len(values)
+	ms.Avg = sum / weight
+	ms.Min = values[0]
+	ms.Max = values[len(values)-1]
+	ms.P95 = percentile(values, 95)
+	ms.P99 = percentile(values, 99)
+	return ms, true
+}
+
+// timeAbove sums the intervals of the snapshots above the threshold.
+func timeAbove(def thresholdDef, threshold float64, snapshots []*models.Metrics, weights []float64, total float64) ThresholdSummary {
+	ts := ThresholdSummary{Metric: def.metric, Threshold: threshold}
+	var above float64
+	for i, m := range snapshots {
+		if def.above(m, threshold) {
+			above += weights[i]
+		}
+	}
+	ts.Seconds = above / 1000
+	if total > 0 {
+		ts.Percent = above / total * 100
+	}
+	return ts
+}
+
+// summarizeProcesses combines the top process lists by process name and
+// returns the processes with the highest average CPU usage.
+func summarizeProcesses(snapshots []*models.Metrics, weights []float64, total float64) []ProcessSummary {
+	byName := make(map[string]*ProcessSummary)
+	cpuTime := make(map[string]float64)
+	get := func(name string) *ProcessSummary {
+		p, ok := byName[name]
+		if !ok {
+			p = &ProcessSummary{Name: name}
+			byName[name] = p
+		}
+		return p
+	}
+
+	for i, m := range snapshots {
+		// Several processes may share a name; sum them per snapshot
+		seen := make(map[string]float64)
+		for _, proc := range m.TopProcesses {
+			p := get(proc.Name)
+			seen[proc.Name] += proc.CPUPercent
+			p.MaxMemoryMB = max(p.MaxMemoryMB, proc.MemoryMB)
+		}
+		for name, cpu := range seen {
+			p := byName[name]
+			p.Samples++
+			p.MaxCPUPercent = max(p.MaxCPUPercent, cpu)
+			cpuTime[name] += cpu * weights[i]
+		}
+		for _, proc := range m.TopGPUProcesses {
+			p := get(proc.Name)
+			p.MaxGPUPercent = max(p.MaxGPUPercent, proc.GPUPercent)
+		}
+	}
+
+	result := make([]ProcessSummary, 0, len(byName))
+	for name, p := range byName {
+		if total > 0 {
+			p.AvgCPUPercent = cpuTime[name] / total
+		}
+		result = append(result, *p)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].AvgCPUPercent != result[j].AvgCPUPercent {
+			return result[i].AvgCPUPercent > result[j].AvgCPUPercent
+		}
+		return result[i].Name < result[j].Name
+	})
+	if len(result) > topProcessLimit {
+		result = result[:topProcessLimit]
+	}
+	return result
+}
+
+// percentile returns the nearest-rank percentile of sorted values.
+func percentile(sorted []float64, p float64) float64 {
+	// The epsilon keeps e.g. 99% of 100 at rank 99 despite rounding
+	rank := int(math.Ceil(p/100*float64(len(sorted)) - 1e-9))
+	rank = max(1, min(rank, len(sorted)))
+	return sorted[rank-1]
+}
+
+// WriteJSON writes the summary as indented JSON.
+func (s *Summary) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// WriteMarkdown writes the summary as a Markdown report.
+func (s *Summary) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Benchmark: %s\n\n", s.Session)
+	fmt.Fprintf(&b, "- Start: %s\n", s.Start.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "- End: %s\n", s.End.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "- Duration: %s\n", (time.Duration(s.DurationSec * float64(time.Second))).Round(time.Second))
+	fmt.Fprintf(&b, "- Samples: %d", s.Samples)
+	if s.Truncated {
+		b.WriteString(" (sample limit reached, the end of the session is missing)")
+	}
+	b.WriteString("\n")
+	if s.System != nil {
+		fmt.Fprintf(&b, "- System: %s, %s (%d cores / %d threads)\n",
+			s.System.Hostname, s.System.CPUModel, s.System.CPUCores, s.System.CPUThreads)
+		fmt.Fprintf(&b, "- OS: %s %s\n", s.System.Platform, s.System.KernelVersion)
+	}
+	if s.GPUName != "" {
+		fmt.Fprintf(&b, "- GPU: %s\n", s.GPUName)
+	}
+
+	b.WriteString("\n## Metrics\n\n")
+	b.WriteString("| Metric | Unit | Avg | Min | Max | P95 | P99 |\n")
+	b.WriteString("|---|---|---:|---:|---:|---:|---:|\n")
+	for _, m := range s.Metrics {
+		fmt.Fprintf(&b, "| %s | %s | %.1f | %.1f | %.1f | %.1f | %.1f |\n",
+			m.Name, m.Unit, m.Avg, m.Min, m.Max, m.P95, m.P99)
+	}
+
+	if len(s.Thresholds) > 0 {
+		b.WriteString("\n## Time over threshold\n\n")
+		b.WriteString("| Metric | Threshold | Time | Share |\n")
+		b.WriteString("|---|---:|---:|---:|\n")
+		for _, t := range s.Thresholds {
+			threshold := "-"
+			if t.Threshold > 0 {
+				threshold = fmt.Sprintf("%g", t.Threshold)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %.1f s | %.1f%% |\n", t.Metric, threshold, t.Seconds, t.Percent)
+		}
+	}
+
+	if len(s.TopProcesses) > 0 {
+		b.WriteString("\n## Top processes\n\n")
+		b.WriteString("| Process | Avg CPU % | Max CPU % | Max RAM MB | Max GPU % | Samples |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+		for _, p := range s.TopProcesses {
+			fmt.Fprintf(&b, "| %s | %.1f | %.1f | %d | %.1f | %d |\n",
+				p.Name, p.AvgCPUPercent, p.MaxCPUPercent, p.MaxMemoryMB, p.MaxGPUPercent, p.Samples)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
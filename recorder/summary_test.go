@@ -0,0 +1,140 @@
+package recorder
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NaveLIL/erez-monitor/config"
+	"github.com/NaveLIL/erez-monitor/models"
+)
+
+// sessionSnapshots returns n one-second snapshots with CPU usage 1..n %.
+func sessionSnapshots(n int) []*models.Metrics {
+	start := time.Date(2024, 3, 1, 20, 0, 0, 0, time.Local)
+	snapshots := make([]*models.Metrics, n)
+	for i := range snapshots {
+		m := &models.Metrics{Timestamp: start.Add(time.Duration(i) * time.Second), IntervalMs: 1000}
+		m.CPU.UsagePercent = float64(i + 1)
+		m.Memory.UsedPercent = 50
+		snapshots[i] = m
+	}
+	return snapshots
+}
+
+func findMetric(s *Summary, name string) *MetricSummary {
+	for i := range s.Metrics {
+		if s.Metrics[i].Name == name {
+			return &s.Metrics[i]
+		}
+	}
+	return nil
+}
+
+func TestSummarizeMetrics(t *testing.T) {
+	snapshots := sessionSnapshots(100)
+	// The last sample covers nine seconds (e.g. adaptive sampling)
+	snapshots[99].IntervalMs = 10000
+
+	summary := Summarize("run", snapshots, nil)
+	if summary.Samples != 100 || summary.DurationSec != 109 {
+		t.Fatalf("Unexpected summary: %d samples, %.1f s", summary.Samples, summary.DurationSec)
+	}
+
+	cpu := findMetric(summary, "CPU usage")
+	if cpu == nil {
+		t.Fatal("Expected a CPU usage summary")
+	}
+	// (sum 1..99 + 100*10) / 109
+	wantAvg := (4950.0 + 1000) / 109
+	if math.Abs(cpu.Avg-wantAvg) > 1e-9 || cpu.Min != 1 || cpu.Max != 100 || cpu.P95 != 95 || cpu.P99 != 99 {
+		t.Errorf("Unexpected CPU summary: %+v", cpu)
+	}
+
+	// Metrics without values are left out
+	if findMetric(summary, "GPU usage") != nil || findMetric(summary, "CPU temperature") != nil {
+		t.Errorf("Expected no GPU or temperature summary: %+v", summary.Metrics)
+	}
+}
+
+func TestSummarizeThresholds(t *testing.T) {
+	snapshots := sessionSnapshots(10)
+	for _, m := range snapshots[6:] {
+		m.Throttling.Throttled = true
+	}
+	alerts := &config.AlertsConfig{CPUThreshold: 8, RAMThreshold: 90}
+
+	summary := Summarize("run", snapshots, alerts)
+	want := map[string]float64{"Throttling": 4, "CPU usage": 2, "RAM usage": 0}
+	if len(summary.Thresholds) != len(want) {
+		t.Fatalf("Unexpected thresholds: %+v", summary.Thresholds)
+	}
+	for _, ts := range summary.Thresholds {
+		if ts.Seconds != want[ts.Metric] || ts.Percent != want[ts.Metric]*10 {
+			t.Errorf("Unexpected time over threshold: %+v", ts)
+		}
+	}
+}
+
+func TestSummarizeProcesses(t *testing.T) {
+	snapshots := sessionSnapshots(4)
+	for i, m := range snapshots {
+		m.TopProcesses = []models.ProcessInfo{
+			{Name: "game.exe", CPUPercent: 40, MemoryMB: uint64(1000 + i)},
+			// Two processes with the same name are combined
+			{Name: "chrome.exe", CPUPercent: 5, MemoryMB: 200},
+			{Name: "chrome.exe", CPUPercent: 5, MemoryMB: 300},
+		}
+		if i == 0 {
+			m.TopProcesses = append(m.TopProcesses, models.ProcessInfo{Name: "updater.exe", CPUPercent: 80})
+		}
+	}
+	snapshots[2].TopGPUProcesses = []models.ProcessInfo{{Name: "game.exe", GPUPercent: 97}}
+
+	procs := Summarize("run", snapshots, nil).TopProcesses
+	if len(procs) != 3 {
+		t.Fatalf("Expected 3 processes, got %+v", procs)
+	}
+	game, updater, chrome := procs[0], procs[1], procs[2]
+	if game.Name != "game.exe" || game.AvgCPUPercent != 40 || game.MaxMemoryMB != 1003 || game.MaxGPUPercent != 97 || game.Samples != 4 {
+		t.Errorf("Unexpected game summary: %+v", game)
+	}
+	if updater.Name != "updater.exe" || updater.AvgCPUPercent != 20 || updater.MaxCPUPercent != 80 || updater.Samples != 1 {
+		t.Errorf("Unexpected updater summary: %+v", updater)
+	}
+	if chrome.Name != "chrome.exe" || chrome.MaxCPUPercent != 10 || chrome.MaxMemoryMB != 300 {
+		t.Errorf("Unexpected chrome summary: %+v", chrome)
+	}
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	summary := Summarize("empty", nil, nil)
+	if summary.Samples != 0 || summary.Metrics != nil {
+		t.Errorf("Expected an empty summary, got %+v", summary)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	snapshots := sessionSnapshots(10)
+	summary := Summarize("driver 551.86", snapshots, &config.AlertsConfig{CPUThreshold: 5})
+	summary.System = &models.SystemInfo{Hostname: "rig", CPUModel: "Ryzen 7", CPUCores: 8, CPUThreads: 16}
+
+	var buf bytes.Buffer
+	if err := summary.WriteMarkdown(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# Benchmark: driver 551.86",
+		"- Duration: 10s",
+		"Ryzen 7 (8 cores / 16 threads)",
+		"| CPU usage | % | 5.5 | 1.0 | 10.0 | 10.0 | 10.0 |",
+		"| CPU usage | 5 | 5.0 s | 50.0% |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in report:\n%s", want, out)
+		}
+	}
+}
@@ -91,10 +91,72 @@ func (rb *RingBuffer) GetAll() []*models.Metrics {
 	return rb.GetLast(rb.count)
 }
 
+// GetWindow returns the snapshots taken within d of the most recent one, in
+// chronological order. Unlike GetLast, the result follows the actual sample
+// timestamps, so it is correct with a variable collection interval.
+func (rb *RingBuffer) GetWindow(d time.Duration) []*models.Metrics {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	if rb.count == 0 {
+		return nil
+	}
+
+	newest := rb.data[(rb.head-1+rb.capacity)%rb.capacity].Timestamp
+	cutoff := newest.Add(-d)
+
+	n := 0
+	for n < rb.count {
+		m := rb.data[(rb.head-1-n+2*rb.capacity)%rb.capacity]
+		if !m.Timestamp.After(cutoff) {
+			break
+		}
+		n++
+	}
+	if n == 0 {
+		n = 1
+	}
+
+	result := make([]*models.Metrics, n)
+	start := (rb.head - n + rb.capacity) % rb.capacity
+	for i := 0; i < n; i++ {
+		result[i] = rb.data[(start+i)%rb.capacity].Clone()
+	}
+	return result
+}
+
+// SampleWeights returns the weight of each snapshot for time-weighted
+// averages: the actual interval it covers. Snapshots without an interval
+// (the first sample) get the mean interval of the others.
+func SampleWeights(snapshots []*models.Metrics) []float64 {
+	var sum float64
+	var known int
+	for _, m := range snapshots {
+		if m.IntervalMs > 0 {
+			sum += m.IntervalMs
+			known++
+		}
+	}
+	fallback := 1.0
+	if known > 0 {
+		fallback = sum / float64(known)
+	}
+
+	weights := make([]float64, len(snapshots))
+	for i, m := range snapshots {
+		weights[i] = m.IntervalMs
+		if weights[i] <= 0 {
+			weights[i] = fallback
+		}
+	}
+	return weights
+}
+
 // GetAverage calculates average metrics over the last specified number of seconds.
+// Snapshots are selected by timestamp and weighted by the interval they cover.
 // Returns nil if no data is available.
 func (rb *RingBuffer) GetAverage(seconds int) *models.Metrics {
-	snapshots := rb.GetLast(seconds)
+	snapshots := rb.GetWindow(time.Duration(seconds) * time.Second)
 	if len(snapshots) == 0 {
 		return nil
 	}
@@ -104,41 +166,44 @@ func (rb *RingBuffer) GetAverage(seconds int) *models.Metrics {
 	}
 
 	var (
+		totalWeight    float64
 		cpuSum         float64
-		memUsedSum     uint64
+		memUsedSum     float64
 		memPercentSum  float64
 		gpuSum         float64
-		gpuTempSum     uint32
+		gpuTempSum     float64
 		diskReadSum    float64
 		diskWriteSum   float64
 		netDownloadSum float64
 		netUploadSum   float64
 	)
 
-	for _, m := range snapshots {
-		cpuSum += m.CPU.UsagePercent
-		memUsedSum += m.Memory.UsedMB
-		memPercentSum += m.Memory.UsedPercent
-		gpuSum += m.GPU.UsagePercent
-		gpuTempSum += m.GPU.TemperatureC
-		diskReadSum += m.Disk.ReadMBps
-		diskWriteSum += m.Disk.WriteMBps
-		netDownloadSum += m.Network.DownloadKBps
-		netUploadSum += m.Network.UploadKBps
-	}
-
-	n := float64(len(snapshots))
-	avg.CPU.UsagePercent = cpuSum / n
-	avg.Memory.UsedMB = memUsedSum / uint64(len(snapshots))
-	avg.Memory.UsedPercent = memPercentSum / n
+	weights := SampleWeights(snapshots)
+	for i, m := range snapshots {
+		w := weights[i]
+		totalWeight += w
+		cpuSum += m.CPU.UsagePercent * w
+		memUsedSum += float64(m.Memory.UsedMB) * w
+		memPercentSum += m.Memory.UsedPercent * w
+		gpuSum += m.GPU.UsagePercent * w
+		gpuTempSum += float64(m.GPU.TemperatureC) * w
+		diskReadSum += m.Disk.ReadMBps * w
+		diskWriteSum += m.Disk.WriteMBps * w
+		netDownloadSum += m.Network.DownloadKBps * w
+		netUploadSum += m.Network.UploadKBps * w
+	}
+
+	avg.CPU.UsagePercent = cpuSum / totalWeight
+	avg.Memory.UsedMB = uint64(memUsedSum / totalWeight)
+	avg.Memory.UsedPercent = memPercentSum / totalWeight
 	avg.Memory.TotalMB = snapshots[len(snapshots)-1].Memory.TotalMB
-	avg.GPU.UsagePercent = gpuSum / n
-	avg.GPU.TemperatureC = gpuTempSum / uint32(len(snapshots))
+	avg.GPU.UsagePercent = gpuSum / totalWeight
+	avg.GPU.TemperatureC = uint32(gpuTempSum / totalWeight)
 	avg.GPU.Available = snapshots[len(snapshots)-1].GPU.Available
-	avg.Disk.ReadMBps = diskReadSum / n
-	avg.Disk.WriteMBps = diskWriteSum / n
-	avg.Network.DownloadKBps = netDownloadSum / n
-	avg.Network.UploadKBps = netUploadSum / n
+	avg.Disk.ReadMBps = diskReadSum / totalWeight
+	avg.Disk.WriteMBps = diskWriteSum / totalWeight
+	avg.Network.DownloadKBps = netDownloadSum / totalWeight
+	avg.Network.UploadKBps = netUploadSum / totalWeight
 
 	return avg
 }
@@ -154,7 +219,7 @@ func (rb *RingBuffer) GetAverageByDuration(duration time.Duration) *models.Metri
 
 // GetMinMax returns the minimum and maximum values for key metrics over the last n seconds.
 func (rb *RingBuffer) GetMinMax(seconds int) (min, max *models.Metrics) {
-	snapshots := rb.GetLast(seconds)
+	snapshots := rb.GetWindow(time.Duration(seconds) * time.Second)
 	if len(snapshots) == 0 {
 		return nil, nil
 	}
@@ -205,6 +270,42 @@ func (rb *RingBuffer) GetMinMax(seconds int) (min, max *models.Metrics) {
 	return min, max
 }
 
+// GetWatchedHistory returns the history of a watched process over the last n
+// snapshots in chronological order. Snapshots without the watchlist entry
+// (e.g. taken before it was configured) are skipped.
+func (rb *RingBuffer) GetWatchedHistory(id string, n int) []models.WatchedProcessPoint {
+	rb.mu.RLock()
+	defer rb.mu.RUnlock()
+
+	if n <= 0 || rb.count == 0 {
+		return nil
+	}
+
+	if n > rb.count {
+		n = rb.count
+	}
+
+	start := (rb.head - n + rb.capacity) % rb.capacity
+	points := make([]models.WatchedProcessPoint, 0, n)
+
+	for i := 0; i < n; i++ {
+		m := rb.data[(start+i)%rb.capacity]
+		for _, w := range m.WatchedProcesses {
+			if w.ID == id {
+				points = append(points, models.WatchedProcessPoint{
+					Timestamp:  m.Timestamp,
+					Running:    w.Running,
+					CPUPercent: w.CPUPercent,
+					MemoryMB:   w.MemoryMB,
+				})
+				break
+			}
+		}
+	}
+
+	return points
+}
+
 // Clear removes all entries from the buffer.
 func (rb *RingBuffer) Clear() {
 	rb.mu.Lock()
@@ -135,9 +135,12 @@ func TestGetAll(t *testing.T) {
 func TestGetAverage(t *testing.T) {
 	rb := NewRingBuffer(10)
 
-	// Add 5 elements: CPU 10, 20, 30, 40, 50 (average = 30)
+	// Add 5 elements one second apart: CPU 10, 20, 30, 40, 50 (average = 30)
+	base := time.Now()
 	for i := 1; i <= 5; i++ {
-		rb.Add(createTestMetrics(float64(i*10), 50.0))
+		m := createTestMetrics(float64(i*10), 50.0)
+		m.Timestamp = base.Add(time.Duration(i) * time.Second)
+		rb.Add(m)
 	}
 
 	avg := rb.GetAverage(5)
@@ -171,13 +174,69 @@ func TestGetAverageByDuration(t *testing.T) {
 	}
 }
 
+func TestGetAverageVariableInterval(t *testing.T) {
+	rb := NewRingBuffer(20)
+
+	// 1 s at 10% sampled every 250 ms, then 4 s at 90% sampled once
+	base := time.Now()
+	for i := 0; i < 4; i++ {
+		m := createTestMetrics(10.0, 50.0)
+		m.Timestamp = base.Add(time.Duration(i+1) * 250 * time.Millisecond)
+		m.IntervalMs = 250
+		rb.Add(m)
+	}
+	m := createTestMetrics(90.0, 50.0)
+	m.Timestamp = base.Add(5 * time.Second)
+	m.IntervalMs = 4000
+	rb.Add(m)
+
+	avg := rb.GetAverage(10)
+	if avg == nil {
+		t.Fatal("Expected non-nil average")
+	}
+	// Time-weighted: (10*1 + 90*4) / 5 = 74, not the per-sample mean of 26
+	if avg.CPU.UsagePercent != 74.0 {
+		t.Errorf("Expected time-weighted average CPU 74, got %f", avg.CPU.UsagePercent)
+	}
+}
+
+func TestGetWindow(t *testing.T) {
+	rb := NewRingBuffer(10)
+
+	base := time.Now()
+	offsets := []time.Duration{0, 500 * time.Millisecond, 5 * time.Second, 5500 * time.Millisecond, 6 * time.Second}
+	for i, offset := range offsets {
+		m := createTestMetrics(float64(i), 50.0)
+		m.Timestamp = base.Add(offset)
+		rb.Add(m)
+	}
+
+	window := rb.GetWindow(time.Second)
+	if len(window) != 2 || window[0].CPU.UsagePercent != 3 || window[1].CPU.UsagePercent != 4 {
+		t.Errorf("Expected the last 2 snapshots, got %d", len(window))
+	}
+	if window := rb.GetWindow(time.Minute); len(window) != 5 {
+		t.Errorf("Expected all 5 snapshots, got %d", len(window))
+	}
+	// The latest snapshot is always included
+	if window := rb.GetWindow(0); len(window) != 1 {
+		t.Errorf("Expected 1 snapshot for an empty window, got %d", len(window))
+	}
+	if window := NewRingBuffer(5).GetWindow(time.Minute); window != nil {
+		t.Errorf("Expected nil for empty buffer, got %v", window)
+	}
+}
+
 func TestGetMinMax(t *testing.T) {
 	rb := NewRingBuffer(10)
 
 	// Add elements with CPU: 20, 50, 30, 80, 10
 	cpuValues := []float64{20, 50, 30, 80, 10}
-	for _, cpu := range cpuValues {
-		rb.Add(createTestMetrics(cpu, 50.0))
+	base := time.Now()
+	for i, cpu := range cpuValues {
+		m := createTestMetrics(cpu, 50.0)
+		m.Timestamp = base.Add(time.Duration(i) * time.Second)
+		rb.Add(m)
 	}
 
 	min, max := rb.GetMinMax(5)
@@ -300,6 +359,40 @@ func TestClone(t *testing.T) {
 	}
 }
 
+func TestGetWatchedHistory(t *testing.T) {
+	rb := NewRingBuffer(5)
+
+	// Snapshot before the watchlist entry existed
+	rb.Add(createTestMetrics(10.0, 50.0))
+
+	for i := 1; i <= 3; i++ {
+		m := createTestMetrics(10.0, 50.0)
+		m.WatchedProcesses = []models.WatchedProcessInfo{
+			{ID: "obs", Running: false},
+			{ID: "game", Running: i != 2, CPUPercent: float64(i * 10), MemoryMB: uint64(i * 100), PIDs: []int32{42}},
+		}
+		rb.Add(m)
+	}
+
+	points := rb.GetWatchedHistory("game", 10)
+	if len(points) != 3 {
+		t.Fatalf("Expected 3 points, got %d", len(points))
+	}
+	if points[0].CPUPercent != 10 || points[2].MemoryMB != 300 {
+		t.Errorf("Unexpected points order or values: %+v", points)
+	}
+	if points[1].Running {
+		t.Error("Expected second point to be not running")
+	}
+
+	if points := rb.GetWatchedHistory("game", 2); len(points) != 2 || points[1].CPUPercent != 30 {
+		t.Errorf("Expected last 2 points, got %+v", points)
+	}
+	if points := rb.GetWatchedHistory("missing", 10); len(points) != 0 {
+		t.Errorf("Expected no points for unknown id, got %d", len(points))
+	}
+}
+
 func BenchmarkAdd(b *testing.B) {
 	rb := NewRingBuffer(60)
 	m := createTestMetrics(50.0, 60.0)
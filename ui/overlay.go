@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"sync/atomic"
 	"syscall"
+	"time"
 	"unsafe"
 
 	"github.com/NaveLIL/erez-monitor/collector"
@@ -411,6 +412,8 @@ type historyData struct {
 	index  int
 	count  int
 	ticker int // counts animation frames to add new sample
+	// resumedAt is the last resume event seen; the sparklines restart after it
+	resumedAt time.Time
 }
 
 // Global instance - ONLY used from UI thread in WndProc
@@ -857,6 +860,12 @@ func (o *Overlay) updateAnimation() {
 	var targetCPU, targetRAM, targetGPU float64
 	if o.collector != nil {
 		if metrics := o.collector.GetLatest(); metrics != nil {
+			// Don't draw a continuous line across a sleep gap
+			if e := metrics.Event; e != nil && !e.Timestamp.Equal(o.history.resumedAt) {
+				o.history.resumedAt = e.Timestamp
+				o.history.index = 0
+				o.history.count = 0
+			}
 			targetCPU = metrics.CPU.UsagePercent
 			targetRAM = metrics.Memory.UsedPercent
 			if metrics.GPU.Available {
@@ -953,6 +962,13 @@ func (o *Overlay) paint(hwnd uintptr) {
 		// CPU
 		if o.config.ShowCPU {
 			o.drawMetricRowAnimated(hdc, "CPU", o.anim.cpuPercent, o.anim.cpuCritical, pulseMultiplier, y, labelX, barX, barWidth, barHeight, valueX)
+			if metrics.CPU.PackagePowerWatts > 0 {
+				procSelectObject.Call(hdc, o.fontSmall)
+				procSetTextColor.Call(hdc, COLOR_TEXT_GRAY)
+				powerText := fmt.Sprintf("%.0fW  Σ %.0fW", metrics.CPU.PackagePowerWatts, metrics.EstimatedPowerWatts())
+				o.drawText(hdc, powerText, barX, y+12)
+				y += 4
+			}
 			y += rowHeight
 		}
 
@@ -983,6 +999,17 @@ func (o *Overlay) paint(hwnd uintptr) {
 			y += rowHeight + 4
 		}
 
+		// Bottleneck, only while something is limiting
+		if b := metrics.Bottleneck; b.Class != "" && b.Class != models.BottleneckBalanced {
+			procSelectObject.Call(hdc, o.fontSmall)
+			procSetTextColor.Call(hdc, COLOR_TEXT_GRAY)
+			o.drawText(hdc, "LIMIT", labelX, y)
+			procSetTextColor.Call(hdc, COLOR_ORANGE)
+			limitText := fmt.Sprintf("%s %.0f%%", b.Class.Label(), b.Confidence*100)
+			o.drawText(hdc, limitText, barX, y)
+			y += 18
+		}
+
 		// Separator
 		if o.config.ShowNet || o.config.ShowDisk {
 			y += 2
@@ -1017,7 +1044,17 @@ func (o *Overlay) paint(hwnd uintptr) {
 			o.drawText(hdc, ulText, barX+85, y)
 			y += 18
 
-			if metrics.Network.PingMs > 0 {
+			// Game server latency takes the ping row while a game is connected
+			if gs := metrics.Network.GameServer; gs != nil && metrics.Network.GameServerMs > 0 {
+				procSetTextColor.Call(hdc, COLOR_TEXT_GRAY)
+				o.drawText(hdc, "GAME", labelX, y)
+				procSetTextColor.Call(hdc, getPingColor(metrics.Network.GameServerMs))
+				gameText := fmt.Sprintf("%.0f ms", metrics.Network.GameServerMs)
+				o.drawText(hdc, gameText, barX, y)
+				procSetTextColor.Call(hdc, COLOR_TEXT_GRAY)
+				o.drawText(hdc, gs.Address, barX+55, y)
+				y += 18
+			} else if metrics.Network.PingMs > 0 {
 				procSetTextColor.Call(hdc, COLOR_TEXT_GRAY)
 				o.drawText(hdc, "PING", labelX, y)
 				procSetTextColor.Call(hdc, getPingColor(metrics.Network.PingMs))
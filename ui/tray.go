@@ -37,6 +37,8 @@ type TrayUI struct {
 	mMoveOverlay   *systray.MenuItem
 	mSettings      *systray.MenuItem
 	mExportLogs    *systray.MenuItem
+	mBenchmark     *systray.MenuItem
+	mFlightRecord  *systray.MenuItem
 	mAutostart     *systray.MenuItem
 	mQuit          *systray.MenuItem
 
@@ -46,7 +48,9 @@ type TrayUI struct {
 	onMoveOverlay   func()
 	onSettings      func()
 	onExportLogs    func()
+	onFlightRecord  func()
 	onAutostart     func() bool // returns new state
+	onBenchmark     func() bool // returns whether a session is recording
 	onQuit          func()
 
 	// State
@@ -54,6 +58,7 @@ type TrayUI struct {
 	mu            sync.Mutex
 	running       bool
 	quitting      bool
+	benchmarking  bool
 
 	// Icons (embedded at build time or loaded)
 	iconGreen  []byte
@@ -73,13 +78,15 @@ func NewTrayUI(cfg *config.UIConfig, alertCfg *config.AlertsConfig, coll *collec
 }
 
 // SetCallbacks sets the callback functions for menu actions.
-func (t *TrayUI) SetCallbacks(onShowDetails, onToggleOverlay, onMoveOverlay, onSettings, onExportLogs, onQuit func(), onAutostart func() bool) {
+func (t *TrayUI) SetCallbacks(onShowDetails, onToggleOverlay, onMoveOverlay, onSettings, onExportLogs, onFlightRecord, onQuit func(), onAutostart, onBenchmark func() bool) {
 	t.onShowDetails = onShowDetails
 	t.onToggleOverlay = onToggleOverlay
 	t.onMoveOverlay = onMoveOverlay
 	t.onSettings = onSettings
 	t.onExportLogs = onExportLogs
+	t.onFlightRecord = onFlightRecord
 	t.onAutostart = onAutostart
+	t.onBenchmark = onBenchmark
 	t.onQuit = onQuit
 }
 
@@ -106,6 +113,10 @@ func (t *TrayUI) onReady() {
 	systray.AddSeparator()
 	t.mSettings = systray.AddMenuItem("Settings", "Open settings")
 	t.mExportLogs = systray.AddMenuItem("Export Logs", "Export metrics to CSV")
+	t.mu.Lock()
+	t.mBenchmark = systray.AddMenuItem(benchmarkTitle(t.benchmarking), "Record a benchmark session with a summary report")
+	t.mu.Unlock()
+	t.mFlightRecord = systray.AddMenuItem("Save Flight Recording", "Save the recent history and the next seconds to a file")
 	t.mAutostart = systray.AddMenuItemCheckbox("Start with Windows", "Start automatically when Windows starts", t.config.Autostart)
 	systray.AddSeparator()
 	t.mQuit = systray.AddMenuItem("Exit", "Exit EREZMonitor")
@@ -156,6 +167,18 @@ func (t *TrayUI) handleMenuEvents() {
 				go t.onExportLogs()
 			}
 
+		case <-t.mFlightRecord.ClickedCh:
+			if t.onFlightRecord != nil {
+				go t.onFlightRecord()
+			}
+
+		case <-t.mBenchmark.ClickedCh:
+			if t.onBenchmark != nil {
+				go func() {
+					t.SetBenchmarkActive(t.onBenchmark())
+				}()
+			}
+
 		case <-t.mAutostart.ClickedCh:
 			if t.onAutostart != nil {
 				go func() {
@@ -247,6 +270,15 @@ func (t *TrayUI) formatTooltip(m *models.Metrics) string {
 	tooltip += fmt.Sprintf("\n───────────\n↓ %.1f KB/s | ↑ %.1f KB/s",
 		m.Network.DownloadKBps, m.Network.UploadKBps)
 
+	// Add battery info
+	if m.Power.Available {
+		tooltip += fmt.Sprintf("\nBattery: %.0f%% (%s)", m.Power.BatteryPercent, m.Power.Status)
+		if m.Power.OnBattery() && m.Power.TimeToEmptyMin > 0 {
+			minutes := int(m.Power.TimeToEmptyMin)
+			tooltip += fmt.Sprintf(" %dh %02dm", minutes/60, minutes%60)
+		}
+	}
+
 	return tooltip
 }
 
@@ -278,6 +310,26 @@ func (t *TrayUI) toggleAutostart() {
 	// The actual registry modification should be done by the autostart module
 }
 
+// SetBenchmarkActive updates the benchmark menu item when a session is
+// started or stopped (e.g. by hotkey).
+func (t *TrayUI) SetBenchmarkActive(active bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.benchmarking = active
+	if t.mBenchmark != nil {
+		t.mBenchmark.SetTitle(benchmarkTitle(active))
+	}
+}
+
+// benchmarkTitle returns the benchmark menu item title.
+func benchmarkTitle(active bool) string {
+	if active {
+		return "Stop Benchmark"
+	}
+	return "Start Benchmark"
+}
+
 // ShowNotification shows a balloon notification.
 func (t *TrayUI) ShowNotification(title, message string) {
 	// Note: systray doesn't support balloon notifications directly